@@ -0,0 +1,294 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// plctl is a small standalone client for the portlayer API. It talks
+// directly to the portlayer server, bypassing the docker personality,
+// which makes it useful for debugging a VCH from inside the appliance
+// or over an SSH tunnel when the docker API isn't available or is the
+// thing under suspicion.
+//
+// plctl deliberately does not implement "container create" - assembling
+// a valid executor.ExecutorConfig by hand is exactly the job the docker
+// personality exists to do, and duplicating that here would just be a
+// second, more fragile copy of it. Everything downstream of a handle
+// (start, stop, inspect, list) is fair game.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/go-openapi/runtime"
+	rc "github.com/go-openapi/runtime/client"
+
+	apiclient "github.com/vmware/vic/lib/apiservers/portlayer/client"
+	"github.com/vmware/vic/lib/apiservers/portlayer/client/containers"
+	"github.com/vmware/vic/lib/apiservers/portlayer/client/scopes"
+	"github.com/vmware/vic/lib/apiservers/portlayer/client/storage"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/version"
+)
+
+// newPortLayerClient mirrors proxy.NewPortLayerClient - kept as a separate
+// copy rather than importing the engine proxy package, since plctl has no
+// other dependency on the docker personality and shouldn't gain one just
+// for this.
+func newPortLayerClient(addr string) *apiclient.PortLayer {
+	t := rc.New(addr, "/", []string{"http"})
+	t.Consumers["application/x-tar"] = runtime.ByteStreamConsumer()
+	t.Consumers["application/octet-stream"] = runtime.ByteStreamConsumer()
+	t.Producers["application/x-tar"] = runtime.ByteStreamProducer()
+	t.Producers["application/octet-stream"] = runtime.ByteStreamProducer()
+
+	return apiclient.New(t, nil)
+}
+
+func newOperation(action string) trace.Operation {
+	return trace.NewOperation(context.Background(), action)
+}
+
+func printJSON(v interface{}) error {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(buf))
+	return nil
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "plctl"
+	app.Usage = "debug client for the portlayer API"
+	app.Version = version.GetBuild().ShortVersion()
+
+	var addr string
+	var asJSON bool
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:        "host",
+			Value:       "localhost:2377",
+			Usage:       "address of the portlayer server",
+			Destination: &addr,
+		},
+		cli.BoolFlag{
+			Name:        "json",
+			Usage:       "print raw JSON instead of a table",
+			Destination: &asJSON,
+		},
+	}
+
+	app.Commands = []cli.Command{
+		{
+			Name:  "container",
+			Usage: "manage containers",
+			Subcommands: []cli.Command{
+				{
+					Name:  "ls",
+					Usage: "list containers",
+					Flags: []cli.Flag{
+						cli.BoolFlag{Name: "all, a", Usage: "show stopped containers too"},
+					},
+					Action: func(clic *cli.Context) error {
+						return containerList(newPortLayerClient(addr), clic.Bool("all"), asJSON)
+					},
+				},
+				{
+					Name:      "inspect",
+					Usage:     "show detailed information for a container",
+					ArgsUsage: "ID",
+					Action: func(clic *cli.Context) error {
+						id := clic.Args().First()
+						if id == "" {
+							return cli.NewExitError("container id is required", 1)
+						}
+						return containerInspect(newPortLayerClient(addr), id)
+					},
+				},
+				{
+					Name:      "start",
+					Usage:     "start a container",
+					ArgsUsage: "ID",
+					Action: func(clic *cli.Context) error {
+						id := clic.Args().First()
+						if id == "" {
+							return cli.NewExitError("container id is required", 1)
+						}
+						return containerSetState(newPortLayerClient(addr), id, "RUNNING")
+					},
+				},
+				{
+					Name:      "stop",
+					Usage:     "stop a container",
+					ArgsUsage: "ID",
+					Action: func(clic *cli.Context) error {
+						id := clic.Args().First()
+						if id == "" {
+							return cli.NewExitError("container id is required", 1)
+						}
+						return containerSetState(newPortLayerClient(addr), id, "STOPPED")
+					},
+				},
+			},
+		},
+		{
+			Name:  "scope",
+			Usage: "manage networking scopes",
+			Subcommands: []cli.Command{
+				{
+					Name:  "ls",
+					Usage: "list scopes",
+					Action: func(clic *cli.Context) error {
+						return scopeList(newPortLayerClient(addr), asJSON)
+					},
+				},
+			},
+		},
+		{
+			Name:  "volume",
+			Usage: "manage volumes",
+			Subcommands: []cli.Command{
+				{
+					Name:  "ls",
+					Usage: "list volumes",
+					Action: func(clic *cli.Context) error {
+						return volumeList(newPortLayerClient(addr), asJSON)
+					},
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func containerList(client *apiclient.PortLayer, all bool, asJSON bool) error {
+	op := newOperation("container.ls")
+
+	res, err := client.Containers.GetContainerList(containers.NewGetContainerListParamsWithContext(op).WithAll(&all))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(res.Payload)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 4, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATE\tIMAGE")
+	for _, info := range res.Payload {
+		var id, name, state, image string
+		if cc := info.ContainerConfig; cc != nil {
+			id = cc.ContainerID
+			state = cc.State
+			image = cc.ImageID
+			if len(cc.Names) > 0 {
+				name = cc.Names[0]
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id, name, state, image)
+	}
+	return w.Flush()
+}
+
+func containerInspect(client *apiclient.PortLayer, id string) error {
+	op := newOperation("container.inspect")
+
+	res, err := client.Containers.GetContainerInfo(containers.NewGetContainerInfoParamsWithContext(op).WithID(id))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(res.Payload)
+}
+
+// containerSetState replicates the handle -> StateChange -> Commit sequence
+// used by the docker personality's containerStart/containerStop (see
+// lib/apiservers/engine/backends/container.go), minus the bind/port-mapping
+// steps that only apply on create/start with network binding.
+func containerSetState(client *apiclient.PortLayer, id, state string) error {
+	op := newOperation("container.setstate")
+	opID := op.ID()
+
+	handleRes, err := client.Containers.Get(containers.NewGetParamsWithContext(op).WithOpID(&opID).WithID(id))
+	if err != nil {
+		return err
+	}
+	handle := handleRes.Payload
+
+	stateRes, err := client.Containers.StateChange(containers.NewStateChangeParamsWithContext(op).WithOpID(&opID).WithHandle(handle).WithState(state))
+	if err != nil {
+		return err
+	}
+	handle = stateRes.Payload
+
+	if _, err := client.Containers.Commit(containers.NewCommitParamsWithContext(op).WithOpID(&opID).WithHandle(handle)); err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+	return nil
+}
+
+func scopeList(client *apiclient.PortLayer, asJSON bool) error {
+	op := newOperation("scope.ls")
+	opID := op.ID()
+
+	res, err := client.Scopes.List(scopes.NewListParamsWithContext(op).WithOpID(&opID).WithIDName("default"))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(res.Payload)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 4, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tTYPE\tSUBNET\tGATEWAY")
+	for _, s := range res.Payload {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.ID, s.ScopeType, s.Subnet, s.Gateway)
+	}
+	return w.Flush()
+}
+
+func volumeList(client *apiclient.PortLayer, asJSON bool) error {
+	op := newOperation("volume.ls")
+	opID := op.ID()
+
+	filter := ""
+	res, err := client.Storage.ListVolumes(storage.NewListVolumesParamsWithContext(op).WithOpID(&opID).WithFilterString(&filter))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(res.Payload)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 4, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDRIVER\tSTORE")
+	for _, v := range res.Payload {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, v.Driver, v.Store)
+	}
+	return w.Flush()
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -69,6 +70,24 @@ func init() {
 	}
 }
 
+// acquireSingleInstanceLock takes an exclusive, non-blocking flock on path, creating it if
+// necessary, and holds it for the life of this process. It returns an error if another
+// process already holds the lock.
+func acquireSingleInstanceLock(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return fmt.Errorf("another port layer instance already holds %s: %s", path, err)
+	}
+
+	// deliberately leaked - the lock is released when this process exits
+	return nil
+}
+
 func main() {
 
 	if _, err := parser.Parse(); err != nil {
@@ -79,6 +98,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Only one port layer instance should ever be active against a given appliance's
+	// inventory at a time - a second one would race the first for the vSphere session and
+	// container cache. Fail fast rather than let both run against the same VCH.
+	if err := acquireSingleInstanceLock(constants.PortlayerLockPath); err != nil {
+		log.Fatalf("Unable to start port layer: %s", err)
+	}
+
 	// load the vch config
 	src, err := extraconfig.GuestInfoSource()
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os/exec"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +27,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/lib/migration/feature"
 	"github.com/vmware/vic/lib/tether"
 	"github.com/vmware/vic/lib/tether/msgs"
@@ -534,6 +536,31 @@ func (t *attachServerSSH) run() error {
 	return nil
 }
 
+// createSession converts a SessionCreateMsg into a session and asks the running tether to
+// launch it immediately, skipping the usual ExtraConfig/guestinfo reconfigure round-trip.
+func (t *attachServerSSH) createSession(msg *msgs.SessionCreateMsg) error {
+	defer trace.End(trace.Begin(msg.ID))
+
+	session := &tether.SessionConfig{
+		Common: executor.Common{
+			ID: msg.ID,
+		},
+		Cmd: exec.Cmd{
+			Path: msg.Path,
+			Args: append([]string{msg.Path}, msg.Args...),
+			Dir:  msg.Dir,
+			Env:  msg.Env,
+		},
+		Tty:       msg.Tty,
+		Attach:    msg.Attach,
+		OpenStdin: msg.OpenStdin,
+		User:      msg.User,
+		Group:     msg.Group,
+	}
+
+	return tthr.AddSession(session)
+}
+
 func (t *attachServerSSH) sessions(all bool) []string {
 	defer trace.End(trace.Begin(""))
 
@@ -588,6 +615,18 @@ func (t *attachServerSSH) globalMux(in <-chan *ssh.Request, cleanup func()) {
 				Version: feature.MaxPluginVersion - 1,
 			}
 			payload = msg.Marshal()
+		case msgs.SessionCreateReq:
+			msg := msgs.SessionCreateMsg{}
+			if err := msg.Unmarshal(req.Payload); err != nil {
+				ok = false
+				payload = []byte(err.Error())
+				break
+			}
+
+			if err := t.createSession(&msg); err != nil {
+				ok = false
+				payload = []byte(err.Error())
+			}
 		default:
 			ok = false
 			payload = []byte("unknown global request type: " + req.Type)
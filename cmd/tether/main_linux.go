@@ -76,6 +76,12 @@ func main() {
 
 	// TODO: hard code executor initialization status reporting via guestinfo here
 	sshserver := NewAttachServerSSH()
+
+	if prefix, err := extraconfig.GuestInfoPrefixOverride(); err == nil {
+		log.Infof("Using guestinfo prefix override %q", prefix)
+		extraconfig.SetPrefix(prefix)
+	}
+
 	src, err := extraconfig.GuestInfoSource()
 	if err != nil {
 		log.Error(err)
@@ -51,6 +51,13 @@ type operations struct {
 	tether.BaseOperations
 
 	logging bool
+
+	// persistentLog is the datastore-backed (ttyS2) log writer opened for the first session
+	// that logs persistently. Kept so that additional sessions (e.g. execs) can have their
+	// output merged into the same persisted stream, interleaved by timestamp, rather than
+	// having it silently discarded. Each session still gets its own DynamicMultiWriter so
+	// attach clients only see their own session's live output.
+	persistentLog *iolog.LogWriter
 }
 
 func (t *operations) Log() (io.Writer, error) {
@@ -85,10 +92,11 @@ func (t *operations) SessionLog(session *tether.SessionConfig) (dio.DynamicMulti
 	defer trace.End(trace.Begin("configure session log writer"))
 
 	if t.logging {
-		detail := "unable to log more than one session concurrently to persistent logging"
-		log.Warn(detail)
-		// use multi-writer so it's still viable for attach
-		return dio.MultiWriter(), dio.MultiWriter(), nil
+		// the datastore-backed serial port is a single device, so merge this session's
+		// output into the already open persistent log rather than discarding it. Each
+		// session keeps its own DynamicMultiWriter so attach clients don't cross-see output.
+		log.Warnf("merging session %s output into the existing persistent log", session.ID)
+		return dio.MultiWriter(t.persistentLog), dio.MultiWriter(t.persistentLog), nil
 	}
 
 	t.logging = true
@@ -117,6 +125,7 @@ func (t *operations) SessionLog(session *tether.SessionConfig) (dio.DynamicMulti
 	// wrap output in a LogWriter to serialize it into our persisted
 	// containerVM output format, using iolog.LogClock for timestamps
 	lw := iolog.NewLogWriter(f, iolog.LogClock{})
+	t.persistentLog = lw
 
 	// use multi-writer so it goes to both screen and session log
 	return dio.MultiWriter(lw, os.Stdout), dio.MultiWriter(lw, os.Stderr), nil
@@ -21,6 +21,22 @@ import (
 type ContainerConfig struct {
 	// NameConvention
 	ContainerNameConvention string `cmd:"container-name-convention"`
+	// GuestInfoPrefix overrides the default "vice." extraConfig/guestinfo key namespace
+	GuestInfoPrefix string `cmd:"guestinfo-prefix"`
+	// WebhookURL is the HTTP endpoint container lifecycle events are POSTed to
+	WebhookURL string `cmd:"webhook-url"`
+	// WebhookSecret signs webhook deliveries with HMAC-SHA256
+	WebhookSecret string `cmd:"webhook-secret"`
+	// AdmissionHookURL is consulted before every container create and may reject or mutate it
+	AdmissionHookURL string `cmd:"admission-hook-url"`
+	// DefaultNumCPUs is used for a container create that doesn't specify a vCPU count
+	DefaultNumCPUs int64 `cmd:"default-num-cpus"`
+	// DefaultMemoryMB is used for a container create that doesn't specify a memory size
+	DefaultMemoryMB int64 `cmd:"default-memory-mb"`
+	// MaxContainerNumCPUs caps the vCPU count a single container create may request
+	MaxContainerNumCPUs int64 `cmd:"max-container-num-cpus"`
+	// MaxContainerMemoryMB caps the memory size a single container create may request
+	MaxContainerMemoryMB int64 `cmd:"max-container-memory-mb"`
 }
 
 func (c *ContainerConfig) ContainerFlags() []cli.Flag {
@@ -29,13 +45,63 @@ func (c *ContainerConfig) ContainerFlags() []cli.Flag {
 		cli.StringFlag{
 			Name:        "container-name-convention, cnc",
 			Value:       "",
-			Usage:       "Provide a naming convention. Allows a token of '{name}' or '{id}', that will be replaced.",
+			Usage:       "Provide a naming convention. Allows a token of '{name}', '{id}' or '{vch}', that will be replaced.",
 			Destination: &c.ContainerNameConvention,
 			Hidden:      true,
 		},
+		cli.StringFlag{
+			Name:        "guestinfo-prefix",
+			Value:       "",
+			Usage:       "Override the default extraConfig/guestinfo key namespace ('vice.') used by this VCH and its containerVMs, to avoid collisions with other VIC versions or tooling sharing the same VMs",
+			Destination: &c.GuestInfoPrefix,
+			Hidden:      true,
+		},
+		cli.StringFlag{
+			Name:        "webhook-url",
+			Value:       "",
+			Usage:       "HTTP endpoint that container lifecycle events (create, start, stop, remove, etc) are POSTed to as they occur",
+			Destination: &c.WebhookURL,
+			Hidden:      true,
+		},
+		cli.StringFlag{
+			Name:        "webhook-secret",
+			Value:       "",
+			Usage:       "Secret used to sign webhook deliveries with HMAC-SHA256, carried in the X-VIC-Signature header",
+			Destination: &c.WebhookSecret,
+			Hidden:      true,
+		},
+		cli.StringFlag{
+			Name:        "admission-hook-url",
+			Value:       "",
+			Usage:       "HTTP endpoint consulted before every container create; it may reject the create or clamp its resource requests",
+			Destination: &c.AdmissionHookURL,
+			Hidden:      true,
+		},
+		cli.Int64Flag{
+			Name:        "default-num-cpus",
+			Usage:       "Number of vCPUs given to a container create that doesn't specify one",
+			Destination: &c.DefaultNumCPUs,
+			Hidden:      true,
+		},
+		cli.Int64Flag{
+			Name:        "default-memory-mb",
+			Usage:       "Memory (MB) given to a container create that doesn't specify one",
+			Destination: &c.DefaultMemoryMB,
+			Hidden:      true,
+		},
+		cli.Int64Flag{
+			Name:        "max-container-num-cpus",
+			Usage:       "Maximum vCPUs a single container create may request. 0 means unlimited",
+			Destination: &c.MaxContainerNumCPUs,
+			Hidden:      true,
+		},
+		cli.Int64Flag{
+			Name:        "max-container-memory-mb",
+			Usage:       "Maximum memory (MB) a single container create may request. 0 means unlimited",
+			Destination: &c.MaxContainerMemoryMB,
+			Hidden:      true,
+		},
 		// other container flags to to added"
-		// default container memory
-		// default container cpu
 		// default container network
 	}
 }
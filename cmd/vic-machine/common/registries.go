@@ -28,11 +28,17 @@ type Registries struct {
 	RegistryCAsArg         cli.StringSlice `arg:"registry-ca"`
 	InsecureRegistriesArg  cli.StringSlice `arg:"insecure-registry"`
 	WhitelistRegistriesArg cli.StringSlice `arg:"whitelist-registry"`
+	ContentTrustDigestsArg cli.StringSlice `arg:"content-trust-digest"`
 
 	RegistryCAs []byte
 
 	InsecureRegistries  []string `cmd:"insecure-registry"`
 	WhitelistRegistries []string `cmd:"whitelist-registry"`
+
+	// ContentTrustDigests, if non-empty, is the only set of image manifest digests
+	// this VCH will pull and write to its image store - any other digest is refused,
+	// so a container can never be created from an unvetted parent image.
+	ContentTrustDigests []string `cmd:"content-trust-digest"`
 }
 
 // Flags generates command line flags
@@ -78,5 +84,6 @@ func (r *Registries) ProcessRegistries(op trace.Operation) error {
 
 	r.InsecureRegistries = r.InsecureRegistriesArg.Value()
 	r.WhitelistRegistries = r.WhitelistRegistriesArg.Value()
+	r.ContentTrustDigests = r.ContentTrustDigestsArg.Value()
 	return nil
 }
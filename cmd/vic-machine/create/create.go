@@ -251,6 +251,13 @@ func (c *Create) Flags() []cli.Flag {
 			Value: &c.Registries.WhitelistRegistriesArg,
 			Usage: "Specify a list of permitted whitelist registry server addresses (insecure addresses still require the --insecure-registry option in addition)",
 		})
+	registries = append(registries,
+		cli.StringSliceFlag{
+			Name:   "content-trust-digest",
+			Value:  &c.Registries.ContentTrustDigestsArg,
+			Usage:  "Specify a list of trusted image manifest digests - if set, only these digests may be pulled and used to create containers",
+			Hidden: true,
+		})
 
 	util := []cli.Flag{
 		// miscellaneous
@@ -373,6 +380,7 @@ func (c *Create) ProcessParams(op trace.Operation) error {
 	c.InsecureRegistries = c.Registries.InsecureRegistries
 	c.WhitelistRegistries = c.Registries.WhitelistRegistries
 	c.RegistryCAs = c.Registries.RegistryCAs
+	c.ContentTrustDigests = c.Registries.ContentTrustDigests
 
 	hproxy, sproxy, nproxy, err := c.Proxies.ProcessProxies()
 	if err != nil {
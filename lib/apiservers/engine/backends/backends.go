@@ -348,9 +348,14 @@ func setPortMapping(op trace.Operation, info *models.ContainerInfo, backend *Con
 	if err != nil {
 		return err
 	}
+	natIP, err := resolveNatIP(container.Config.Labels)
+	if err != nil {
+		return err
+	}
+
 	for _, e := range endpointsOK.Payload {
 		if len(e.Ports) > 0 && !e.Direct {
-			if err = network.MapPorts(container, e, container.ContainerID); err != nil {
+			if err = network.MapPorts(container, e, container.ContainerID, natIP); err != nil {
 				log.Errorf(err.Error())
 				return err
 			}
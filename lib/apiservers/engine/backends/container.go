@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"path/filepath"
 	"strconv"
@@ -110,6 +111,19 @@ const (
 
 	// maximum elapsed time for retry
 	maxElapsedTime = 2 * time.Minute
+
+	// maximum number of times to ask namesgenerator for a new name before
+	// giving up on finding one that isn't already in use on this VCH
+	maxNameGenerationAttempts = 10
+
+	// ProjectLabelKey is the docker label used to attribute a container to a project/tenant for
+	// the purposes of admission-checking Config.ProjectQuotas.
+	ProjectLabelKey = "com.vmware.vic.project"
+
+	// NatIPLabelKey is the docker label used to pin a container's mapped ports to a specific
+	// external IP drawn from Config.NATIPPool, for users whose upstream services do IP-based
+	// allow-listing on outbound connections.
+	NatIPLabelKey = "com.vmware.vic.nat-ip"
 )
 
 // These are the constants used for the portlayer exec states checks returned when obtaining the state of a container handle
@@ -229,10 +243,13 @@ func (c *ContainerBackend) ContainerExecCreate(name string, config *types.ExecCo
 
 		switch state {
 		case StoppedState, CreatedState, SuspendedState:
-			return engerr.InternalServerError(fmt.Sprintf("container (%s) is not running", name))
+			// matches docker's own exec-on-non-running-container response: 409, not 500.
+			// This is a terminal state for the purposes of this exec attempt, so it's
+			// surfaced immediately rather than retried.
+			return engerr.ConflictError(fmt.Sprintf("Container %s is not running", name))
 		case StartingState:
-			// This is a transient state, returning conflict error to trigger a retry in the operation.
-			return engerr.ConflictError(fmt.Sprintf("container (%s) is still starting", id))
+			// This is a transient state, returning a transient conflict error to trigger a retry in the operation.
+			return engerr.NewTransientConflictError(fmt.Sprintf("container (%s) is still starting", id))
 		case RunningState:
 			// NO-OP - this is the state that allows an exec to occur.
 		default:
@@ -672,8 +689,19 @@ func (c *ContainerBackend) ContainerCreate(config types.ContainerCreateConfig) (
 	}
 	defer cache.ContainerCache().DecreaseContainersReservation()
 
-	log.Infof("** createconfig = %#v", config)
-	log.Infof("** container config = %#v", config.Config)
+
+	log.Infof("** createconfig = %#v", redactEnv(config))
+	log.Infof("** container config = %#v", redactContainerConfigEnv(config.Config))
+
+	// Config and its Image field are dereferenced below, before validateCreateConfig
+	// gets a chance to run, so check for them up front and return a 400 with a
+	// field-level message rather than panicking on a malformed request.
+	if config.Config == nil {
+		return containertypes.ContainerCreateCreatedBody{}, engerr.BadRequestError("missing field Config")
+	}
+	if config.Config.Image == "" {
+		return containertypes.ContainerCreateCreatedBody{}, engerr.BadRequestError("missing field Config.Image")
+	}
 
 	// get the image from the cache
 	image, err := cache.ImageCache().Get(config.Config.Image)
@@ -691,6 +719,10 @@ func (c *ContainerBackend) ContainerCreate(config types.ContainerCreateConfig) (
 		return containertypes.ContainerCreateCreatedBody{}, err
 	}
 
+	if err = checkProjectQuota(op, PortLayerClient(), config.Config.Labels[ProjectLabelKey], config.HostConfig.CPUCount, config.HostConfig.Memory); err != nil {
+		return containertypes.ContainerCreateCreatedBody{}, err
+	}
+
 	reserved, err := checkStorageQuota(&config)
 	if reserved > 0 {
 		defer cache.ContainerCache().RemoveStorageReservation(reserved)
@@ -714,16 +746,23 @@ func (c *ContainerBackend) ContainerCreate(config types.ContainerCreateConfig) (
 			return containertypes.ContainerCreateCreatedBody{}, derr.NewRequestConflictError(err)
 		}
 	} else {
-		for i := 0; i < 5; i++ {
+		for i := 0; i < maxNameGenerationAttempts; i++ {
+			// GetRandomName appends "N-" to the name once the collision counter
+			// is non-zero, so this also spreads generated names out on retry.
 			generated := randomName(i)
+			// ReserveName checks the container cache, which mirrors this VCH's
+			// container inventory, so a hit here means the name is truly free.
 			if cache.ContainerCache().ReserveName(container, generated) == nil {
 				config.Name = generated
 				break
 			}
+			log.Debugf("ContainerCreate: generated name %q collided with an existing container, retrying (%d/%d)",
+				generated, i+1, maxNameGenerationAttempts)
 		}
 
 		if config.Name == "" {
-			return containertypes.ContainerCreateCreatedBody{}, derr.NewRequestConflictError(errors.New("attempted random names conflicted with existing containers"))
+			return containertypes.ContainerCreateCreatedBody{}, derr.NewRequestConflictError(
+				errors.Errorf("unable to generate a unique container name after %d attempts against the VCH inventory", maxNameGenerationAttempts))
 		}
 	}
 
@@ -1141,7 +1180,12 @@ func (c *ContainerBackend) containerStart(op trace.Operation, name string, hostC
 	if bind {
 		scope, e := c.findPortBoundNetworkEndpoint(op, hostConfig, endpoints)
 		if scope != nil && scope.ScopeType == constants.BridgeScopeType {
-			if err = network.MapPorts(vc, e, id); err != nil {
+			natIP, natErr := resolveNatIP(vc.Config.Labels)
+			if natErr != nil {
+				return natErr
+			}
+
+			if err = network.MapPorts(vc, e, id, natIP); err != nil {
 				return engerr.InternalServerError(fmt.Sprintf("error mapping ports: %s", err))
 			}
 
@@ -1338,7 +1382,10 @@ func (c *ContainerBackend) ContainerChanges(name string) ([]docker.Change, error
 
 	r, err := c.GetContainerChanges(op, vc, false)
 	if err != nil {
-		return nil, engerr.InternalServerError(err.Error())
+		// GetContainerChanges already returns a properly typed engine error, e.g.
+		// ResourceLockedError when the delta disk can't be read while the
+		// container is running - don't flatten that into a 500.
+		return nil, err
 	}
 
 	changes := []docker.Change{}
@@ -1396,7 +1443,10 @@ func (c *ContainerBackend) GetContainerChanges(op trace.Operation, vc *viccontai
 
 	r, err := archiveProxy.ArchiveExportReader(op, constants.ContainerStoreName, host, vc.ContainerID, parent, data, spec)
 	if err != nil {
-		return nil, engerr.InternalServerError(err.Error())
+		// ArchiveExportReader already returns a properly typed engine error -
+		// propagate it as-is so callers like docker diff report the right
+		// status (e.g. 423 Locked) instead of a blanket 500.
+		return nil, err
 	}
 
 	return r, nil
@@ -1863,6 +1913,31 @@ func createInternalVicContainer(image *metadata.ImageConfig) (*viccontainer.VicC
 	return container, nil
 }
 
+// redactEnv returns a shallow copy of config with its Env values redacted, suitable
+// for logging. Env var names are kept since they're useful for debugging, but the
+// values often carry secrets that shouldn't end up in the personality server logs.
+func redactEnv(config types.ContainerCreateConfig) types.ContainerCreateConfig {
+	config.Config = redactContainerConfigEnv(config.Config)
+	return config
+}
+
+func redactContainerConfigEnv(config *containertypes.Config) *containertypes.Config {
+	if config == nil || len(config.Env) == 0 {
+		return config
+	}
+
+	redacted := *config
+	redacted.Env = make([]string, len(config.Env))
+	for i, kv := range config.Env {
+		if idx := strings.Index(kv, "="); idx >= 0 {
+			redacted.Env[i] = kv[:idx] + "=<redacted>"
+			continue
+		}
+		redacted.Env[i] = "<redacted>"
+	}
+	return &redacted
+}
+
 // SetConfigOptions is a place to add necessary container configuration
 // values that were not explicitly supplied by the user
 func setCreateConfigOptions(config, imageConfig *containertypes.Config) {
@@ -2062,6 +2137,21 @@ func validateCreateConfig(config *types.ContainerCreateConfig) error {
 		return derr.NewRequestNotFoundError(fmt.Errorf("No command specified"))
 	}
 
+	// VIC doesn't watch container exit codes and restart container VMs itself - that's left
+	// to vSphere HA restarting the VM if it disappears. Reject policies we can't honor rather
+	// than silently accepting them and leaving the user to discover the gap the hard way.
+	if name := config.HostConfig.RestartPolicy.Name; name != "" && name != "no" {
+		return engerr.BadRequestError(fmt.Sprintf("unsupported restart policy %q: VIC relies on vSphere HA to restart container VMs and does not support per-container restart policies", name))
+	}
+
+	// Every container VM already gets its output persisted to a datastore-backed log
+	// (equivalent to the "json-file" driver) regardless of what's asked for, since that's
+	// wired at the serial port level rather than per-container. Accept the drivers that
+	// describe what actually happens and reject the rest instead of silently ignoring them.
+	if driver := config.HostConfig.LogConfig.Type; driver != "" && driver != "json-file" && driver != "none" {
+		return engerr.APINotSupportedMsg(ProductName(), fmt.Sprintf("--log-driver %s", driver))
+	}
+
 	return nil
 }
 
@@ -2169,6 +2259,104 @@ func checkContainerCount(op context.Context, client *client.PortLayer) error {
 	return nil
 }
 
+// checkProjectQuota admission-checks a new container of the given size against the
+// Config.ProjectQuotas limit for its project, where project is the value of the container's
+// ProjectLabelKey label. Containers without that label, or projects with no configured quota,
+// are unrestricted.
+func checkProjectQuota(op context.Context, client *client.PortLayer, project string, cpuCount int64, memoryMB int64) error {
+	if project == "" || vchConfig.Cfg.ProjectQuotas == nil {
+		return nil
+	}
+
+	quota, ok := vchConfig.Cfg.ProjectQuotas[project]
+	if !ok {
+		return nil
+	}
+
+	usage, err := projectUsage(op, client, project)
+	if err != nil {
+		return fmt.Errorf("Error computing usage for project %q: %s", project, err)
+	}
+
+	if quota.MaxContainers > 0 && usage.Containers+1 > quota.MaxContainers {
+		return engerr.BadRequestError(fmt.Sprintf("project %q container count would exceed quota %d (currently %d)", project, quota.MaxContainers, usage.Containers))
+	}
+	if quota.MaxCPUs > 0 && usage.CPUs+cpuCount > quota.MaxCPUs {
+		return engerr.BadRequestError(fmt.Sprintf("project %q vCPU usage would exceed quota %d (currently %d, requesting %d)", project, quota.MaxCPUs, usage.CPUs, cpuCount))
+	}
+	if quota.MaxMemoryMB > 0 && usage.MemoryMB+memoryMB > quota.MaxMemoryMB {
+		return engerr.BadRequestError(fmt.Sprintf("project %q memory usage would exceed quota %dMB (currently %dMB, requesting %dMB)", project, quota.MaxMemoryMB, usage.MemoryMB, memoryMB))
+	}
+
+	return nil
+}
+
+// resolveNatIP returns the external IP a container's mapped ports should be pinned to, based on
+// its NatIPLabelKey label, or nil if the container didn't request pinning. It's an error to
+// request an IP that isn't in Config.NATIPPool, or to request pinning at all when the pool is
+// empty.
+func resolveNatIP(labels map[string]string) (net.IP, error) {
+	requested, ok := labels[NatIPLabelKey]
+	if !ok || requested == "" {
+		return nil, nil
+	}
+
+	natIP := net.ParseIP(requested)
+	if natIP == nil {
+		return nil, engerr.BadRequestError(fmt.Sprintf("%s label value %q is not a valid IP address", NatIPLabelKey, requested))
+	}
+
+	for _, allowed := range vchConfig.Cfg.NATIPPool {
+		if net.ParseIP(allowed).Equal(natIP) {
+			return natIP, nil
+		}
+	}
+
+	return nil, engerr.BadRequestError(fmt.Sprintf("%s label value %q is not in the configured NAT IP pool", NatIPLabelKey, requested))
+}
+
+// ProjectUsageInfo reports a project's current consumption against its quota, for surfacing
+// usage to callers (e.g. a future admin API or vic-machine inspect command).
+type ProjectUsageInfo struct {
+	Containers int
+	CPUs       int64
+	MemoryMB   int64
+}
+
+// projectUsage sums the containers, vCPUs and memory currently attributed to project across all
+// containers on this VCH, by decoding each container's docker labels annotation.
+func projectUsage(op context.Context, client *client.PortLayer, project string) (ProjectUsageInfo, error) {
+	var usage ProjectUsageInfo
+
+	all := true
+	containme, err := client.Containers.GetContainerList(containers.NewGetContainerListParamsWithContext(op).WithAll(&all))
+	if err != nil {
+		switch err := err.(type) {
+		case *containers.GetContainerListInternalServerError:
+			return usage, fmt.Errorf("Error invoking GetContainerList: %s", err.Payload.Message)
+		default:
+			return usage, fmt.Errorf("Error invoking GetContainerList: %s", err.Error())
+		}
+	}
+
+	for _, t := range containme.Payload {
+		var labels map[string]string
+		if err := convert.ContainerAnnotation(t.ContainerConfig.Annotations, convert.AnnotationKeyLabels, &labels); err != nil {
+			return usage, fmt.Errorf("unable to convert vic annotations to docker labels (%s)", t.ContainerConfig.ContainerID)
+		}
+
+		if labels[ProjectLabelKey] != project {
+			continue
+		}
+
+		usage.Containers++
+		usage.CPUs += int64(t.ContainerConfig.NumCPU)
+		usage.MemoryMB += t.ContainerConfig.MemorySizeMB
+	}
+
+	return usage, nil
+}
+
 // Reserve storage during parallel requests and rollback reservation if quota exceeds.
 func checkStorageQuota(config *types.ContainerCreateConfig) (int64, error) {
 	// 0 means unlimited
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/container"
 	dnetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/reference"
 	"github.com/docker/go-connections/nat"
 	"github.com/go-openapi/runtime"
@@ -118,6 +120,14 @@ type MockContainerProxy struct {
 	mockAddInteractionData []AddInteractionMockData
 	mockAddLoggingData     []AddLoggingMockData
 	mockCommitData         []CommitHandleMockData
+
+	// execState drives GetStateFromHandle for ContainerExecCreate tests; defaults
+	// to RunningState so tests that don't care about it can ignore it.
+	execState string
+	// execEIDs is popped from on each CreateExecTask call, so a test can hand out
+	// distinct exec IDs to concurrent callers.
+	execMu   sync.Mutex
+	execEIDs []string
 }
 
 type MockStorageProxy struct {
@@ -316,7 +326,16 @@ func (m *MockContainerProxy) BindInteraction(ctx context.Context, handle string,
 }
 
 func (m *MockContainerProxy) CreateExecTask(ctx context.Context, handle string, config *types.ExecConfig) (string, string, error) {
-	return "", "", nil
+	m.execMu.Lock()
+	defer m.execMu.Unlock()
+
+	if len(m.execEIDs) == 0 {
+		return handle, "", nil
+	}
+
+	eid := m.execEIDs[0]
+	m.execEIDs = m.execEIDs[1:]
+	return handle, eid, nil
 }
 
 func (m *MockContainerProxy) UnbindInteraction(ctx context.Context, handle string, name string, id string) (string, error) {
@@ -357,7 +376,11 @@ func (m *MockContainerProxy) State(ctx context.Context, vc *viccontainer.VicCont
 }
 
 func (m *MockContainerProxy) GetStateFromHandle(op trace.Operation, handle string) (string, string, error) {
-	return "", "", nil
+	if m.execState == "" {
+		return handle, RunningState, nil
+	}
+
+	return handle, m.execState, nil
 }
 
 func (m *MockContainerProxy) InspectTask(op trace.Operation, handle string, eid string, cid string) (*models.TaskInspectResponse, error) {
@@ -918,3 +941,71 @@ func TestCreateConfigNetworkMode(t *testing.T) {
 
 	assert.Contains(t, err.Error(), "NetworkMode error", "error (%s) should have 'NetworkMode error'", err.Error())
 }
+
+// TestContainerExecCreatePoweredOff verifies that exec against a stopped
+// container is rejected immediately with a 409, rather than the 500 it used
+// to return, and that it isn't silently retried as a transient failure.
+func TestContainerExecCreatePoweredOff(t *testing.T) {
+	mockContainerProxy := NewMockContainerProxy()
+	mockContainerProxy.execState = StoppedState
+
+	cb := &ContainerBackend{
+		containerProxy: mockContainerProxy,
+	}
+
+	vc := viccontainer.NewVicContainer()
+	vc.ContainerID = "execpoweredoff"
+	vc.Name = "execpoweredoff"
+	cache.ContainerCache().AddContainer(vc)
+
+	_, err := cb.ContainerExecCreate(vc.Name, &types.ExecConfig{Cmd: []string{"true"}})
+	if assert.Error(t, err) {
+		httpErr, ok := err.(interface{ HTTPErrorStatusCode() int })
+		if assert.True(t, ok, "expected an error with an HTTP status code, got %T", err) {
+			assert.Equal(t, http.StatusConflict, httpErr.HTTPErrorStatusCode())
+		}
+	}
+}
+
+// TestContainerExecCreateConcurrentSessions verifies that multiple concurrent
+// exec calls against a running container all succeed and are each handed a
+// distinct session ID.
+func TestContainerExecCreateConcurrentSessions(t *testing.T) {
+	const sessions = 5
+
+	mockContainerProxy := NewMockContainerProxy()
+	mockContainerProxy.execState = RunningState
+	for i := 0; i < sessions; i++ {
+		mockContainerProxy.execEIDs = append(mockContainerProxy.execEIDs, fmt.Sprintf("eid-%d", i))
+	}
+
+	cb := &ContainerBackend{
+		containerProxy: mockContainerProxy,
+	}
+	eventService = events.New()
+
+	vc := viccontainer.NewVicContainer()
+	vc.ContainerID = "execconcurrent"
+	vc.Name = "execconcurrent"
+	cache.ContainerCache().AddContainer(vc)
+
+	var wg sync.WaitGroup
+	eids := make([]string, sessions)
+	errs := make([]error, sessions)
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			eids[i], errs[i] = cb.ContainerExecCreate(vc.Name, &types.ExecConfig{Cmd: []string{"true"}})
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, sessions)
+	for i := 0; i < sessions; i++ {
+		assert.NoError(t, errs[i])
+		assert.NotEmpty(t, eids[i])
+		assert.False(t, seen[eids[i]], "exec session id %s was handed out more than once", eids[i])
+		seen[eids[i]] = true
+	}
+}
@@ -380,10 +380,11 @@ func (i *ImageBackend) PullImage(ctx context.Context, image, tag string, metaHea
 	//*****
 
 	options := imagec.Options{
-		Destination: os.TempDir(),
-		Reference:   ref,
-		Timeout:     imagec.DefaultHTTPTimeout,
-		Outstream:   outStream,
+		Destination:         os.TempDir(),
+		Reference:           ref,
+		Timeout:             imagec.DefaultHTTPTimeout,
+		Outstream:           outStream,
+		ContentTrustDigests: vchConfig.Cfg.ContentTrustDigests,
 	}
 
 	portLayerServer := PortLayerServer()
@@ -145,6 +145,24 @@ func (p *portMapper) UnmapPort(ip net.IP, port int, proto string, destPort int,
 	return p.forward(Unmap, ip, port, proto, "", destPort, srcIface, destIface)
 }
 
+// postroutingArgs builds the POSTROUTING rule that rewrites the source address of traffic
+// destined for a mapped port. With no egressIP it MASQUERADEs, picking whatever address the
+// outbound interface currently holds - the pre-existing behavior. With egressIP set (a
+// container pinned to a specific external IP from Config.NATIPPool), it SNATs to that address
+// instead, so the container's outbound side of the mapping is stable across interface changes.
+func postroutingArgs(proto, destAddr string, destPort int, egressIP string) []string {
+	args := []string{"POSTROUTING", "-t", string(iptables.Nat),
+		"-p", proto,
+		"-d", destAddr,
+		"--dport", strconv.Itoa(destPort)}
+
+	if egressIP == "" {
+		return append(args, "-j", "MASQUERADE")
+	}
+
+	return append(args, "-j", "SNAT", "--to-source", egressIP)
+}
+
 // iptablesRunAndCheck runs an iptables command with the provided args
 func iptablesRunAndCheck(action iptables.Action, args []string) error {
 	args = append([]string{string(action)}, args...)
@@ -251,11 +269,7 @@ func (p *portMapper) forward(op Operation, ip net.IP, port int, proto, destAddr
 		savedArgs = append(savedArgs, args)
 		p.bindings[key] = savedArgs
 
-		args = []string{"POSTROUTING", "-t", string(iptables.Nat),
-			"-p", proto,
-			"-d", destAddr,
-			"--dport", strconv.Itoa(destPort),
-			"-j", "MASQUERADE"}
+		args = postroutingArgs(proto, destAddr, destPort, ipStr)
 		if err := iptablesRunAndCheck(iptables.Append, args); err != nil {
 			return err
 		}
@@ -123,6 +123,27 @@ func ConflictError(msg string) error {
 	return derr.NewRequestConflictError(fmt.Errorf("Conflict error from portlayer: %s", msg))
 }
 
+// TransientConflictError is a 409 caused by a container sitting in a short-lived
+// transitional state (e.g. still starting) that's expected to clear on its own.
+// Unlike a plain ConflictError, IsLockTimeoutOrConflictError treats it as worth
+// retrying rather than surfacing immediately.
+type TransientConflictError struct {
+	msg string
+}
+
+func (e TransientConflictError) Error() string {
+	return e.msg
+}
+
+// HTTPErrorStatusCode implements httpStatusError so callers still see a 409.
+func (e TransientConflictError) HTTPErrorStatusCode() int {
+	return http.StatusConflict
+}
+
+func NewTransientConflictError(msg string) error {
+	return TransientConflictError{msg: msg}
+}
+
 func PluginNotFoundError(name string) error {
 	return derr.NewErrorWithStatusCode(fmt.Errorf("plugin %s not found", name), http.StatusNotFound)
 }
@@ -193,10 +214,14 @@ func NewLockTimeoutError(desc string) error {
 }
 
 func IsLockTimeoutOrConflictError(err error) bool {
-	// Is Error is due to Timeout or a Conflict return true
+	// Is Error is due to Timeout or a transient (retryable) Conflict, return true.
+	// A plain ConflictError is deliberately excluded - that signals a state the
+	// caller isn't expected to grow out of, so retrying it would just delay the
+	// error rather than resolve it.
 	if _, ok := err.(LockTimeoutError); ok {
 		return true
 	}
 
-	return IsConflictError(err)
+	_, ok := err.(TransientConflictError)
+	return ok
 }
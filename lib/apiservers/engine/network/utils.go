@@ -133,8 +133,10 @@ func unrollPortMap(portMap nat.PortMap) ([]*portMapping, error) {
 	return portMaps, nil
 }
 
-// MapPorts maps ports defined in bridge endpoint for containerID
-func MapPorts(vc *viccontainer.VicContainer, endpoint *models.EndpointConfig, containerID string) error {
+// MapPorts maps ports defined in bridge endpoint for containerID. natIP, if not nil, pins the
+// mapping to that external IP (see NatIPLabelKey in the engine backend) instead of the default
+// MASQUERADE behavior of picking whatever address the public interface currently holds.
+func MapPorts(vc *viccontainer.VicContainer, endpoint *models.EndpointConfig, containerID string, natIP net.IP) error {
 	if endpoint == nil {
 		return fmt.Errorf("invalid endpoint")
 	}
@@ -177,7 +179,7 @@ func MapPorts(vc *viccontainer.VicContainer, endpoint *models.EndpointConfig, co
 			continue
 		}
 
-		if err = portMapper.MapPort(nil, p.intHostPort, p.portProto.Proto(), containerIP.String(), p.portProto.Int(), publicIfaceName, bridgeIfaceName); err != nil {
+		if err = portMapper.MapPort(natIP, p.intHostPort, p.portProto.Proto(), containerIP.String(), p.portProto.Int(), publicIfaceName, bridgeIfaceName); err != nil {
 			log.Debugf(err.Error())
 			return err
 		}
@@ -1045,9 +1045,11 @@ func dockerContainerCreateParamsToTask(ctx context.Context, id string, cc types.
 	// user
 	config.User = cc.Config.User
 
-	// attach.  Always set to true otherwise we cannot attach later.
-	// this tells portlayer container is attachable.
-	config.Attach = true
+	// attach.  Derived from the create request rather than forced on, so a
+	// detached `docker run -d` isn't joined as if a client were about to attach.
+	// Tty=true with Attach=false is a valid combination - the guest allocates a
+	// tty for the process without anyone attached to it yet.
+	config.Attach = cc.Config.AttachStdin || cc.Config.AttachStdout || cc.Config.AttachStderr
 
 	// openstdin
 	config.OpenStdin = cc.Config.OpenStdin
@@ -1058,7 +1060,7 @@ func dockerContainerCreateParamsToTask(ctx context.Context, id string, cc types.
 	// container stop signal
 	config.StopSignal = cc.Config.StopSignal
 
-	log.Debugf("dockerContainerCreateParamsToTask = %+v", config)
+	log.Infof("dockerContainerCreateParamsToTask: id %s, tty %t, attach %t, openStdin %t", id, config.Tty, config.Attach, config.OpenStdin)
 
 	return tasks.NewJoinParamsWithContext(ctx).WithConfig(config)
 }
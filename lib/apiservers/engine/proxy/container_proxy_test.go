@@ -15,8 +15,11 @@
 package proxy
 
 import (
+	"context"
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 )
@@ -81,3 +84,37 @@ func TestPort(t *testing.T) {
 
 	}
 }
+
+func TestDockerContainerCreateParamsToTaskAttachFlags(t *testing.T) {
+	tests := []struct {
+		tty           bool
+		attachStdin   bool
+		attachStdio   bool
+		wantAttach    bool
+		wantOpenStdin bool
+	}{
+		{tty: false, attachStdin: false, attachStdio: false, wantAttach: false, wantOpenStdin: false},
+		{tty: false, attachStdin: false, attachStdio: true, wantAttach: true, wantOpenStdin: false},
+		{tty: true, attachStdin: false, attachStdio: false, wantAttach: false, wantOpenStdin: false},
+		{tty: true, attachStdin: true, attachStdio: true, wantAttach: true, wantOpenStdin: true},
+	}
+
+	for _, test := range tests {
+		cc := types.ContainerCreateConfig{
+			Config: &container.Config{
+				Cmd:          []string{"/bin/sh"},
+				Tty:          test.tty,
+				OpenStdin:    test.attachStdin,
+				AttachStdin:  test.attachStdin,
+				AttachStdout: test.attachStdio,
+				AttachStderr: test.attachStdio,
+			},
+		}
+
+		params := dockerContainerCreateParamsToTask(context.Background(), "cid", cc)
+
+		assert.Equal(t, test.tty, params.Config.Tty)
+		assert.Equal(t, test.wantAttach, params.Config.Attach)
+		assert.Equal(t, test.wantOpenStdin, params.Config.OpenStdin)
+	}
+}
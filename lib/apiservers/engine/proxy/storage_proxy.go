@@ -27,6 +27,7 @@ import (
 
 	derr "github.com/docker/docker/api/errors"
 	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/go-units"
 
 	viccontainer "github.com/vmware/vic/lib/apiservers/engine/backends/container"
@@ -241,6 +242,31 @@ func (s *StorageProxy) Remove(ctx context.Context, name string) error {
 	return nil
 }
 
+// rwLayerIOPSLimit maps docker's --device-read-iops/--device-write-iops onto the single
+// aggregate IOPS limit the portlayer can apply to a container's rw layer disk today. Only the
+// container's own root filesystem is throttled by this - device paths naming a mounted volume
+// have no equivalent yet, so the highest of the read/write limits configured for any device is
+// used as a conservative stand-in for a true per-device limit.
+func rwLayerIOPSLimit(hostConfig *containertypes.HostConfig) int64 {
+	if hostConfig == nil {
+		return 0
+	}
+
+	var limit int64
+	for _, d := range hostConfig.BlkioDeviceReadIOps {
+		if int64(d.Rate) > limit {
+			limit = int64(d.Rate)
+		}
+	}
+	for _, d := range hostConfig.BlkioDeviceWriteIOps {
+		if int64(d.Rate) > limit {
+			limit = int64(d.Rate)
+		}
+	}
+
+	return limit
+}
+
 // AddImageToContainer adds the specified image to a container, referenced by handle.
 // If an error is returned, the returned handle should not be used.
 // - deltaID is the ID to use for the read/write layer - it's expected that this does not exist
@@ -273,10 +299,11 @@ func (s *StorageProxy) AddImageToContainer(ctx context.Context, handle, deltaID,
 
 	response, err := s.client.Storage.ImageJoin(storage.NewImageJoinParamsWithContext(op).WithOpID(&opID).WithStoreName(host).WithID(layerID).
 		WithConfig(&models.ImageJoinConfig{
-			Handle:   handle,
-			DeltaID:  deltaID,
-			ImageID:  imageID,
-			RepoName: config.Config.Image,
+			Handle:    handle,
+			DeltaID:   deltaID,
+			ImageID:   imageID,
+			RepoName:  config.Config.Image,
+			IopsLimit: rwLayerIOPSLimit(config.HostConfig),
 		}))
 	if err != nil {
 		if _, ok := err.(*storage.ImageJoinNotFound); ok {
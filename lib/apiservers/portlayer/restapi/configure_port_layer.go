@@ -21,6 +21,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -34,6 +39,8 @@ import (
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/options"
 	"github.com/vmware/vic/lib/portlayer"
+	"github.com/vmware/vic/lib/portlayer/auth"
+	"github.com/vmware/vic/pkg/trace"
 	"github.com/vmware/vic/pkg/version"
 	"github.com/vmware/vic/pkg/vsphere/session"
 )
@@ -54,6 +61,10 @@ var portlayerhandlers = []handler{
 	&handlers.KvHandlersImpl{},
 	&handlers.EventsHandlerImpl{},
 	&handlers.TaskHandlersImpl{},
+	&handlers.JobsHandlersImpl{},
+	&handlers.TemplatesHandlersImpl{},
+	&handlers.PodsHandlersImpl{},
+	&handlers.BundlesHandlersImpl{},
 }
 
 var apiServers []*graceful.Server
@@ -125,6 +136,8 @@ func configureAPI(api *operations.PortLayerAPI) http.Handler {
 	api.TarProducer = ByteStreamProducer()
 	api.TxtProducer = runtime.TextProducer()
 
+	configureAuth()
+
 	handlerCtx := &handlers.HandlerContext{
 		Session: sess,
 	}
@@ -132,12 +145,120 @@ func configureAPI(api *operations.PortLayerAPI) http.Handler {
 		handler.Configure(api, handlerCtx)
 	}
 
-	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
+	h := setupGlobalMiddleware(api.Serve(setupMiddlewares))
+	startReadOnlyListener(h)
+
+	return h
+}
+
+// startReadOnlyListener, if configured, serves h on a second address that only
+// allows GET/HEAD - every list/inspect/stats operation in this API is a GET, so
+// rejecting other methods is sufficient to make the listener read-only without
+// having to duplicate or filter individual routes.
+func startReadOnlyListener(h http.Handler) {
+	addr := options.PortLayerOptions.ReadOnlyListener
+	if addr == "" {
+		return
+	}
+
+	s := &graceful.Server{
+		Server: &http.Server{
+			Addr:    addr,
+			Handler: readOnlyFilter(h),
+		},
+		NoSignalHandling: true,
+		Timeout:          stopTimeout,
+	}
+	apiServers = append(apiServers, s)
+
+	go func() {
+		if err := s.ListenAndServe(); err != nil {
+			log.Errorf("read-only listener on %s stopped: %s", addr, err)
+		}
+	}()
+}
+
+// readOnlyFilter rejects every request that isn't a GET or HEAD.
+func readOnlyFilter(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "this listener is read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// certReloader serves a certificate that can be swapped out from under a running listener,
+// so a rotated server certificate can be picked up without restarting the port layer.
+type certReloader struct {
+	certPath string
+	keyPath  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(certPath, keyPath string) (*certReloader, error) {
+	r := &certReloader{certPath: certPath, keyPath: keyPath}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// watchSIGHUP reloads the certificate every time the process receives SIGHUP, which is the
+// signal vic-machine's certificate rotation already sends to other VCH services.
+func (r *certReloader) watchSIGHUP() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	for range sighup {
+		if err := r.reload(); err != nil {
+			log.Errorf("certificate rotation: failed to reload %s / %s, keeping previous certificate: %s", r.certPath, r.keyPath, err)
+			continue
+		}
+		log.Infof("certificate rotation: reloaded TLS certificate from %s", r.certPath)
+	}
 }
 
 // The TLS configuration before HTTPS server starts.
 func configureTLS(tlsConfig *tls.Config) {
-	// Make all necessary changes to the TLS configuration here.
+	if options.PortLayerOptions.TLSCertificate == "" || options.PortLayerOptions.TLSPrivateKey == "" {
+		// Rotation wasn't opted into - leave the certificate the generated server already
+		// loaded from its own --tls-certificate/--tls-key flags untouched.
+		return
+	}
+
+	reloader, err := newCertReloader(options.PortLayerOptions.TLSCertificate, options.PortLayerOptions.TLSPrivateKey)
+	if err != nil {
+		log.Errorf("certificate rotation: unable to watch %s / %s, falling back to static certificate: %s",
+			options.PortLayerOptions.TLSCertificate, options.PortLayerOptions.TLSPrivateKey, err)
+		return
+	}
+
+	tlsConfig.GetCertificate = reloader.GetCertificate
+	go reloader.watchSIGHUP()
 }
 
 func StopAPIServers() {
@@ -156,10 +277,62 @@ func configureServer(s *graceful.Server, scheme string) {
 	apiServers = append(apiServers, s)
 }
 
+// configureAuth registers whatever auth.Provider(s) the port layer was started with. Leaving
+// both --auth-static-token and --auth-provider-url unset (the default) registers nothing, so
+// the API is gated by its mutual-TLS client certificate alone, exactly as before this existed.
+func configureAuth() {
+	for _, pair := range options.PortLayerOptions.AuthStaticToken {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			log.Errorf("Ignoring malformed --auth-static-token value %q, expected token=subject", pair)
+			continue
+		}
+
+		auth.Register(auth.NewStaticTokenProvider(map[string]string{parts[0]: parts[1]}))
+	}
+
+	if options.PortLayerOptions.AuthProviderURL != "" {
+		auth.Register(auth.NewHTTPProvider("external", options.PortLayerOptions.AuthProviderURL))
+	}
+}
+
+// authBearerPrefix is the standard "Authorization: Bearer <credential>" scheme.
+const authBearerPrefix = "Bearer "
+
+// authMiddleware rejects requests with a missing or unauthenticated bearer credential once at
+// least one auth.Provider has been registered, and logs the resolved identity against every
+// request that passes - the audit trail the identities from pluggable auth backends feed into.
+// It's a no-op, as before this existed, when no provider is registered.
+func authMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.Registered() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		credential := strings.TrimPrefix(r.Header.Get("Authorization"), authBearerPrefix)
+		if credential == "" {
+			http.Error(w, "missing bearer credential", http.StatusUnauthorized)
+			return
+		}
+
+		op := trace.NewOperation(r.Context(), "auth")
+		id, err := auth.Authenticate(op, credential)
+		if err != nil {
+			log.Warnf("Rejected request for %s %s: %s", r.Method, r.URL.Path, err)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		log.Infof("portlayer API request: %s %s by %s (%s)", r.Method, r.URL.Path, id.Subject, id.Provider)
+		h.ServeHTTP(w, r)
+	})
+}
+
 // The middleware configuration is for the handler executors. These do not apply to the swagger.json document.
 // The middleware executes after routing but before authentication, binding and validation
 func setupMiddlewares(handler http.Handler) http.Handler {
-	return handler
+	return authMiddleware(handler)
 }
 
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
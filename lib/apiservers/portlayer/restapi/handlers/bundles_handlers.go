@@ -0,0 +1,217 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/models"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/bundles"
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/lib/portlayer/bundle"
+	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/lib/portlayer/network"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/version"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// BundlesHandlersImpl is the receiver for the bundle create handler. It composes the
+// already-configured network context and volume cache to create a bundle's members in
+// dependency order via lib/portlayer/bundle.
+type BundlesHandlersImpl struct {
+	handlerCtx *HandlerContext
+	netCtx     *network.Context
+}
+
+// Configure assigns functions to all the bundle api handlers. It must run after
+// StorageHandlersImpl.Configure, since it relies on DefaultVolumeCache having been set -
+// bundles is registered last in portlayerhandlers for this reason.
+func (handler *BundlesHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
+	api.BundlesCreateBundleHandler = bundles.CreateBundleHandlerFunc(handler.CreateBundle)
+
+	handler.handlerCtx = handlerCtx
+	handler.netCtx = network.DefaultContext
+}
+
+// CreateBundle resolves the dependency order of params.BundleConfig's resources and creates
+// each of them, rolling the whole bundle back if any member fails.
+func (handler *BundlesHandlersImpl) CreateBundle(params bundles.CreateBundleParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "bundles.CreateBundle()")
+	defer trace.End(trace.Begin("CreateBundle", op))
+
+	cfg := params.BundleConfig
+	spec := bundle.Spec{}
+
+	for _, n := range cfg.Networks {
+		spec.Resources = append(spec.Resources, bundle.Resource{Name: n.Name, Kind: bundle.KindNetwork, Spec: n})
+	}
+	for _, v := range cfg.Volumes {
+		spec.Resources = append(spec.Resources, bundle.Resource{Name: v.Name, Kind: bundle.KindVolume, Spec: v})
+	}
+	for _, c := range cfg.Containers {
+		deps := append([]string{}, c.DependsOn...)
+		deps = append(deps, c.Networks...)
+		spec.Resources = append(spec.Resources, bundle.Resource{Name: c.Name, Kind: bundle.KindContainer, DependsOn: deps, Spec: c})
+	}
+
+	creators := map[bundle.Kind]bundle.Creator{
+		bundle.KindNetwork:   &networkCreator{netCtx: handler.netCtx},
+		bundle.KindVolume:    &volumeCreator{},
+		bundle.KindContainer: &containerCreator{session: handler.handlerCtx.Session, netCtx: handler.netCtx},
+	}
+
+	res, err := bundle.Create(op, creators, spec)
+	if err != nil {
+		op.Errorf("CreateBundle error: %s", err.Error())
+		return bundles.NewCreateBundleDefault(500).WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return bundles.NewCreateBundleOK().WithPayload(&models.BundleCreatedInfo{Ids: res.IDs})
+}
+
+// networkCreator creates and destroys the bundle's BundleNetworkSpec resources as scopes.
+type networkCreator struct {
+	netCtx *network.Context
+}
+
+func (n *networkCreator) Create(op trace.Operation, r bundle.Resource) (string, error) {
+	spec := r.Spec.(*models.BundleNetworkSpec)
+
+	scopeType := spec.ScopeType
+	if scopeType == "" {
+		scopeType = "bridge"
+	}
+
+	subnet, gateway, _, _, err := parseScopeConfig(&models.ScopeConfig{Subnet: spec.Subnet, Gateway: spec.Gateway})
+	if err != nil {
+		return "", err
+	}
+
+	s, err := n.netCtx.NewScope(context.Background(), &network.ScopeData{
+		ScopeType: scopeType,
+		Name:      spec.Name,
+		Subnet:    subnet,
+		Gateway:   gateway,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.Name(), nil
+}
+
+func (n *networkCreator) Destroy(op trace.Operation, r bundle.Resource, id string) error {
+	return n.netCtx.DeleteScope(context.Background(), id)
+}
+
+// volumeCreator creates and destroys the bundle's BundleVolumeSpec resources via the
+// already-configured DefaultVolumeCache.
+type volumeCreator struct{}
+
+func (v *volumeCreator) Create(op trace.Operation, r bundle.Resource) (string, error) {
+	spec := r.Spec.(*models.BundleVolumeSpec)
+
+	if DefaultVolumeCache == nil {
+		return "", fmt.Errorf("no volume store configured")
+	}
+
+	storeURL, err := DefaultVolumeCache.GetVolumeStore(op, spec.Store)
+	if err != nil {
+		return "", err
+	}
+
+	id := uid.New().String()
+	vol, err := DefaultVolumeCache.VolumeCreate(op, id, storeURL, uint64(spec.Capacity), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return vol.ID, nil
+}
+
+func (v *volumeCreator) Destroy(op trace.Operation, r bundle.Resource, id string) error {
+	if DefaultVolumeCache == nil {
+		return nil
+	}
+	return DefaultVolumeCache.VolumeDestroy(op, id)
+}
+
+// containerCreator creates and destroys the bundle's BundleContainerSpec resources, joining
+// each to any networks it references before committing it.
+type containerCreator struct {
+	session *session.Session
+	netCtx  *network.Context
+}
+
+func (c *containerCreator) Create(op trace.Operation, r bundle.Resource) (string, error) {
+	spec := r.Spec.(*models.BundleContainerSpec)
+	id := uid.New().String()
+
+	numCPUs := spec.NumCpus
+	memoryMB := spec.MemoryMB
+	if numCPUs == 0 {
+		numCPUs = 1
+	}
+	if memoryMB == 0 {
+		memoryMB = 512
+	}
+
+	h, err := exec.Create(op, c.session, &exec.ContainerCreateConfig{
+		Metadata: &executor.ExecutorConfig{
+			ExecutorConfigCommon: executor.ExecutorConfigCommon{
+				ID:   id,
+				Name: spec.Name,
+			},
+			CreateTime: time.Now().UTC().UnixNano(),
+			Version:    version.GetBuild(),
+		},
+		Resources: exec.Resources{
+			NumCPUs:  int64(numCPUs),
+			MemoryMB: int64(memoryMB),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, netName := range spec.Networks {
+		if err := c.netCtx.AddContainer(h, &network.AddContainerOptions{Scope: netName}); err != nil {
+			h.Close()
+			return "", err
+		}
+	}
+
+	waitTime := int32(60)
+	if err := h.Commit(op, c.session, &waitTime); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (c *containerCreator) Destroy(op trace.Operation, r bundle.Resource, id string) error {
+	cont := exec.Containers.Container(id)
+	if cont == nil {
+		return nil
+	}
+	return cont.Remove(op, c.session)
+}
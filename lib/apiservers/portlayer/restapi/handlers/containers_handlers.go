@@ -30,26 +30,37 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/vic/lib/apiservers/portlayer/models"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/containers"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/options"
 	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/lib/constants"
 	"github.com/vmware/vic/lib/iolog"
 	"github.com/vmware/vic/lib/migration/feature"
+	"github.com/vmware/vic/lib/portlayer/admission"
+	"github.com/vmware/vic/lib/portlayer/event/events"
 	"github.com/vmware/vic/lib/portlayer/exec"
 	"github.com/vmware/vic/lib/portlayer/metrics"
 	"github.com/vmware/vic/lib/portlayer/network"
+	"github.com/vmware/vic/lib/portlayer/templates"
 	"github.com/vmware/vic/pkg/ip"
 	"github.com/vmware/vic/pkg/trace"
 	"github.com/vmware/vic/pkg/uid"
 	"github.com/vmware/vic/pkg/version"
+	vspheresession "github.com/vmware/vic/pkg/vsphere/session"
 )
 
 const (
 	containerWaitTimeout = 3 * time.Minute
+
+	// defaultOperationTimeout is used in place of options.PortLayerOptions.OperationTimeout
+	// when it hasn't been set, e.g. in tests that construct handlers without parsing flags.
+	defaultOperationTimeout = 5 * time.Minute
 )
 
 // ContainersHandlersImpl is the receiver for all of the exec handler methods
@@ -61,11 +72,14 @@ type ContainersHandlersImpl struct {
 // Configure assigns functions to all the exec api handlers
 func (handler *ContainersHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
 	api.ContainersCreateHandler = containers.CreateHandlerFunc(handler.CreateHandler)
+	api.ContainersCreateFromTemplateHandler = containers.CreateFromTemplateHandlerFunc(handler.CreateFromTemplateHandler)
 	api.ContainersStateChangeHandler = containers.StateChangeHandlerFunc(handler.StateChangeHandler)
 	api.ContainersGetHandler = containers.GetHandlerFunc(handler.GetHandler)
 	api.ContainersCommitHandler = containers.CommitHandlerFunc(handler.CommitHandler)
 	api.ContainersGetStateHandler = containers.GetStateHandlerFunc(handler.GetStateHandler)
 	api.ContainersContainerRemoveHandler = containers.ContainerRemoveHandlerFunc(handler.RemoveContainerHandler)
+	api.ContainersContainerExportHandler = containers.ContainerExportHandlerFunc(handler.ExportContainerHandler)
+	api.ContainersAdoptContainerHandler = containers.AdoptContainerHandlerFunc(handler.AdoptContainerHandler)
 	api.ContainersGetContainerInfoHandler = containers.GetContainerInfoHandlerFunc(handler.GetContainerInfoHandler)
 	api.ContainersGetContainerListHandler = containers.GetContainerListHandlerFunc(handler.GetContainerListHandler)
 	api.ContainersContainerSignalHandler = containers.ContainerSignalHandlerFunc(handler.ContainerSignalHandler)
@@ -73,6 +87,19 @@ func (handler *ContainersHandlersImpl) Configure(api *operations.PortLayerAPI, h
 	api.ContainersContainerWaitHandler = containers.ContainerWaitHandlerFunc(handler.ContainerWaitHandler)
 	api.ContainersContainerRenameHandler = containers.ContainerRenameHandlerFunc(handler.RenameContainerHandler)
 	api.ContainersGetContainerStatsHandler = containers.GetContainerStatsHandlerFunc(handler.GetContainerStatsHandler)
+	api.ContainersSetContainerIOPSLimitHandler = containers.SetContainerIOPSLimitHandlerFunc(handler.SetContainerIOPSLimitHandler)
+	api.ContainersSetContainerConsoleHandler = containers.SetContainerConsoleHandlerFunc(handler.SetContainerConsoleHandler)
+	api.ContainersRestoreContainerHandler = containers.RestoreContainerHandlerFunc(handler.RestoreContainerHandler)
+	api.ContainersMigrateContainerHandler = containers.MigrateContainerHandlerFunc(handler.MigrateContainerHandler)
+	api.ContainersSetContainerAffinityHandler = containers.SetContainerAffinityHandlerFunc(handler.SetContainerAffinityHandler)
+	api.ContainersListContainerBackupsHandler = containers.ListContainerBackupsHandlerFunc(handler.ListContainerBackupsHandler)
+	api.ContainersCreateContainerBackupHandler = containers.CreateContainerBackupHandlerFunc(handler.CreateContainerBackupHandler)
+	api.ContainersDeleteContainerBackupHandler = containers.DeleteContainerBackupHandlerFunc(handler.DeleteContainerBackupHandler)
+	api.ContainersRestoreContainerBackupHandler = containers.RestoreContainerBackupHandlerFunc(handler.RestoreContainerBackupHandler)
+	api.ContainersStartContainerCaptureHandler = containers.StartContainerCaptureHandlerFunc(handler.StartContainerCaptureHandler)
+	api.ContainersStopContainerCaptureHandler = containers.StopContainerCaptureHandlerFunc(handler.StopContainerCaptureHandler)
+	api.ContainersBatchStartHandler = containers.BatchStartHandlerFunc(handler.BatchStartHandler)
+	api.ContainersBatchPatchMetadataHandler = containers.BatchPatchMetadataHandlerFunc(handler.BatchPatchMetadataHandler)
 
 	handler.handlerCtx = handlerCtx
 	handler.netCtx = network.DefaultContext
@@ -80,12 +107,77 @@ func (handler *ContainersHandlersImpl) Configure(api *operations.PortLayerAPI, h
 
 // CreateHandler creates a new container
 func (handler *ContainersHandlersImpl) CreateHandler(params containers.CreateParams) middleware.Responder {
+	if params.CreateConfig == nil {
+		return containers.NewCreateBadRequest().WithPayload(&models.Error{Message: "create config is required"})
+	}
+
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.CreateHandler(%s)", params.CreateConfig.Name)
 	defer trace.End(trace.Begin("CreateHandler", op))
 
+	return handler.createContainer(op, params.CreateConfig)
+}
+
+// CreateFromTemplateHandler creates a new container from a stored template, applying
+// any fields set in params.Overrides on top of it. See lib/portlayer/templates.
+func (handler *ContainersHandlersImpl) CreateFromTemplateHandler(params containers.CreateFromTemplateParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.CreateFromTemplateHandler(%s)", params.Name)
+	defer trace.End(trace.Begin("CreateFromTemplateHandler", op))
+
+	base, ok := templates.Get(params.Name)
+	if !ok {
+		return containers.NewCreateFromTemplateNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("template %q not found", params.Name)})
+	}
+
+	return handler.createContainer(op, templates.Merge(base, params.Overrides))
+}
+
+// createContainer is the shared body of CreateHandler and CreateFromTemplateHandler -
+// everything past having a fully resolved models.ContainerCreateConfig in hand.
+func (handler *ContainersHandlersImpl) createContainer(op trace.Operation, cc *models.ContainerCreateConfig) middleware.Responder {
 	session := handler.handlerCtx.Session
 	id := uid.New().String()
 
+	// the first reportable stage of a container create - a client watching the event
+	// stream sees this before admission, network attach, or placement have run
+	exec.PublishContainerEvent(op, id, time.Now().UTC(), events.ContainerCreateValidating)
+
+	decision, err := admission.Review(op, admission.Request{
+		ID:          id,
+		Name:        cc.Name,
+		NumCPUs:     cc.NumCpus,
+		MemoryMB:    cc.MemoryMB,
+		Annotations: cc.Annotations,
+	})
+	if err != nil {
+		if capacity, ok := err.(admission.CapacityError); ok {
+			op.Infof("container create for %q rejected: %s", cc.Name, capacity.Error())
+			return containers.NewCreateServiceUnavailable().WithPayload(&models.Error{Message: capacity.Error()})
+		}
+
+		op.Errorf("admission hook error: %s", err.Error())
+		return containers.NewCreateForbidden().WithPayload(&models.Error{Message: err.Error()})
+	}
+	if decision != nil {
+		if !decision.Allow {
+			op.Infof("container create for %q rejected by admission policy: %s", cc.Name, decision.Reason)
+			return containers.NewCreateForbidden().WithPayload(&models.Error{Message: decision.Reason})
+		}
+		if decision.MemoryMB != 0 {
+			cc.MemoryMB = decision.MemoryMB
+		}
+	}
+
+	// admission.Review above reserves a slot against the container count cap for id. Once
+	// this handler hands the caller a real, committable Handle, that reservation stays open
+	// until CommitHandler's exec.Commit releases it; every other exit below - including the
+	// dry-run path, which never reaches Commit - must release it itself.
+	committable := false
+	defer func() {
+		if !committable {
+			admission.Release(id)
+		}
+	}()
+
 	// Init key for tether
 	// #nosec: RSA keys should be at least 2048 bits
 	// Size is 512 because key validation is not performed - see GitHub #2849
@@ -102,38 +194,74 @@ func (handler *ContainersHandlersImpl) CreateHandler(params containers.CreatePar
 	m := &executor.ExecutorConfig{
 		ExecutorConfigCommon: executor.ExecutorConfigCommon{
 			ID:   id,
-			Name: params.CreateConfig.Name,
+			Name: cc.Name,
 		},
-		CreateTime: time.Now().UTC().UnixNano(),
-		Version:    version.GetBuild(),
-		Key:        pem.EncodeToMemory(&privateKeyBlock),
-		Hostname:   params.CreateConfig.Hostname,
-		Domainname: params.CreateConfig.Domainname,
+		CreateTime:  time.Now().UTC().UnixNano(),
+		Version:     version.GetBuild(),
+		Key:         pem.EncodeToMemory(&privateKeyBlock),
+		Hostname:    cc.Hostname,
+		Domainname:  cc.Domainname,
+		ReapOrphans: true,
+		DependsOn:   cc.DependsOn,
+	}
+
+	if cc.TTLSeconds > 0 {
+		m.Deadline = time.Now().Unix() + cc.TTLSeconds
+		m.RemoveOnDeadline = cc.RemoveOnTTL
 	}
 
-	if params.CreateConfig.Annotations != nil && len(params.CreateConfig.Annotations) > 0 {
+	if cc.Annotations != nil && len(cc.Annotations) > 0 {
 		m.Annotations = make(map[string]string)
-		for k, v := range params.CreateConfig.Annotations {
+		for k, v := range cc.Annotations {
 			m.Annotations[k] = v
 		}
 	}
 
+	numCPUs, memoryMB, err := exec.ResolveResources(cc.NumCpus, cc.MemoryMB)
+	if err != nil {
+		return containers.NewCreateBadRequest().WithPayload(&models.Error{Message: err.Error()})
+	}
+
 	// Create the executor.ExecutorCreateConfig
 	c := &exec.ContainerCreateConfig{
 		Metadata: m,
 		Resources: exec.Resources{
-			NumCPUs:  params.CreateConfig.NumCpus,
-			MemoryMB: params.CreateConfig.MemoryMB,
+			NumCPUs:  numCPUs,
+			MemoryMB: memoryMB,
+			CPUSet:   cc.CPUSet,
+
+			ScratchDiskMB: cc.ScratchDiskMB,
 		},
+		DryRun:    cc.DryRun,
+		NoConsole: cc.NoConsole,
 	}
 
-	h, err := exec.Create(op, session, c)
+	var h *exec.Handle
+	err = session.WithLoginRetry(op, func() error {
+		var cerr error
+		h, cerr = exec.Create(op, session, c)
+		return cerr
+	})
 	if err != nil {
 		op.Errorf("ContainerCreate error: %s", err.Error())
+		if vspheresession.IsSessionExpired(err) {
+			return containers.NewCreateDefault(http.StatusServiceUnavailable).WithPayload(&models.Error{Message: err.Error()})
+		}
 		return containers.NewCreateNotFound().WithPayload(&models.Error{Message: err.Error()})
 	}
 
+	if cc.DryRun {
+		spec, merr := json.Marshal(h.Spec.Spec())
+		if merr != nil {
+			op.Errorf("ContainerCreate dry-run error marshaling spec: %s", merr.Error())
+			return containers.NewCreateNotFound().WithPayload(&models.Error{Message: merr.Error()})
+		}
+
+		return containers.NewCreateOK().WithPayload(&models.ContainerCreatedInfo{ID: id, Spec: string(spec)})
+	}
+
 	//  send the container id back to the caller
+	committable = true
 	return containers.NewCreateOK().WithPayload(&models.ContainerCreatedInfo{ID: id, Handle: h.String()})
 }
 
@@ -156,7 +284,13 @@ func (handler *ContainersHandlersImpl) StateChangeHandler(params containers.Stat
 	case "CREATED":
 		state = exec.StateCreated
 	default:
-		return containers.NewStateChangeDefault(http.StatusServiceUnavailable).WithPayload(&models.Error{Message: "unknown state"})
+		// this is a caller error, not a backend fault - report it as such rather than
+		// the generic 503 so swagger validation and clients can distinguish the two
+		return containers.NewStateChangeDefault(http.StatusBadRequest).WithPayload(&models.Error{Message: fmt.Sprintf("unknown state %q", params.State)})
+	}
+
+	if err := exec.ValidateTargetState(h.State(op), state); err != nil {
+		return containers.NewStateChangeDefault(http.StatusConflict).WithPayload(&models.Error{Message: err.Error()})
 	}
 
 	h.SetTargetState(state)
@@ -198,9 +332,12 @@ func (handler *ContainersHandlersImpl) GetHandler(params containers.GetParams) m
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.GetHandler(%s)", params.ID)
 	defer trace.End(trace.Begin("GetHandler", op))
 
-	h, err := exec.GetContainer(context.Background(), uid.Parse(params.ID))
+	h, err := exec.ResolveContainer(context.Background(), params.ID)
 	if err != nil {
-		return containers.NewGetDefault(503).WithPayload(&models.Error{Message: err.Error()})
+		if _, ok := err.(exec.AmbiguousIDError); ok {
+			return containers.NewGetDefault(http.StatusConflict).WithPayload(&models.Error{Message: err.Error()})
+		}
+		return containers.NewGetDefault(http.StatusServiceUnavailable).WithPayload(&models.Error{Message: err.Error()})
 	}
 	if h == nil {
 		return containers.NewGetNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
@@ -218,13 +355,38 @@ func (handler *ContainersHandlersImpl) CommitHandler(params containers.CommitPar
 		return containers.NewCommitNotFound().WithPayload(&models.Error{Message: "container not found"})
 	}
 
-	if err := h.Commit(op, handler.handlerCtx.Session, params.WaitTime); err != nil {
+	// Commit blocks on whatever vSphere tasks it issues (VM create, reconfigure, power
+	// on/off) with no bound of its own, so a slow or disconnected vCenter would otherwise
+	// hang the request forever. Bound the wait here rather than in Commit itself so the
+	// vSphere-side task keeps running - we're only giving up on waiting for it, not
+	// canceling it.
+	timeout := options.PortLayerOptions.OperationTimeout
+	if timeout <= 0 {
+		timeout = defaultOperationTimeout
+	}
+	timeoutOp, cancel := trace.WithTimeout(&op, timeout, "CommitHandler(%s)", params.Handle)
+	defer cancel()
+
+	if err := h.Commit(timeoutOp, handler.handlerCtx.Session, params.WaitTime); err != nil {
 		op.Errorf("CommitHandler error on handle(%s) for %s: %s", h, h.ExecConfig.ID, err)
+
+		if timeoutOp.Err() == context.DeadlineExceeded {
+			return containers.NewCommitDefault(http.StatusGatewayTimeout).WithPayload(&models.Error{Message: fmt.Sprintf("timed out waiting on vSphere after %s: %s", timeout, err)})
+		}
+
+		if exec.IsDuplicateNameError(err) {
+			return containers.NewCommitConflict().WithPayload(&models.Error{Message: err.Error()})
+		}
+
 		switch err := err.(type) {
 		case exec.ConcurrentAccessError:
 			return containers.NewCommitConflict().WithPayload(&models.Error{Message: err.Error()})
 		case exec.DevicesInUseError:
 			return containers.NewCommitConflict().WithPayload(&models.Error{Message: err.Error()})
+		case exec.NotYetExistError:
+			return containers.NewCommitNotFound().WithPayload(&models.Error{Message: err.Error()})
+		case exec.NotFoundError:
+			return containers.NewCommitNotFound().WithPayload(&models.Error{Message: err.Error()})
 		default:
 			return containers.NewCommitDefault(http.StatusServiceUnavailable).WithPayload(&models.Error{Message: err.Error()})
 		}
@@ -244,7 +406,13 @@ func (handler *ContainersHandlersImpl) RemoveContainerHandler(params containers.
 	}
 
 	// NOTE: this should allowing batching of operations, as with Create, Start, Stop, et al
-	err := container.Remove(op, handler.handlerCtx.Session)
+	var err error
+	if params.Trash {
+		retention := time.Duration(params.RestoreRetentionSeconds) * time.Second
+		err = container.Trash(op, handler.handlerCtx.Session, retention)
+	} else {
+		err = container.Remove(op, handler.handlerCtx.Session)
+	}
 	if err != nil {
 		switch err := err.(type) {
 		case exec.NotFoundError:
@@ -263,9 +431,353 @@ func (handler *ContainersHandlersImpl) RemoveContainerHandler(params containers.
 		}
 	}
 
+	// the VM is gone at this point, so release any scope reservations it was still
+	// holding - otherwise the IP/alias stays allocated forever since nothing else
+	// ever revisits a destroyed container's networks
+	if _, err := handler.netCtx.RemoveIDFromScopes(op, params.ID); err != nil {
+		op.Errorf("Failed to release network scopes for removed container %s: %s", params.ID, err.Error())
+	}
+
 	return containers.NewContainerRemoveOK()
 }
 
+// RestoreContainerHandler re-registers a container previously removed with trash=true,
+// restoring it to the inventory unchanged, as long as the trash reaper hasn't already
+// permanently deleted its files.
+func (handler *ContainersHandlersImpl) RestoreContainerHandler(params containers.RestoreContainerParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.RestoreContainerHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("RestoreContainerHandler", op))
+
+	c, err := exec.RestoreContainer(op, handler.handlerCtx.Session, params.ID)
+	if err != nil {
+		switch err.(type) {
+		case exec.NotFoundError:
+			return containers.NewRestoreContainerNotFound().WithPayload(&models.Error{Message: err.Error()})
+		default:
+			return containers.NewRestoreContainerInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+		}
+	}
+
+	return containers.NewRestoreContainerOK().WithPayload(c.String())
+}
+
+// ExportContainerHandler exports a stopped container's disks and metadata as an OVF package.
+func (handler *ContainersHandlersImpl) ExportContainerHandler(params containers.ContainerExportParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.ExportContainerHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("ExportContainerHandler", op))
+
+	container := exec.Containers.Container(params.ID)
+	if container == nil {
+		return containers.NewContainerExportNotFound()
+	}
+
+	descriptorPath, err := container.ExportOVF(op, params.DestDir)
+	if err != nil {
+		switch err := err.(type) {
+		case exec.NotFoundError:
+			return containers.NewContainerExportNotFound()
+		case exec.RemovePowerError:
+			return containers.NewContainerExportConflict().WithPayload(&models.Error{Message: err.Error()})
+		default:
+			return containers.NewContainerExportInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+		}
+	}
+
+	return containers.NewContainerExportOK().WithPayload(&models.ContainerExportInfo{DescriptorPath: descriptorPath})
+}
+
+// AdoptContainerHandler turns an existing, unmanaged VM into a container the portlayer
+// manages like any other, stamping its identity metadata and registering it in the cache.
+func (handler *ContainersHandlersImpl) AdoptContainerHandler(params containers.AdoptContainerParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.AdoptContainerHandler(%s)", params.AdoptConfig.Moref)
+	defer trace.End(trace.Begin("AdoptContainerHandler", op))
+
+	id := uid.New().String()
+
+	m := &executor.ExecutorConfig{
+		ExecutorConfigCommon: executor.ExecutorConfigCommon{
+			ID:   id,
+			Name: params.AdoptConfig.Name,
+		},
+		CreateTime: time.Now().UTC().UnixNano(),
+		Version:    version.GetBuild(),
+		Hostname:   params.AdoptConfig.Hostname,
+		Domainname: params.AdoptConfig.Domainname,
+	}
+
+	if len(params.AdoptConfig.Annotations) > 0 {
+		m.Annotations = make(map[string]string)
+		for k, v := range params.AdoptConfig.Annotations {
+			m.Annotations[k] = v
+		}
+	}
+
+	moref := types.ManagedObjectReference{Type: "VirtualMachine", Value: params.AdoptConfig.Moref}
+
+	h, err := exec.AdoptVM(op, handler.handlerCtx.Session, moref, m)
+	if err != nil {
+		op.Errorf("ContainerAdopt error: %s", err.Error())
+		return containers.NewAdoptContainerNotFound().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewAdoptContainerOK().WithPayload(&models.ContainerCreatedInfo{ID: id, Handle: h.String()})
+}
+
+// MigrateContainerHandler relocates a container into another VCH's resource pool for VCH
+// rebalancing. The destination portlayer is expected to adopt the container into its own cache
+// once it appears in its resource pool inventory - see AdoptContainerHandler.
+func (handler *ContainersHandlersImpl) MigrateContainerHandler(params containers.MigrateContainerParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.MigrateContainerHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("MigrateContainerHandler", op))
+
+	sess := handler.handlerCtx.Session
+
+	pool := object.NewResourcePool(sess.Vim25(), types.ManagedObjectReference{Type: "ResourcePool", Value: params.MigrateConfig.Pool})
+
+	var host *object.HostSystem
+	if params.MigrateConfig.Host != "" {
+		host = object.NewHostSystem(sess.Vim25(), types.ManagedObjectReference{Type: "HostSystem", Value: params.MigrateConfig.Host})
+	}
+
+	if err := exec.MigrateVM(op, sess, params.ID, pool, host, params.MigrateConfig.NewOwnerVCH); err != nil {
+		op.Errorf("ContainerMigrate error: %s", err.Error())
+		if _, ok := err.(exec.NotFoundError); ok {
+			return containers.NewMigrateContainerNotFound().WithPayload(&models.Error{Message: err.Error()})
+		}
+		return containers.NewMigrateContainerInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewMigrateContainerOK()
+}
+
+// SetContainerAffinityHandler creates or replaces a DRS VM-VM affinity rule keeping the
+// container on the same host as another VM - typically the VCH endpoint VM, to avoid
+// hairpinning port-mapped traffic across hosts, or another named container.
+func (handler *ContainersHandlersImpl) SetContainerAffinityHandler(params containers.SetContainerAffinityParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.SetContainerAffinityHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("SetContainerAffinityHandler", op))
+
+	target := types.ManagedObjectReference{Type: "VirtualMachine", Value: params.AffinityConfig.Target}
+
+	if err := exec.SetContainerAffinity(op, params.ID, target); err != nil {
+		op.Errorf("ContainerAffinity error: %s", err.Error())
+		if _, ok := err.(exec.NotFoundError); ok {
+			return containers.NewSetContainerAffinityNotFound().WithPayload(&models.Error{Message: err.Error()})
+		}
+		return containers.NewSetContainerAffinityInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewSetContainerAffinityOK()
+}
+
+// BatchStartHandler starts the given containers in dependency order, per each
+// container's ExecConfig.DependsOn
+func (handler *ContainersHandlersImpl) BatchStartHandler(params containers.BatchStartParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.BatchStartHandler(%v)", params.Ids)
+	defer trace.End(trace.Begin("BatchStartHandler", op))
+
+	if err := exec.BatchStart(op, handler.handlerCtx.Session, params.Ids); err != nil {
+		op.Errorf("BatchStart error: %s", err.Error())
+		return containers.NewBatchStartInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewBatchStartOK()
+}
+
+// BatchPatchMetadataHandler patches labels and/or annotations on every container
+// matching params.Config, reconfiguring the matched containers concurrently rather than
+// requiring the caller to issue one commit per container.
+func (handler *ContainersHandlersImpl) BatchPatchMetadataHandler(params containers.BatchPatchMetadataParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.BatchPatchMetadataHandler(%v)", params.Config.Ids)
+	defer trace.End(trace.Begin("BatchPatchMetadataHandler", op))
+
+	opts := exec.BatchMetadataOptions{
+		IDs:         params.Config.Ids,
+		LabelFilter: params.Config.LabelFilter,
+		Labels:      params.Config.Labels,
+		Annotations: params.Config.Annotations,
+	}
+
+	failures := exec.BatchPatchMetadata(op, handler.handlerCtx.Session, opts)
+
+	results := make([]*models.BatchMetadataResult, len(failures))
+	for i, f := range failures {
+		results[i] = &models.BatchMetadataResult{ID: f.ID, Error: f.Err.Error()}
+	}
+
+	if len(failures) > 0 {
+		op.Errorf("BatchPatchMetadata: %d containers failed to patch", len(failures))
+	}
+
+	return containers.NewBatchPatchMetadataOK().WithPayload(results)
+}
+
+// ListContainerBackupsHandler lists the container's backup snapshots
+func (handler *ContainersHandlersImpl) ListContainerBackupsHandler(params containers.ListContainerBackupsParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.ListContainerBackupsHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("ListContainerBackupsHandler", op))
+
+	container := exec.Containers.Container(params.ID)
+	if container == nil {
+		return containers.NewListContainerBackupsNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
+	}
+
+	backups, err := container.ListBackups(op)
+	if err != nil {
+		op.Errorf("ListContainerBackups error: %s", err.Error())
+		return containers.NewListContainerBackupsNotFound().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	payload := make([]*models.BackupInfo, 0, len(backups))
+	for _, b := range backups {
+		payload = append(payload, backupInfo(b))
+	}
+
+	return containers.NewListContainerBackupsOK().WithPayload(payload)
+}
+
+// CreateContainerBackupHandler takes a quiesced snapshot of the container's disk
+func (handler *ContainersHandlersImpl) CreateContainerBackupHandler(params containers.CreateContainerBackupParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.CreateContainerBackupHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("CreateContainerBackupHandler", op))
+
+	container := exec.Containers.Container(params.ID)
+	if container == nil {
+		return containers.NewCreateContainerBackupNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
+	}
+
+	backup, err := container.Backup(op)
+	if err != nil {
+		op.Errorf("CreateContainerBackup error: %s", err.Error())
+		return containers.NewCreateContainerBackupInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewCreateContainerBackupOK().WithPayload(backupInfo(backup))
+}
+
+// DeleteContainerBackupHandler deletes a container backup
+func (handler *ContainersHandlersImpl) DeleteContainerBackupHandler(params containers.DeleteContainerBackupParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.DeleteContainerBackupHandler(%s, %s)", params.ID, params.Name)
+	defer trace.End(trace.Begin("DeleteContainerBackupHandler", op))
+
+	container := exec.Containers.Container(params.ID)
+	if container == nil {
+		return containers.NewDeleteContainerBackupNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
+	}
+
+	moref, err := backupMoref(op, container, params.Name)
+	if err != nil {
+		return containers.NewDeleteContainerBackupNotFound().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	if err := container.RemoveBackup(op, moref); err != nil {
+		op.Errorf("DeleteContainerBackup error: %s", err.Error())
+		return containers.NewDeleteContainerBackupInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewDeleteContainerBackupOK()
+}
+
+// RestoreContainerBackupHandler reverts the container's disk to a previous backup
+func (handler *ContainersHandlersImpl) RestoreContainerBackupHandler(params containers.RestoreContainerBackupParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.RestoreContainerBackupHandler(%s, %s)", params.ID, params.Name)
+	defer trace.End(trace.Begin("RestoreContainerBackupHandler", op))
+
+	container := exec.Containers.Container(params.ID)
+	if container == nil {
+		return containers.NewRestoreContainerBackupNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
+	}
+
+	if err := container.RestoreBackup(op, params.Name); err != nil {
+		op.Errorf("RestoreContainerBackup error: %s", err.Error())
+		return containers.NewRestoreContainerBackupInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewRestoreContainerBackupOK()
+}
+
+// backupMoref finds the snapshot moref for a named backup, since the RemoveSnapshot
+// API needs a moref rather than the name RevertToSnapshot accepts.
+func backupMoref(op trace.Operation, container *exec.Container, name string) (types.ManagedObjectReference, error) {
+	backups, err := container.ListBackups(op)
+	if err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+
+	for _, b := range backups {
+		if b.Name == name {
+			return b.Moref, nil
+		}
+	}
+
+	return types.ManagedObjectReference{}, fmt.Errorf("backup %q not found for container %s", name, container.ExecConfig.ID)
+}
+
+func backupInfo(b *exec.BackupInfo) *models.BackupInfo {
+	return &models.BackupInfo{
+		ContainerID: b.ContainerID,
+		Name:        b.Name,
+		Created:     strfmt.DateTime(b.Created),
+		Volumes:     b.Volumes,
+	}
+}
+
+// StartContainerCaptureHandler starts a time-boxed packet mirror on the container's network
+func (handler *ContainersHandlersImpl) StartContainerCaptureHandler(params containers.StartContainerCaptureParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.StartContainerCaptureHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("StartContainerCaptureHandler", op))
+
+	if exec.Containers.Container(params.ID) == nil {
+		return containers.NewStartContainerCaptureNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("container %s not found", params.ID)})
+	}
+
+	cfg := params.CaptureConfig
+	duration := captureDuration
+	if cfg.DurationSeconds != 0 {
+		duration = time.Duration(cfg.DurationSeconds) * time.Second
+	}
+
+	session, err := exec.StartPacketCapture(op, params.ID, cfg.Network, cfg.DestinationPortKey, duration)
+	if err != nil {
+		op.Errorf("StartContainerCapture error: %s", err.Error())
+		return containers.NewStartContainerCaptureInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewStartContainerCaptureOK().WithPayload(captureSessionInfo(session))
+}
+
+// StopContainerCaptureHandler stops a packet capture session started by StartContainerCaptureHandler
+func (handler *ContainersHandlersImpl) StopContainerCaptureHandler(params containers.StopContainerCaptureParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.StopContainerCaptureHandler(%s, %s)", params.ID, params.Key)
+	defer trace.End(trace.Begin("StopContainerCaptureHandler", op))
+
+	if err := exec.StopPacketCapture(op, params.Key); err != nil {
+		if exec.IsNotFoundError(err) {
+			return containers.NewStopContainerCaptureNotFound().WithPayload(&models.Error{Message: fmt.Sprintf("capture session %s not found", params.Key)})
+		}
+
+		op.Errorf("StopContainerCapture error: %s", err.Error())
+		return containers.NewStopContainerCaptureInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewStopContainerCaptureOK()
+}
+
+// captureDuration is used when a client doesn't specify durationSeconds - long enough to
+// attach a capture tool to the mirror destination without leaving it running indefinitely.
+const captureDuration = 5 * time.Minute
+
+func captureSessionInfo(s *exec.CaptureSession) *models.CaptureSessionInfo {
+	return &models.CaptureSessionInfo{
+		Key:                s.Key,
+		ContainerID:        s.ContainerID,
+		Network:            s.Network,
+		SourcePortKey:      s.SourcePortKey,
+		DestinationPortKey: s.DestinationPortKey,
+		Expires:            strfmt.DateTime(s.Expires),
+	}
+}
+
 func (handler *ContainersHandlersImpl) GetContainerInfoHandler(params containers.GetContainerInfoParams) middleware.Responder {
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.GetContainerInfoHandler(%s)", params.ID)
 	defer trace.End(trace.Begin("GetContainerInfoHandler", op))
@@ -449,21 +961,26 @@ func (handler *ContainersHandlersImpl) GetContainerLogsHandler(params containers
 	return NewStreamOutputHandler("containerLogs").WithPayload(detachableOut, params.ID, nil)
 }
 
+// containerWaitDeadline picks the context governing a ContainerWaitHandler call. A negative
+// timeoutSeconds means wait indefinitely (see ContainerBackend.ContainerWait), so only a
+// non-negative value gets a deadline - falling back to containerWaitTimeout for a negative
+// value would silently truncate an indefinite wait to 3 minutes.
+func containerWaitDeadline(op trace.Operation, timeoutSeconds int64) (context.Context, context.CancelFunc) {
+	switch {
+	case timeoutSeconds > 0:
+		return context.WithTimeout(op, time.Duration(timeoutSeconds)*time.Second)
+	case timeoutSeconds == 0:
+		return context.WithTimeout(op, containerWaitTimeout)
+	default:
+		return context.WithCancel(op)
+	}
+}
+
 func (handler *ContainersHandlersImpl) ContainerWaitHandler(params containers.ContainerWaitParams) middleware.Responder {
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.ContainerWaitHandler(%s, %d)", params.ID, params.Timeout)
 	defer trace.End(trace.Begin("ContainerWaitHandler", op))
 
-	// default context timeout in seconds
-	defaultTimeout := int64(containerWaitTimeout.Seconds())
-
-	// if we have a positive timeout specified then use it
-	if params.Timeout > 0 {
-		defaultTimeout = params.Timeout
-	}
-
-	timeout := time.Duration(defaultTimeout) * time.Second
-
-	ctx, cancel := context.WithTimeout(op, timeout)
+	ctx, cancel := containerWaitDeadline(op, params.Timeout)
 	defer cancel()
 
 	c := exec.Containers.Container(uid.Parse(params.ID).String())
@@ -525,6 +1042,43 @@ func (handler *ContainersHandlersImpl) RenameContainerHandler(params containers.
 	return containers.NewContainerRenameOK().WithPayload(h.String())
 }
 
+// SetContainerIOPSLimitHandler updates the aggregate read+write IOPS limit on a container's rw
+// layer disk. Like Rename, this only mutates the handle - it's up to the caller to Commit it.
+func (handler *ContainersHandlersImpl) SetContainerIOPSLimitHandler(params containers.SetContainerIOPSLimitParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.SetContainerIOPSLimitHandler(%s, %d)", params.Handle, params.Limit)
+	defer trace.End(trace.Begin("SetContainerIOPSLimitHandler", op))
+
+	h := exec.GetHandle(params.Handle)
+	if h == nil || h.ExecConfig == nil {
+		return containers.NewSetContainerIOPSLimitNotFound()
+	}
+
+	if err := h.SetDiskIOLimit(op, params.Limit); err != nil {
+		return containers.NewSetContainerIOPSLimitInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewSetContainerIOPSLimitOK().WithPayload(h.String())
+}
+
+// SetContainerConsoleHandler adds or removes a container's virtual video card, so a container
+// provisioned serial-only for density can have a console temporarily restored for interactive
+// debugging. Like Rename, this only mutates the handle - it's up to the caller to Commit it.
+func (handler *ContainersHandlersImpl) SetContainerConsoleHandler(params containers.SetContainerConsoleParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "containers.SetContainerConsoleHandler(%s, %t)", params.Handle, params.Enabled)
+	defer trace.End(trace.Begin("SetContainerConsoleHandler", op))
+
+	h := exec.GetHandle(params.Handle)
+	if h == nil || h.ExecConfig == nil {
+		return containers.NewSetContainerConsoleNotFound()
+	}
+
+	if err := h.SetConsoleEnabled(op, params.Enabled); err != nil {
+		return containers.NewSetContainerConsoleInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return containers.NewSetContainerConsoleOK().WithPayload(h.String())
+}
+
 // utility function to convert from a Container type to the API Model ContainerInfo (which should prob be called ContainerDetail)
 func convertContainerToContainerInfo(c *exec.Container) *models.ContainerInfo {
 	container := c.Info()
@@ -594,6 +1148,21 @@ func convertContainerToContainerInfo(c *exec.Container) *models.ContainerInfo {
 	info.ContainerConfig.MemorySizeMB = int64(container.MemorySizeMB)
 	info.ContainerConfig.NumCPU = container.NumCPU
 
+	// vsphereHost/datastore reflect the VM's actual current placement, which can change
+	// out from under us via DRS - Runtime and Config are refreshed independently of this
+	// handler, so this is best-effort as of the last cache refresh rather than a live query.
+	if container.Runtime != nil && container.Runtime.Host != nil {
+		host := container.Runtime.Host.Value
+		info.ContainerConfig.VsphereHost = &host
+	}
+	if container.Config != nil && len(container.Config.DatastoreUrl) > 0 {
+		datastore := container.Config.DatastoreUrl[0].Name
+		info.ContainerConfig.Datastore = &datastore
+	}
+	info.ContainerConfig.TaskHistory = container.TaskHistory
+	info.ContainerConfig.TetherStatus = container.TetherStatus(trace.NewOperation(context.Background(), "tetherStatus(%s)", ccid))
+	info.ContainerConfig.Generation = int64(container.Generation)
+
 	if container.ExecConfig.Annotations != nil && len(container.ExecConfig.Annotations) > 0 {
 		info.ContainerConfig.Annotations = make(map[string]string)
 
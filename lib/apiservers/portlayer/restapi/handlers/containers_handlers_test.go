@@ -0,0 +1,93 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/containers"
+	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// TestCreateHandlerNilCreateConfig ensures a request with no create config in
+// the body is rejected with a structured error instead of panicking on the
+// nil dereference in the trace operation name.
+func TestCreateHandlerNilCreateConfig(t *testing.T) {
+	handler := &ContainersHandlersImpl{}
+
+	responder := handler.CreateHandler(containers.CreateParams{CreateConfig: nil})
+
+	badRequest, ok := responder.(*containers.CreateBadRequest)
+	if !ok {
+		t.Fatalf("expected *containers.CreateBadRequest, got %T", responder)
+	}
+
+	if badRequest.Payload == nil || badRequest.Payload.Message == "" {
+		t.Error("expected a non-empty error message")
+	}
+}
+
+// TestGetContainerInfoHandlerUnknownID ensures inspecting a container ID the
+// cache has never seen - e.g. it was never created, or belongs to a VM with
+// no vic metadata - is reported as a 404 rather than surfacing a decode panic.
+func TestGetContainerInfoHandlerUnknownID(t *testing.T) {
+	exec.NewContainerCache()
+
+	handler := &ContainersHandlersImpl{}
+
+	responder := handler.GetContainerInfoHandler(containers.GetContainerInfoParams{ID: "no-such-container"})
+
+	notFound, ok := responder.(*containers.GetContainerInfoNotFound)
+	if !ok {
+		t.Fatalf("expected *containers.GetContainerInfoNotFound, got %T", responder)
+	}
+
+	if notFound.Payload == nil || notFound.Payload.Message == "" {
+		t.Error("expected a non-empty error message")
+	}
+}
+
+// TestContainerWaitDeadlineNegativeTimeoutIsIndefinite ensures a negative timeout - the
+// documented way to ask ContainerWait to wait forever - doesn't fall back to the finite
+// containerWaitTimeout default, which would silently cut an indefinite wait short.
+func TestContainerWaitDeadlineNegativeTimeoutIsIndefinite(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	ctx, cancel := containerWaitDeadline(op, -1)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("expected a negative timeout to produce a context with no deadline")
+	}
+}
+
+func TestContainerWaitDeadlineZeroTimeoutUsesDefault(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	ctx, cancel := containerWaitDeadline(op, 0)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("expected a zero timeout to fall back to the default deadline")
+	}
+
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > containerWaitTimeout {
+		t.Errorf("expected deadline within (0, %s] from now, got %s", containerWaitTimeout, remaining)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// validateSessionEncoding enforces the tether's encoding contract for a session's launch
+// config: Path, Args and Env are carried to the guest via ExtraConfig, which round-trips
+// through vSphere's XML-based API - a value that isn't valid UTF-8 either gets mangled in
+// transit or breaks the tether's parsing on the other end. Reject it here, at the API
+// boundary, rather than let it reach guestinfo and fail somewhere much harder to diagnose.
+func validateSessionEncoding(path string, args, env []string) error {
+	if !utf8.ValidString(path) {
+		return fmt.Errorf("path is not valid UTF-8: %q", path)
+	}
+
+	for i, a := range args {
+		if !utf8.ValidString(a) {
+			return fmt.Errorf("arg %d is not valid UTF-8: %q", i, a)
+		}
+	}
+
+	for i, e := range env {
+		if !utf8.ValidString(e) {
+			return fmt.Errorf("environment variable %d is not valid UTF-8: %q", i, e)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSessionEncoding(t *testing.T) {
+	assert.NoError(t, validateSessionEncoding("/bin/sh", []string{"-c", "echo hello"}, []string{"PATH=/usr/bin"}))
+
+	invalid := string([]byte{0xff, 0xfe, 0xfd})
+
+	assert.Error(t, validateSessionEncoding(invalid, nil, nil))
+	assert.Error(t, validateSessionEncoding("/bin/sh", []string{invalid}, nil))
+	assert.Error(t, validateSessionEncoding("/bin/sh", nil, []string{invalid}))
+}
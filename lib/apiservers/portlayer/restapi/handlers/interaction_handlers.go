@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -26,6 +27,7 @@ import (
 	"github.com/vmware/vic/lib/apiservers/portlayer/models"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/interaction"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/options"
 	"github.com/vmware/vic/lib/constants"
 	"github.com/vmware/vic/lib/portlayer/attach"
 	"github.com/vmware/vic/lib/portlayer/attach/communication"
@@ -61,6 +63,15 @@ func (i *InteractionHandlersImpl) Configure(api *operations.PortLayerAPI, _ *Han
 
 	api.InteractionContainerCloseStdinHandler = interaction.ContainerCloseStdinHandlerFunc(i.ContainerCloseStdinHandler)
 
+	if options.PortLayerOptions.ConnectorURI != "" {
+		u, err := url.Parse(options.PortLayerOptions.ConnectorURI)
+		if err != nil || u.Scheme != "tcp" || u.Host == "" {
+			log.Fatalf("invalid --connector-uri %q: must be of the form tcp://host:port", options.PortLayerOptions.ConnectorURI)
+		}
+
+		attach.Config.ServiceURI = options.PortLayerOptions.ConnectorURI
+	}
+
 	i.server = communication.NewServer("localhost", constants.AttachServerPort)
 	if err := i.server.Start(); err != nil {
 		log.Fatalf("Attach server unable to start: %s", err)
@@ -77,7 +88,7 @@ func (i *InteractionHandlersImpl) JoinHandler(params interaction.InteractionJoin
 		return interaction.NewInteractionJoinInternalServerError().WithPayload(err)
 	}
 
-	handleprime, err := attach.Join(handle)
+	handleprime, err := attach.Join(handle, params.Config.ConnectorURI)
 	if err != nil {
 		log.Errorf("%s", err.Error())
 
@@ -192,6 +203,15 @@ func (i *InteractionHandlersImpl) ContainerSetStdinHandler(params interaction.Co
 		return interaction.NewContainerSetStdinNotFound().WithPayload(e)
 	}
 
+	if err = i.server.AcquireSession(params.ID); err != nil {
+		log.Errorf("%s", err.Error())
+
+		return interaction.NewContainerSetStdinConflict().WithPayload(
+			&models.Error{Message: err.Error()},
+		)
+	}
+	defer i.server.ReleaseSession(params.ID)
+
 	detachableIn := NewFlushingReaderWithInitBytes(params.RawStream, []byte(attachStdinInitString))
 	_, err = io.Copy(session.Stdin(), detachableIn)
 	if err != nil {
@@ -274,12 +294,20 @@ func (i *InteractionHandlersImpl) ContainerGetStdoutHandler(params interaction.C
 		return interaction.NewContainerGetStdoutNotFound()
 	}
 
+	if err = i.server.AcquireSession(params.ID); err != nil {
+		log.Errorf("%s", err.Error())
+
+		return interaction.NewContainerGetStdoutConflict().WithPayload(
+			&models.Error{Message: err.Error()},
+		)
+	}
+
 	return NewStreamOutputHandler("stdout").WithPayload(
 		NewFlushingReader(
 			session.Stdout(),
 		),
 		params.ID,
-		nil,
+		func() { i.server.ReleaseSession(params.ID) },
 	)
 }
 
@@ -307,11 +335,19 @@ func (i *InteractionHandlersImpl) ContainerGetStderrHandler(params interaction.C
 		return interaction.NewContainerGetStderrNotFound()
 	}
 
+	if err = i.server.AcquireSession(params.ID); err != nil {
+		log.Errorf("%s", err.Error())
+
+		return interaction.NewContainerGetStderrConflict().WithPayload(
+			&models.Error{Message: err.Error()},
+		)
+	}
+
 	return NewStreamOutputHandler("stderr").WithPayload(
 		NewFlushingReader(
 			session.Stderr(),
 		),
 		params.ID,
-		nil,
+		func() { i.server.ReleaseSession(params.ID) },
 	)
 }
@@ -0,0 +1,146 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/models"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/jobs"
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/lib/portlayer/exec"
+	jobmgr "github.com/vmware/vic/lib/portlayer/jobs"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/version"
+)
+
+// JobsHandlersImpl is the receiver for all of the scheduled-job api handlers
+type JobsHandlersImpl struct {
+	manager *jobmgr.Manager
+}
+
+// Configure assigns functions to all the jobs api handlers
+func (handler *JobsHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
+	api.JobsListJobsHandler = jobs.ListJobsHandlerFunc(handler.ListJobsHandler)
+	api.JobsCreateJobHandler = jobs.CreateJobHandlerFunc(handler.CreateJobHandler)
+	api.JobsGetJobHandler = jobs.GetJobHandlerFunc(handler.GetJobHandler)
+	api.JobsDeleteJobHandler = jobs.DeleteJobHandlerFunc(handler.DeleteJobHandler)
+
+	handler.manager = jobmgr.NewManager(handlerCtx.Session)
+}
+
+// CreateJobHandler stores a new scheduled job and starts triggering it on its interval
+func (handler *JobsHandlersImpl) CreateJobHandler(params jobs.CreateJobParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "jobs.CreateJobHandler(%s)", params.JobConfig.Name)
+	defer trace.End(trace.Begin("CreateJobHandler", op))
+
+	if params.JobConfig.IntervalSeconds <= 0 {
+		return jobs.NewCreateJobBadRequest().WithPayload(&models.Error{Message: "intervalSeconds must be positive"})
+	}
+
+	cc := params.JobConfig.CreateConfig
+	id := uid.New().String()
+
+	m := &executor.ExecutorConfig{
+		ExecutorConfigCommon: executor.ExecutorConfigCommon{
+			ID:   id,
+			Name: cc.Name,
+		},
+		CreateTime:  time.Now().UTC().UnixNano(),
+		Version:     version.GetBuild(),
+		Hostname:    cc.Hostname,
+		Domainname:  cc.Domainname,
+		ReapOrphans: true,
+	}
+
+	cfg := &exec.ContainerCreateConfig{
+		Metadata: m,
+		Resources: exec.Resources{
+			NumCPUs:  cc.NumCpus,
+			MemoryMB: cc.MemoryMB,
+		},
+	}
+
+	interval := time.Duration(params.JobConfig.IntervalSeconds) * time.Second
+	j := handler.manager.Create(params.JobConfig.Name, cfg, jobmgr.Schedule{Interval: interval})
+
+	return jobs.NewCreateJobOK().WithPayload(jobInfo(j))
+}
+
+// ListJobsHandler lists every scheduled job
+func (handler *JobsHandlersImpl) ListJobsHandler(params jobs.ListJobsParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "jobs.ListJobsHandler()")
+	defer trace.End(trace.Begin("ListJobsHandler", op))
+
+	all := handler.manager.List()
+	payload := make([]*models.JobInfo, 0, len(all))
+	for _, j := range all {
+		payload = append(payload, jobInfo(j))
+	}
+
+	return jobs.NewListJobsOK().WithPayload(payload)
+}
+
+// GetJobHandler returns a single scheduled job's configuration and run history
+func (handler *JobsHandlersImpl) GetJobHandler(params jobs.GetJobParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "jobs.GetJobHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("GetJobHandler", op))
+
+	j, ok := handler.manager.Get(params.ID)
+	if !ok {
+		return jobs.NewGetJobNotFound()
+	}
+
+	return jobs.NewGetJobOK().WithPayload(jobInfo(j))
+}
+
+// DeleteJobHandler cancels a scheduled job's future runs
+func (handler *JobsHandlersImpl) DeleteJobHandler(params jobs.DeleteJobParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "jobs.DeleteJobHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("DeleteJobHandler", op))
+
+	if !handler.manager.Delete(params.ID) {
+		return jobs.NewDeleteJobNotFound()
+	}
+
+	return jobs.NewDeleteJobOK()
+}
+
+func jobInfo(j *jobmgr.Job) *models.JobInfo {
+	history := j.History()
+	runs := make([]*models.JobRun, 0, len(history))
+	for _, r := range history {
+		run := &models.JobRun{
+			ContainerID: r.ContainerID,
+			Start:       strfmt.DateTime(r.Start),
+			End:         strfmt.DateTime(r.End),
+			Error:       r.Error,
+		}
+		runs = append(runs, run)
+	}
+
+	return &models.JobInfo{
+		ID:              j.ID,
+		Name:            j.Name,
+		IntervalSeconds: int64(j.Schedule.Interval / time.Second),
+		History:         runs,
+	}
+}
@@ -23,15 +23,22 @@ import (
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/misc"
 	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/pkg/trace"
 )
 
 // MiscHandlersImpl is the receiver for all the misc handler methods
-type MiscHandlersImpl struct{}
+type MiscHandlersImpl struct {
+	handlerCtx *HandlerContext
+}
 
 // Configure assigns functions to all the miscellaneous api handlers
 func (h *MiscHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
 	api.MiscPingHandler = misc.PingHandlerFunc(h.Ping)
 	api.MiscGetVCHInfoHandler = misc.GetVCHInfoHandlerFunc(h.GetVCHInfo)
+	api.MiscGetCapabilitiesHandler = misc.GetCapabilitiesHandlerFunc(h.GetCapabilities)
+	api.MiscGetHostCapacityHandler = misc.GetHostCapacityHandlerFunc(h.GetHostCapacity)
+
+	h.handlerCtx = handlerCtx
 }
 
 // Ping sends an OK response to let the client know the server is up
@@ -44,15 +51,66 @@ func (h *MiscHandlersImpl) GetVCHInfo(params misc.GetVCHInfoParams) middleware.R
 
 	vch := exec.GetVCHstats(context.Background())
 
+	var reconnectQueueDepth int64
+	if h.handlerCtx.Session.ReconnectQueue != nil {
+		reconnectQueueDepth = int64(h.handlerCtx.Session.ReconnectQueue.Depth())
+	}
+
 	vchInfo := &models.VCHInfo{
-		CPUMhz:          vch.CPULimit,
-		Memory:          vch.MemoryLimit,
-		CPUUsage:        vch.CPUUsage,
-		MemUsage:        vch.MemoryUsage,
-		HostOS:          exec.Config.HostOS,
-		HostOSVersion:   exec.Config.HostOSVersion,
-		HostProductName: exec.Config.HostProductName,
+		CPUMhz:              vch.CPULimit,
+		Memory:              vch.MemoryLimit,
+		CPUUsage:            vch.CPUUsage,
+		MemUsage:            vch.MemoryUsage,
+		HostOS:              exec.Config.HostOS,
+		HostOSVersion:       exec.Config.HostOSVersion,
+		HostProductName:     exec.Config.HostProductName,
+		ReconnectQueueDepth: reconnectQueueDepth,
 	}
 
 	return misc.NewGetVCHInfoOK().WithPayload(vchInfo)
 }
+
+// GetCapabilities enumerates which optional subsystems this portlayer build/deployment
+// supports, so the personality can degrade gracefully instead of guessing. Subsystems that
+// are always present regardless of build (e.g. containers themselves) aren't listed here.
+func (h *MiscHandlersImpl) GetCapabilities(params misc.GetCapabilitiesParams) middleware.Responder {
+	return misc.NewGetCapabilitiesOK().WithPayload(&models.Capabilities{
+		Volumes:     true,
+		PortMapping: true,
+		Exec:        true,
+		Stats:       true,
+
+		// Neither is implemented by this port layer today - listed explicitly rather than
+		// omitted so callers can distinguish "unsupported" from "this build predates the field".
+		IPv6:      false,
+		NsxDriver: false,
+	})
+}
+
+// GetHostCapacity reports, per cluster host backing this VCH, how many of its container VMs
+// are running there, their aggregate reservations and the host's remaining headroom - input
+// for capacity planning and for external schedulers layered on VIC.
+func (h *MiscHandlersImpl) GetHostCapacity(params misc.GetHostCapacityParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "GetHostCapacity")
+	defer trace.End(trace.Begin("GetHostCapacity", op))
+
+	report, err := exec.HostCapacity(op, h.handlerCtx.Session)
+	if err != nil {
+		op.Errorf("Error getting host capacity: %s", err)
+		return misc.NewGetHostCapacityDefault(500).WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	hosts := make([]*models.HostDensity, len(report))
+	for i, hd := range report {
+		hosts[i] = &models.HostDensity{
+			HostName:         hd.HostName,
+			Containers:       int64(hd.Containers),
+			ReservedMemoryMB: hd.ReservedMemoryMB,
+			ReservedCPU:      hd.ReservedCPU,
+			FreeMemoryMB:     hd.FreeMemoryMB,
+			FreeCPUMhz:       hd.FreeCPUMhz,
+		}
+	}
+
+	return misc.NewGetHostCapacityOK().WithPayload(hosts)
+}
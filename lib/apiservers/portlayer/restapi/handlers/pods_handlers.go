@@ -0,0 +1,130 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/models"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/pods"
+	podmgr "github.com/vmware/vic/lib/portlayer/pods"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// PodsHandlersImpl is the receiver for all of the pod api handlers
+type PodsHandlersImpl struct {
+	manager *podmgr.Manager
+}
+
+// Configure assigns functions to all the pods api handlers
+func (handler *PodsHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
+	api.PodsListPodsHandler = pods.ListPodsHandlerFunc(handler.ListPodsHandler)
+	api.PodsCreatePodHandler = pods.CreatePodHandlerFunc(handler.CreatePodHandler)
+	api.PodsGetPodHandler = pods.GetPodHandlerFunc(handler.GetPodHandler)
+	api.PodsDeletePodHandler = pods.DeletePodHandlerFunc(handler.DeletePodHandler)
+	api.PodsStartPodHandler = pods.StartPodHandlerFunc(handler.StartPodHandler)
+	api.PodsStopPodHandler = pods.StopPodHandlerFunc(handler.StopPodHandler)
+
+	handler.manager = podmgr.NewManager(handlerCtx.Session)
+}
+
+// CreatePodHandler groups existing containers into a pod
+func (handler *PodsHandlersImpl) CreatePodHandler(params pods.CreatePodParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.CreatePodHandler(%s)", params.PodConfig.Name)
+	defer trace.End(trace.Begin("CreatePodHandler", op))
+
+	p, err := handler.manager.Create(params.PodConfig.Name, params.PodConfig.Members)
+	if err != nil {
+		return pods.NewCreatePodBadRequest().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return pods.NewCreatePodOK().WithPayload(podInfo(p))
+}
+
+// ListPodsHandler lists every known pod
+func (handler *PodsHandlersImpl) ListPodsHandler(params pods.ListPodsParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.ListPodsHandler()")
+	defer trace.End(trace.Begin("ListPodsHandler", op))
+
+	all := handler.manager.List()
+	payload := make([]*models.PodInfo, 0, len(all))
+	for _, p := range all {
+		payload = append(payload, podInfo(p))
+	}
+
+	return pods.NewListPodsOK().WithPayload(payload)
+}
+
+// GetPodHandler returns a pod's configuration
+func (handler *PodsHandlersImpl) GetPodHandler(params pods.GetPodParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.GetPodHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("GetPodHandler", op))
+
+	p, ok := handler.manager.Get(params.ID)
+	if !ok {
+		return pods.NewGetPodNotFound()
+	}
+
+	return pods.NewGetPodOK().WithPayload(podInfo(p))
+}
+
+// DeletePodHandler forgets a pod grouping
+func (handler *PodsHandlersImpl) DeletePodHandler(params pods.DeletePodParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.DeletePodHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("DeletePodHandler", op))
+
+	if !handler.manager.Delete(params.ID) {
+		return pods.NewDeletePodNotFound()
+	}
+
+	return pods.NewDeletePodOK()
+}
+
+// StartPodHandler starts the pod's anchor, then each sidecar in order
+func (handler *PodsHandlersImpl) StartPodHandler(params pods.StartPodParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.StartPodHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("StartPodHandler", op))
+
+	if err := handler.manager.Start(op, params.ID); err != nil {
+		op.Errorf("StartPod error: %s", err.Error())
+		return pods.NewStartPodInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return pods.NewStartPodOK()
+}
+
+// StopPodHandler stops the pod's members in reverse order
+func (handler *PodsHandlersImpl) StopPodHandler(params pods.StopPodParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "pods.StopPodHandler(%s)", params.ID)
+	defer trace.End(trace.Begin("StopPodHandler", op))
+
+	if err := handler.manager.Stop(op, params.ID); err != nil {
+		op.Errorf("StopPod error: %s", err.Error())
+		return pods.NewStopPodInternalServerError().WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return pods.NewStopPodOK()
+}
+
+func podInfo(p *podmgr.Pod) *models.PodInfo {
+	return &models.PodInfo{
+		ID:      p.ID,
+		Name:    p.Name,
+		Members: p.Members,
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/go-openapi/runtime/middleware"
@@ -26,7 +27,9 @@ import (
 	"github.com/vmware/vic/lib/apiservers/portlayer/models"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
 	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/scopes"
+	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/lib/constants"
+	"github.com/vmware/vic/lib/portlayer/event/events"
 	"github.com/vmware/vic/lib/portlayer/exec"
 	"github.com/vmware/vic/lib/portlayer/network"
 	"github.com/vmware/vic/pkg/ip"
@@ -50,6 +53,7 @@ func (handler *ScopesHandlersImpl) Configure(api *operations.PortLayerAPI, handl
 	api.ScopesRemoveContainerHandler = scopes.RemoveContainerHandlerFunc(handler.ScopesRemoveContainer)
 	api.ScopesBindContainerHandler = scopes.BindContainerHandlerFunc(handler.ScopesBindContainer)
 	api.ScopesUnbindContainerHandler = scopes.UnbindContainerHandlerFunc(handler.ScopesUnbindContainer)
+	api.ScopesAddScopePoolHandler = scopes.AddScopePoolHandlerFunc(handler.ScopesAddPool)
 
 	handler.netCtx = network.DefaultContext
 	handler.handlerCtx = handlerCtx
@@ -166,6 +170,24 @@ func (handler *ScopesHandlersImpl) ScopesDelete(params scopes.DeleteScopeParams)
 	return scopes.NewDeleteScopeOK()
 }
 
+func (handler *ScopesHandlersImpl) ScopesAddPool(params scopes.AddScopePoolParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "ScopesAddPool(%s)", params.IDName)
+	defer trace.End(trace.Begin("ScopesAddPool", op))
+
+	s, err := handler.netCtx.AddPool(context.Background(), params.IDName, params.Pools)
+	if err != nil {
+		switch err := err.(type) {
+		case network.ResourceNotFoundError:
+			return scopes.NewAddScopePoolNotFound().WithPayload(errorPayload(err))
+
+		default:
+			return scopes.NewAddScopePoolDefault(http.StatusServiceUnavailable).WithPayload(errorPayload(err))
+		}
+	}
+
+	return scopes.NewAddScopePoolOK().WithPayload(toScopeConfig(s))
+}
+
 func (handler *ScopesHandlersImpl) ScopesListAll(params scopes.ListAllParams) middleware.Responder {
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "ScopesListAll")
 	defer trace.End(trace.Begin("ScopesListAll", op))
@@ -223,6 +245,13 @@ func (handler *ScopesHandlersImpl) ScopesAddContainer(params scopes.AddContainer
 		return scopes.NewAddContainerNotFound().WithPayload(&models.Error{Message: "container not found"})
 	}
 
+	// h.Runtime is only nil for a handle that hasn't been committed yet - i.e. this call is
+	// part of a container create rather than a docker network connect/disconnect against an
+	// already-running container. Only the former has create stages worth reporting.
+	if h.Runtime == nil {
+		exec.PublishContainerEvent(op, h.ExecConfig.ID, time.Now().UTC(), events.ContainerCreateNetworking)
+	}
+
 	err := func() error {
 		addr := params.Config.NetworkConfig.Address
 		var ip net.IP
@@ -237,22 +266,37 @@ func (handler *ScopesHandlersImpl) ScopesAddContainer(params scopes.AddContainer
 			log.Debugf("Links/Aliases: %#v", params.Config.NetworkConfig.Aliases)
 		}
 
+		var shaping *executor.ShapingConfig
+		if s := params.Config.NetworkConfig.Shaping; s != nil {
+			shaping = &executor.ShapingConfig{
+				AverageBandwidth: s.AverageBandwidth,
+				PeakBandwidth:    s.PeakBandwidth,
+				BurstSize:        s.BurstSize,
+			}
+		}
+
 		options := &network.AddContainerOptions{
 			Scope:       params.Config.NetworkConfig.NetworkName,
 			IP:          ip,
 			Aliases:     params.Config.NetworkConfig.Aliases,
 			Ports:       params.Config.NetworkConfig.Ports,
 			Nameservers: params.Config.NetworkConfig.Nameservers,
+			Shaping:     shaping,
 		}
 		return handler.netCtx.AddContainer(h, options)
 	}()
 
 	if err != nil {
-		if _, ok := err.(*network.ResourceNotFoundError); ok {
+		switch err.(type) {
+		case *network.ResourceNotFoundError:
 			return scopes.NewAddContainerNotFound().WithPayload(errorPayload(err))
-		}
 
-		return scopes.NewAddContainerInternalServerError().WithPayload(errorPayload(err))
+		case network.InvalidIPForScopeError:
+			return scopes.NewAddContainerDefault(http.StatusUnprocessableEntity).WithPayload(errorPayload(err))
+
+		default:
+			return scopes.NewAddContainerInternalServerError().WithPayload(errorPayload(err))
+		}
 	}
 
 	return scopes.NewAddContainerOK().WithPayload(h.String())
@@ -294,6 +338,9 @@ func (handler *ScopesHandlersImpl) ScopesBindContainer(params scopes.BindContain
 		case network.ResourceNotFoundError:
 			return scopes.NewBindContainerNotFound().WithPayload(errorPayload(err))
 
+		case network.IPAddressInUseError:
+			return scopes.NewBindContainerDefault(http.StatusConflict).WithPayload(errorPayload(err))
+
 		default:
 			return scopes.NewBindContainerInternalServerError().WithPayload(errorPayload(err))
 		}
@@ -400,13 +447,14 @@ func toEndpointConfig(e *network.Endpoint) *models.EndpointConfig {
 	}
 
 	ret := models.EndpointConfig{
-		Address:   addr,
-		Container: e.ID().String(),
-		ID:        e.ID().String(),
-		Name:      e.Name(),
-		Scope:     e.Scope().Name(),
-		Ports:     ecports,
-		Gateway:   e.Gateway().String(),
+		Address:    addr,
+		Container:  e.ID().String(),
+		ID:         e.ID().String(),
+		Name:       e.Name(),
+		Scope:      e.Scope().Name(),
+		Ports:      ecports,
+		Gateway:    e.Gateway().String(),
+		IPConflict: e.IPConflict(),
 	}
 
 	if e.Scope().Type() != constants.BridgeScopeType {
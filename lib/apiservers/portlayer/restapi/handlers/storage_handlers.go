@@ -16,11 +16,13 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/go-openapi/runtime/middleware"
@@ -50,14 +52,24 @@ import (
 type StorageHandlersImpl struct {
 	imageCache  *icache.NameLookupCache
 	volumeCache *vcache.VolumeLookupCache
+	handlerCtx  *HandlerContext
 }
 
+// DefaultVolumeCache is the volume store lookup cache configured by
+// StorageHandlersImpl.Configure, exposed so other handlers (e.g. bundles) can create and
+// destroy volumes without duplicating the volume store setup - mirrors network.DefaultContext.
+var DefaultVolumeCache *vcache.VolumeLookupCache
+
 const (
 	nfsScheme = "nfs"
 	dsScheme  = "ds"
 
 	uidQueryKey = "uid"
 	gidQueryKey = "gid"
+
+	// driverArgContainerKey is the VolumeRequest.DriverArgs key personalities (e.g. the docker
+	// engine api) populate with the ID of the container a volume is being created for.
+	driverArgContainerKey = "container"
 )
 
 // Configure assigns functions to all the storage api handlers
@@ -106,6 +118,7 @@ func (h *StorageHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx
 	api.StorageWriteImageHandler = storage.WriteImageHandlerFunc(h.WriteImage)
 	api.StorageImageJoinHandler = storage.ImageJoinHandlerFunc(h.ImageJoin)
 	api.StorageDeleteImageHandler = storage.DeleteImageHandlerFunc(h.DeleteImage)
+	api.StoragePrefetchImageHandler = storage.PrefetchImageHandlerFunc(h.PrefetchImage)
 
 	api.StorageVolumeStoresListHandler = storage.VolumeStoresListHandlerFunc(h.VolumeStoresList)
 	api.StorageCreateVolumeHandler = storage.CreateVolumeHandlerFunc(h.CreateVolume)
@@ -118,6 +131,9 @@ func (h *StorageHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx
 	api.StorageImportArchiveHandler = storage.ImportArchiveHandlerFunc(h.ImportArchive)
 	api.StorageStatPathHandler = storage.StatPathHandlerFunc(h.StatPath)
 	api.StorageGetImageStorageUsageHandler = storage.GetImageStorageUsageHandlerFunc(h.GetImageStorageUsage)
+	api.StorageGetStorageUsageHandler = storage.GetStorageUsageHandlerFunc(h.GetStorageUsage)
+
+	h.handlerCtx = handlerCtx
 }
 
 func (h *StorageHandlersImpl) configureVolumeStores(op trace.Operation, handlerCtx *HandlerContext) {
@@ -127,6 +143,7 @@ func (h *StorageHandlersImpl) configureVolumeStores(op trace.Operation, handlerC
 	)
 
 	h.volumeCache = vcache.NewVolumeLookupCache(op)
+	DefaultVolumeCache = h.volumeCache
 
 	// register the pseudo-store to handle the generic "volume" store name
 	spl.RegisterImporter(op, "volume", h.volumeCache)
@@ -244,6 +261,32 @@ func (h *StorageHandlersImpl) GetImage(params storage.GetImageParams) middleware
 	return storage.NewGetImageOK().WithPayload(result)
 }
 
+// PrefetchImage warms the store cache with an image's full ancestry chain, so a later
+// container create referencing it doesn't pay the datastore lookup cost on its first use.
+func (h *StorageHandlersImpl) PrefetchImage(params storage.PrefetchImageParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "PrefetchImage(%s)", params.ID)
+	defer trace.End(trace.Begin("PrefetchImage", op))
+
+	url, err := util.ImageStoreNameToURL(params.StoreName)
+	if err != nil {
+		return storage.NewPrefetchImageDefault(http.StatusInternalServerError).WithPayload(
+			&models.Error{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+	}
+
+	warmed, err := h.imageCache.Prefetch(op, url, params.ID)
+	if err != nil {
+		return storage.NewPrefetchImageNotFound().WithPayload(&models.Error{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		})
+	}
+
+	return storage.NewPrefetchImageOK().WithPayload(warmed)
+}
+
 // DeleteImage deletes an image from a store
 func (h *StorageHandlersImpl) DeleteImage(params storage.DeleteImageParams) middleware.Responder {
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "DeleteImage(%s)", params.ID)
@@ -390,7 +433,7 @@ func (h *StorageHandlersImpl) ImageJoin(params storage.ImageJoinParams) middlewa
 	}
 
 	cfg := params.Config
-	handleprime, err := vsimage.Join(op, handle, cfg.DeltaID, cfg.ImageID, cfg.RepoName, img)
+	handleprime, err := vsimage.Join(op, handle, cfg.DeltaID, cfg.ImageID, cfg.RepoName, img, cfg.IopsLimit)
 	if err != nil {
 		op.Errorf("join image failed: %#v", err)
 		return storage.NewImageJoinInternalServerError().WithPayload(&models.Error{Message: err.Error()})
@@ -437,6 +480,22 @@ func (h *StorageHandlersImpl) CreateVolume(params storage.CreateVolumeParams) mi
 		})
 	}
 
+	// If the volume is destined for a specific container, try to keep its VMDK on the same
+	// datastore as that container's VM files - this keeps IO local and keeps the volume in the
+	// same SRM/replication group as the container. Best-effort: if the container can't be
+	// resolved, or no configured volume store lives on its datastore, fall back to the
+	// requested store unchanged.
+	if cid := params.VolumeRequest.DriverArgs[driverArgContainerKey]; cid != "" {
+		if ch, cherr := epl.ResolveContainer(context.Background(), cid); cherr == nil && ch != nil {
+			if ds, dserr := ch.DatastoreName(); dserr == nil {
+				if affineURL, ok := h.volumeCache.VolumeStoreOnDatastore(op, ds); ok {
+					op.Infof("placing volume %s on datastore %s to keep it local to container %s", params.VolumeRequest.Name, ds, cid)
+					storeURL = affineURL
+				}
+			}
+		}
+	}
+
 	byteMap := make(map[string][]byte)
 	for key, value := range params.VolumeRequest.Metadata {
 		byteMap[key] = []byte(value)
@@ -530,6 +589,51 @@ func (h *StorageHandlersImpl) RemoveVolume(params storage.RemoveVolumeParams) mi
 	return storage.NewRemoveVolumeOK()
 }
 
+// dockerMetadataKey is the Volume.Info key under which the personality layer stashes a json
+// blob of docker-level volume metadata (see DockerMetadataModelKey in the engine backend's
+// storage proxy) - this must stay in sync with that constant.
+const dockerMetadataKey = "DockerMetaData"
+
+// volumeDockerMetadata is the subset of the engine backend's VolumeMetadata that the portlayer
+// itself needs to decode, e.g. to support filtering volumes by docker label. It is duplicated
+// here rather than imported because the portlayer must not depend on the engine backend.
+type volumeDockerMetadata struct {
+	Labels map[string]string
+}
+
+// volumeLabels extracts the docker labels stashed on a volume at creation time, if any.
+func volumeLabels(v *volume.Volume) map[string]string {
+	raw, ok := v.Info[dockerMetadataKey]
+	if !ok {
+		return nil
+	}
+
+	var md volumeDockerMetadata
+	if err := json.Unmarshal(raw, &md); err != nil {
+		return nil
+	}
+
+	return md.Labels
+}
+
+// matchesLabelFilter reports whether filterString, in docker's "label=key=value" form,
+// matches the given labels. An empty filterString always matches.
+func matchesLabelFilter(labels map[string]string, filterString string) bool {
+	if filterString == "" {
+		return true
+	}
+
+	parts := strings.SplitN(filterString, "=", 3)
+	if len(parts) != 3 || parts[0] != "label" {
+		// only label filters are understood at this layer - anything else is left
+		// unfiltered rather than rejected, consistent with filterString historically
+		// being accepted but unused.
+		return true
+	}
+
+	return labels[parts[1]] == parts[2]
+}
+
 //VolumesList : Lists available volumes for use
 func (h *StorageHandlersImpl) VolumesList(params storage.ListVolumesParams) middleware.Responder {
 	op := trace.NewOperationFromID(context.Background(), params.OpID, "VolumesList")
@@ -547,7 +651,20 @@ func (h *StorageHandlersImpl) VolumesList(params storage.ListVolumesParams) midd
 
 	op.Debugf("volumes fetched from list call : %#v", portlayerVolumes)
 
+	var filterString string
+	if params.FilterString != nil {
+		filterString = *params.FilterString
+	}
+
 	for i := range portlayerVolumes {
+		// filter by docker label, e.g. "label=com.vmware.vic.project=teamA" - this is how a
+		// caller scopes ListVolumes to a single tenant's volumes. Scope/network and image
+		// store tenancy are not addressed here; they have no equivalent per-resource label
+		// to filter on today.
+		if !matchesLabelFilter(volumeLabels(portlayerVolumes[i]), filterString) {
+			continue
+		}
+
 		model, err := fillVolumeModel(portlayerVolumes[i])
 		if err != nil {
 			op.Error(err)
@@ -754,6 +871,46 @@ func (h *StorageHandlersImpl) GetImageStorageUsage(params storage.GetImageStorag
 	return storage.NewGetImageStorageUsageOK().WithPayload(cachedResult)
 }
 
+// GetStorageUsage aggregates image, container and volume disk usage for this VCH,
+// roughly equivalent to `docker system df`. Volume usage isn't tracked at a
+// per-byte level yet, so only a volume count is reported for now.
+func (h *StorageHandlersImpl) GetStorageUsage(params storage.GetStorageUsageParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "GetStorageUsage")
+	defer trace.End(trace.Begin("GetStorageUsage", op))
+
+	imageBytes := h.imageCache.ImageStorageUsage()
+	if imageBytes < 0 {
+		op.Warnf("Image storage usage is not cached, reporting 0")
+		imageBytes = 0
+	}
+
+	containerUsage, err := epl.ContainerStorageUsage(op, h.handlerCtx.Session)
+	if err != nil {
+		op.Errorf("Error getting container disk usage: %s", err)
+		return storage.NewGetStorageUsageDefault(500).WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	var containerBytes int64
+	containerByID := make(map[string]int64, len(containerUsage))
+	for id, bytes := range containerUsage {
+		containerByID[id] = bytes
+		containerBytes += bytes
+	}
+
+	volumes, err := h.volumeCache.VolumesList(op)
+	if err != nil {
+		op.Errorf("Error listing volumes: %s", err)
+		return storage.NewGetStorageUsageDefault(500).WithPayload(&models.Error{Message: err.Error()})
+	}
+
+	return storage.NewGetStorageUsageOK().WithPayload(&models.StorageUsage{
+		ImageStorageBytes:     imageBytes,
+		ContainerStorageBytes: containerBytes,
+		ContainerStorageByID:  containerByID,
+		VolumeCount:           int64(len(volumes)),
+	})
+}
+
 //utility functions
 
 // convert an SPL Image to a swagger-defined Image
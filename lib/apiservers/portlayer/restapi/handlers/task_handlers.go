@@ -16,6 +16,7 @@ package handlers
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	"github.com/go-openapi/runtime/middleware"
@@ -69,6 +70,11 @@ func (handler *TaskHandlersImpl) JoinHandler(params tasks.JoinParams) middleware
 
 	op.Debugf("User: %s", params.Config.User)
 
+	if err := validateSessionEncoding(params.Config.Path, params.Config.Args, params.Config.Env); err != nil {
+		op.Errorf("rejecting session join: %s", err.Error())
+		return tasks.NewJoinDefault(http.StatusBadRequest).WithPayload(&models.Error{Message: err.Error()})
+	}
+
 	sessionConfig := &executor.SessionConfig{
 		Common: executor.Common{
 			ID: id,
@@ -0,0 +1,81 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/models"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations"
+	"github.com/vmware/vic/lib/apiservers/portlayer/restapi/operations/templates"
+	tmpl "github.com/vmware/vic/lib/portlayer/templates"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// TemplatesHandlersImpl is the receiver for all of the container template api handlers
+type TemplatesHandlersImpl struct{}
+
+// Configure assigns functions to all the templates api handlers
+func (handler *TemplatesHandlersImpl) Configure(api *operations.PortLayerAPI, handlerCtx *HandlerContext) {
+	api.TemplatesListTemplatesHandler = templates.ListTemplatesHandlerFunc(handler.ListTemplatesHandler)
+	api.TemplatesSaveTemplateHandler = templates.SaveTemplateHandlerFunc(handler.SaveTemplateHandler)
+	api.TemplatesGetTemplateHandler = templates.GetTemplateHandlerFunc(handler.GetTemplateHandler)
+	api.TemplatesDeleteTemplateHandler = templates.DeleteTemplateHandlerFunc(handler.DeleteTemplateHandler)
+}
+
+// ListTemplatesHandler lists the name of every stored template
+func (handler *TemplatesHandlersImpl) ListTemplatesHandler(params templates.ListTemplatesParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "templates.ListTemplatesHandler()")
+	defer trace.End(trace.Begin("ListTemplatesHandler", op))
+
+	return templates.NewListTemplatesOK().WithPayload(tmpl.List())
+}
+
+// SaveTemplateHandler stores a template under name, replacing any existing template of that name
+func (handler *TemplatesHandlersImpl) SaveTemplateHandler(params templates.SaveTemplateParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "templates.SaveTemplateHandler(%s)", params.Name)
+	defer trace.End(trace.Begin("SaveTemplateHandler", op))
+
+	tmpl.Save(params.Name, params.CreateConfig)
+
+	return templates.NewSaveTemplateOK()
+}
+
+// GetTemplateHandler returns the create config stored under name
+func (handler *TemplatesHandlersImpl) GetTemplateHandler(params templates.GetTemplateParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "templates.GetTemplateHandler(%s)", params.Name)
+	defer trace.End(trace.Begin("GetTemplateHandler", op))
+
+	cc, ok := tmpl.Get(params.Name)
+	if !ok {
+		return templates.NewGetTemplateNotFound().WithPayload(&models.Error{Message: "template not found"})
+	}
+
+	return templates.NewGetTemplateOK().WithPayload(cc)
+}
+
+// DeleteTemplateHandler removes the template stored under name
+func (handler *TemplatesHandlersImpl) DeleteTemplateHandler(params templates.DeleteTemplateParams) middleware.Responder {
+	op := trace.NewOperationFromID(context.Background(), params.OpID, "templates.DeleteTemplateHandler(%s)", params.Name)
+	defer trace.End(trace.Begin("DeleteTemplateHandler", op))
+
+	if !tmpl.Delete(params.Name) {
+		return templates.NewDeleteTemplateNotFound().WithPayload(&models.Error{Message: "template not found"})
+	}
+
+	return templates.NewDeleteTemplateOK()
+}
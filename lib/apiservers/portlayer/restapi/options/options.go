@@ -27,6 +27,43 @@ type PortLayerOptionsType struct {
 	ClusterPath    string `long:"cluster" default:"" description:"Cluster path" env:"CS_PATH" required:"true"`
 	PoolPath       string `long:"pool" default:"" description:"Resource pool path" env:"POOL_PATH" required:"true"`
 	DatastorePath  string `long:"datastore" default:"/ha-datacenter/datastore/*" description:"Datastore path" env:"DS_PATH" required:"true"`
+
+	// TLSCertificate and TLSPrivateKey duplicate the paths already handed to the generated
+	// server on the command line. Keeping them here as well lets configureTLS re-read the
+	// same files on SIGHUP so a rotated server certificate can be picked up without
+	// restarting the port layer.
+	TLSCertificate string `long:"tls-certificate" description:"Server certificate file, watched for rotation" env:"PORTLAYER_TLS_CERTIFICATE"`
+	TLSPrivateKey  string `long:"tls-key" description:"Server private key file, watched for rotation" env:"PORTLAYER_TLS_KEY"`
+
+	// ReadOnlyListener, if set, is a second address the port layer listens on that
+	// only serves GET/HEAD requests - a way for monitoring systems to scrape
+	// container/job/pod list and inspect state without being granted mutating
+	// access to, or sharing rate limits with, the primary API.
+	ReadOnlyListener string `long:"read-only-listener" description:"Address for a second, read-only listener (e.g. 127.0.0.1:2379)" env:"PORTLAYER_READ_ONLY_LISTENER"`
+
+	// OperationTimeout bounds how long a single vSphere task (VM create, power on, etc.)
+	// invoked on behalf of a request is allowed to run before the handler gives up and
+	// returns an error to the caller, rather than blocking indefinitely on a slow or
+	// disconnected vCenter. The vSphere-side task is not itself canceled - only the
+	// client's wait for it.
+	OperationTimeout time.Duration `long:"operation-timeout" default:"5m" description:"Maximum time to wait on a single vSphere task before giving up" env:"PORTLAYER_OPERATION_TIMEOUT"`
+
+	// ConnectorURI overrides the address embedded in a container VM's serial port
+	// backing that it dials back to reach this port layer's attach server. Empty
+	// means the default of tcp://127.0.0.1:<AttachServerPort>, which only works
+	// when the attach server is co-resident with the port layer on that exact
+	// port; set this explicitly in a split or multi-VCH deployment.
+	ConnectorURI string `long:"connector-uri" description:"Address container VMs dial back to for interaction, e.g. tcp://10.0.0.5:9000" env:"PORTLAYER_CONNECTOR_URI"`
+
+	// AuthStaticToken, if set, is one or more "token=subject" pairs the API accepts as a
+	// bearer credential on top of the mutual-TLS handshake already required to reach it.
+	// Repeat the flag for more than one token.
+	AuthStaticToken []string `long:"auth-static-token" description:"Accept a bearer token, as token=subject, in addition to client certificate auth" env:"PORTLAYER_AUTH_STATIC_TOKEN"`
+
+	// AuthProviderURL, if set, points at an operator-run service that authenticates bearer
+	// credentials on the API's behalf - the integration point for LDAP/AD or any other
+	// identity system, since the port layer doesn't vendor a directory client itself.
+	AuthProviderURL string `long:"auth-provider-url" description:"URL of an external service to authenticate bearer credentials against (e.g. an LDAP/AD bridge)" env:"PORTLAYER_AUTH_PROVIDER_URL"`
 }
 
 var (
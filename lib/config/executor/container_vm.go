@@ -99,6 +99,11 @@ type Diagnostics struct {
 	// SyslogConfig holds configuration for connecting to a syslog
 	// server
 	SysLogConfig *SysLogConfig `vic:"0.1" scope:"read-only" key:"syslog"`
+
+	// EnableCoreDumps, if set, has the tether lift the session processes' core ulimit and
+	// point the guest kernel's core pattern at CoreDumpDir so a crashed session process
+	// leaves a core file behind for post-mortem debugging instead of just an exit status.
+	EnableCoreDumps bool `vic:"0.1" scope:"read-only" key:"enable_core_dumps"`
 }
 
 // SyslogConfig holds the configuration necessary to connect to a syslog server
@@ -107,6 +112,9 @@ type SysLogConfig struct {
 	Network string
 	// RAddr is the remote address of the syslog endpoint
 	RAddr string
+	// Format selects the message framing used on the wire, e.g. "rfc3164" or "rfc5424".
+	// Empty defaults to rfc3164.
+	Format string
 }
 
 // ExitLog records some basic diagnostics about anomalous exit for restartable entities
@@ -195,9 +203,34 @@ type ExecutorConfig struct {
 	// Image id that is backing this container VM
 	ImageID string `vic:"0.1" scope:"read-only" key:"imageid"`
 
+	// BootstrapVersion pins the bootstrap ISO version this container VM was created with,
+	// so a rolling upgrade of the VCH's bootstrap ISO doesn't change what an existing
+	// container boots from on its next power-on. Empty means the VCH's current default
+	// (see config.Container.BootstrapImagePath).
+	BootstrapVersion string `vic:"0.1" scope:"read-only" key:"bootstrap_version"`
+
 	// Blob metadata for the caller
 	Annotations map[string]string `vic:"0.1" scope:"hidden" key:"annotations"`
 
+	// DependsOn lists the IDs of other containers that must be running before this one
+	// is started during a batch start (see lib/portlayer/exec.BatchStart). It has no
+	// effect on an individual container start.
+	DependsOn []string `vic:"0.1" scope:"read-only" key:"depends_on"`
+
+	// AutoStart marks this container to be powered on automatically once the VCH has
+	// finished reconstructing its container cache at boot (see lib/portlayer/exec.AutoStart).
+	AutoStart bool `vic:"0.1" scope:"read-only" key:"auto_start"`
+
+	// StartOrder groups auto-started containers into ascending boot waves - containers
+	// with a lower StartOrder are started, and reach StateRunning, before containers
+	// with a higher StartOrder are started. Containers sharing a StartOrder are started
+	// concurrently.
+	StartOrder int `vic:"0.1" scope:"read-only" key:"start_order"`
+
+	// StartDelay is how long, in seconds, to wait after a StartOrder wave reaches
+	// StateRunning before starting the next wave.
+	StartDelay int `vic:"0.1" scope:"read-only" key:"start_delay"`
+
 	// Repository requested by user
 	// TODO: a bit docker specific
 	RepoName string `vic:"0.1" scope:"read-only" key:"repo"`
@@ -211,6 +244,21 @@ type ExecutorConfig struct {
 	// Hostname and domainname provided by personality
 	Hostname   string `vic:"0.1" scope:"read-only" key:"hostname"`
 	Domainname string `vic:"0.1" scope:"read-only" key:"domainname"`
+
+	// ReapOrphans controls whether the tether, acting as pid 1, marks itself as a child
+	// subreaper so that orphaned grandchildren are reparented to it for reaping rather than
+	// to the guest's actual init. Defaults to true; corresponds to docker's --init flag,
+	// which is otherwise a no-op here since the tether already fulfills that role.
+	ReapOrphans bool `vic:"0.1" scope:"read-only" key:"reaporphans"`
+
+	// Deadline is the unix time, in seconds, at which this container is automatically
+	// stopped by the portlayer's deadline reaper (see lib/portlayer/exec.StartDeadlineReaper).
+	// Zero means no deadline.
+	Deadline int64 `vic:"0.1" scope:"read-only" key:"deadline"`
+
+	// RemoveOnDeadline additionally removes the container, rather than just stopping it,
+	// once Deadline passes.
+	RemoveOnDeadline bool `vic:"0.1" scope:"read-only" key:"removeondeadline"`
 }
 
 // Cmd is here because the encoding packages seem to have issues with the full exec.Cmd struct
@@ -258,6 +306,12 @@ type SessionConfig struct {
 
 	Restart bool `vic:"0.1" scope:"read-only" key:"restart"`
 
+	// RestartPolicy refines how Restart is applied - whether it's unconditional or
+	// contingent on the session having exited with a failure, and how many times it
+	// may be retried. An empty RestartPolicy.Name preserves the historical behavior of
+	// restarting unconditionally while Restart is true.
+	RestartPolicy RestartConfig `vic:"0.1" scope:"read-only" key:"restartpolicy"`
+
 	// StopSignal is the signal name or number used to stop container session
 	StopSignal string `vic:"0.1" scope:"read-only" key:"stopSignal"`
 
@@ -283,6 +337,19 @@ type SessionConfig struct {
 	Detail `vic:"0.1" scope:"read-write" key:"detail"`
 }
 
+// RestartConfig describes the policy the tether uses to decide whether a session's process
+// should be relaunched after it exits. It mirrors docker's restart policy names but is only
+// consulted while the owning SessionConfig's Restart flag is set.
+type RestartConfig struct {
+	// Name is the restart policy: "" or "always" restarts unconditionally, "on-failure"
+	// restarts only on a non-zero exit status
+	Name string `vic:"0.1" scope:"read-only" key:"name"`
+
+	// MaximumRetryCount caps the number of restarts under the "on-failure" policy. Zero
+	// means unlimited.
+	MaximumRetryCount int `vic:"0.1" scope:"read-only" key:"maxretry"`
+}
+
 type Detail struct {
 
 	// creation, started & stopped timestamps
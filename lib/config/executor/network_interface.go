@@ -47,6 +47,25 @@ type NetworkEndpoint struct {
 
 	// whether or not this represents an internal network
 	Internal bool `vic:"0.1" scope:"read-only" key:"internal"`
+
+	// Shaping, if set, applies traffic shaping to this endpoint for fault-injection
+	// testing (e.g. simulating a constrained network link). May be nil.
+	Shaping *ShapingConfig `vic:"0.1" scope:"read-only" key:"shaping"`
+}
+
+// ShapingConfig describes bandwidth-limiting traffic shaping to apply to a container's
+// network endpoint. vSphere's traffic shaping only throttles bandwidth - there is no
+// native mechanism for injecting latency or packet loss on a VM's virtual network port,
+// so those are not modeled here even though they're often what "fault injection" implies.
+type ShapingConfig struct {
+	// AverageBandwidth is the allowed average bandwidth, in bits per second.
+	AverageBandwidth int64 `vic:"0.1" scope:"read-only" key:"average_bandwidth"`
+
+	// PeakBandwidth is the maximum bandwidth, in bits per second, allowed during a burst.
+	PeakBandwidth int64 `vic:"0.1" scope:"read-only" key:"peak_bandwidth"`
+
+	// BurstSize is the maximum size, in bytes, of a burst allowed at PeakBandwidth.
+	BurstSize int64 `vic:"0.1" scope:"read-only" key:"burst_size"`
 }
 
 // ContainerNetwork is the data needed on a per container basis both for vSphere to ensure it's attached
@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"net"
 	"net/mail"
 	"net/url"
@@ -39,6 +40,8 @@ const (
 	IDToken PatternToken = "{id}"
 	// Name is the container name of the VM
 	NameToken PatternToken = "{name}"
+	// VCH is the name of the VCH owning the VM, for enforcing inventory naming conventions across VCHs
+	VCHToken PatternToken = "{vch}"
 
 	// The default naming pattern that gets applied if no convention is supplied
 	DefaultNamePattern = "{name}-{id}"
@@ -126,8 +129,12 @@ type Container struct {
 	ContainerVMSize Resources `vic:"0.1" scope:"read-only" recurse:"depth=0"`
 	// Resource pools under which all containers will be created
 	ComputeResources []types.ManagedObjectReference `vic:"0.1" scope:"read-only"`
-	// Path of the ISO to use for bootstrapping containers
+	// Path of the ISO to use for bootstrapping containers that don't request a specific version
 	BootstrapImagePath string `vic:"0.1" scope:"read-only" key:"bootstrap_image_path"`
+	// Paths of additional bootstrap ISO versions kept side-by-side with BootstrapImagePath, keyed
+	// by tether version string. Lets a container pin the bootstrap ISO it was created with across
+	// a rolling upgrade of the VCH, rather than being forced onto whatever ISO is now current.
+	BootstrapImagePaths map[string]string `vic:"0.1" scope:"read-only" key:"bootstrap_image_paths"`
 	// Allow custom naming convention for containerVMs
 	ContainerNameConvention string
 	// Whether to create and manage a DRS VM Group for the VCH and its containerVMs
@@ -138,6 +145,82 @@ type Container struct {
 	ContainerStores []url.URL `vic:"0.1" scope:"read-only" recurse:"depth=0"`
 	// Total number of containers that can exist in this virtual container host
 	ContainerCount int `vic:"0.1" scope:"read-only" key:"container_count"`
+	// SyncContainerLabels names the docker labels that should be mirrored onto the container
+	// VM as vSphere custom fields, so vSphere-side automation (backup policies, compliance
+	// scans) can target containers by label. Empty means no mirroring is performed.
+	SyncContainerLabels []string `vic:"0.1" scope:"read-only" key:"sync_container_labels"`
+	// ProjectQuotas holds admission limits keyed by the value of the project label (see
+	// ProjectLabelKey in the engine backend), for VCHs shared across multiple projects/tenants.
+	// A project with no entry here is unlimited.
+	ProjectQuotas map[string]ProjectQuota `vic:"0.1" scope:"read-only" recurse:"depth=2"`
+	// NATIPPool lists the external IPs a container's port mappings may be pinned to via the
+	// NatIPLabelKey label in the engine backend, for users whose upstream services do IP-based
+	// allow-listing. Empty means no pinning is permitted; requests then fall back to the
+	// default MASQUERADE behavior.
+	NATIPPool []string `vic:"0.1" scope:"read-only" key:"nat_ip_pool"`
+	// GuestInfoPrefix overrides the default "vice." extraConfig/guestinfo key namespace used by
+	// this VCH and its containerVMs, so multiple VIC versions or other tooling sharing the same
+	// VMs don't collide. Empty means use extraconfig.DefaultPrefix. The tether reads this same
+	// setting via a fixed, unprefixed guestinfo key (see extraconfig.PrefixOverrideKey) before it
+	// decodes the rest of its own configuration.
+	GuestInfoPrefix string `vic:"0.1" scope:"read-only" key:"guestinfo_prefix"`
+	// WebhookURL, if set, is the HTTP endpoint container lifecycle events are POSTed to as
+	// they occur, so external orchestrators and chatops tooling can react without polling
+	// the portlayer API. Empty means no webhook is configured.
+	WebhookURL string `vic:"0.1" scope:"read-only" key:"webhook_url"`
+	// WebhookSecret, if set, is used to sign webhook deliveries with HMAC-SHA256 (see
+	// webhook.SignatureHeader) so the receiver can authenticate them.
+	WebhookSecret string `vic:"0.1" scope:"secret" key:"webhook_secret"`
+	// AdmissionHookURL, if set, is an HTTP endpoint consulted before every container
+	// create; it may reject the create or clamp its resource requests (see
+	// lib/portlayer/admission). Empty means every create is unconditionally allowed.
+	AdmissionHookURL string `vic:"0.1" scope:"read-only" key:"admission_hook_url"`
+	// DefaultNumCPUs is used for a container create that doesn't specify a vCPU count.
+	// Zero means fall back to the port layer's own built-in default.
+	DefaultNumCPUs int64 `vic:"0.1" scope:"read-only" key:"default_num_cpus"`
+	// DefaultMemoryMB is used for a container create that doesn't specify a memory size.
+	// Zero means fall back to the port layer's own built-in default.
+	DefaultMemoryMB int64 `vic:"0.1" scope:"read-only" key:"default_memory_mb"`
+	// MaxContainerNumCPUs caps the vCPU count a single container create may request.
+	// Zero means unlimited.
+	MaxContainerNumCPUs int64 `vic:"0.1" scope:"read-only" key:"max_container_num_cpus"`
+	// MaxContainerMemoryMB caps the memory size a single container create may request.
+	// Zero means unlimited.
+	MaxContainerMemoryMB int64 `vic:"0.1" scope:"read-only" key:"max_container_memory_mb"`
+	// MaxConcurrentCreates caps how many CreateVM/PowerOn tasks this VCH will have
+	// outstanding against vCenter at once; additional creates queue in arrival order
+	// until a slot frees up. Zero means unlimited, matching prior behavior.
+	MaxConcurrentCreates int `vic:"0.1" scope:"read-only" key:"max_concurrent_creates"`
+	// MinDatastoreFreeSpaceMB is the free space an image datastore must retain for a
+	// container create to be allowed to proceed against it. Zero disables the check,
+	// matching prior behavior.
+	MinDatastoreFreeSpaceMB int64 `vic:"0.1" scope:"read-only" key:"min_datastore_free_space_mb"`
+}
+
+// BootstrapImage returns the datastore path of the bootstrap ISO for the given tether
+// version. An empty version, or one with no entry in BootstrapImagePaths, resolves to
+// BootstrapImagePath - the ISO current containers are created with.
+func (c *Container) BootstrapImage(version string) (string, error) {
+	if version == "" {
+		return c.BootstrapImagePath, nil
+	}
+
+	if path, ok := c.BootstrapImagePaths[version]; ok {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("bootstrap ISO version %q is not available on this VCH", version)
+}
+
+// ProjectQuota caps the resources a single project/tenant may consume on a shared VCH.
+// Zero means unlimited for that dimension.
+type ProjectQuota struct {
+	// MaxContainers is the maximum number of containers the project may have running or created
+	MaxContainers int
+	// MaxCPUs is the maximum total vCPUs summed across the project's containers
+	MaxCPUs int64
+	// MaxMemoryMB is the maximum total memory in MB summed across the project's containers
+	MaxMemoryMB int64
 }
 
 // RegistryConfig defines the registries virtual container host can talk to
@@ -148,6 +231,10 @@ type Registry struct {
 	RegistryBlacklist []string `vic:"0.1" scope:"read-only" recurse:"depth=0"`
 	// Insecure registries
 	InsecureRegistries []string `vic:"0.1" scope:"read-only" key:"insecure_registries"`
+	// ContentTrustDigests, if non-empty, is the only set of image manifest digests this
+	// VCH will pull and write to its image store - any other digest is refused, so a
+	// container can never be created from an unvetted parent image.
+	ContentTrustDigests []string `vic:"0.1" scope:"read-only" key:"content_trust_digests"`
 }
 
 // NetworkConfig defines the network configuration of virtual container host
@@ -160,6 +247,10 @@ type Network struct {
 	BridgeIPRange *net.IPNet `vic:"0.1" scope:"read-only" key:"bridge-ip-range"`
 	// The width of each new bridge network
 	BridgeNetworkWidth *net.IPMask `vic:"0.1" scope:"read-only" key:"bridge-net-width"`
+	// IPAMHookURL, if set, is an HTTP endpoint notified whenever a container endpoint is
+	// allocated or released, so an external IPAM/DNS system (e.g. Infoblox) can be kept in
+	// sync with container IPs and names. Empty means no notification is sent.
+	IPAMHookURL string `vic:"0.1" scope:"read-only" key:"ipam_hook_url"`
 }
 
 // StorageConfig defines the storage configuration including images and volumes
@@ -84,6 +84,11 @@ const (
 	TaskCreatedState = "created"
 	TaskFailedState  = "failed"
 	TaskUnknownState = "unknown"
+
+	// PortlayerLockPath is advisory-locked for the lifetime of the port layer process, so a
+	// second instance started against the same appliance (e.g. by an operator error during
+	// upgrade) fails fast instead of racing the first for the vSphere session and inventory.
+	PortlayerLockPath = "/var/run/portlayer.lock"
 )
 
 func DefaultAltVCHGuestName() string {
@@ -541,6 +541,76 @@ func (s *Server) HandleVIC(w mdns.ResponseWriter, r *mdns.Msg) (bool, error) {
 	return true, nil
 }
 
+// ptrQuestionToIP parses a PTR question name of the form "d.c.b.a.in-addr.arpa." back into
+// the IPv4 address it encodes. It is the inverse of reverseaddr for the IPv4 case.
+func ptrQuestionToIP(name string) net.IP {
+	name = strings.TrimSuffix(name, ".")
+	if !strings.HasSuffix(name, ".in-addr.arpa") {
+		// IPv6 PTR (ip6.arpa) isn't handled internally - VIC's bridge networks are IPv4 only.
+		return nil
+	}
+
+	octets := strings.Split(strings.TrimSuffix(name, ".in-addr.arpa"), ".")
+	if len(octets) != 4 {
+		return nil
+	}
+
+	// octets are reversed in the arpa name
+	reversed := fmt.Sprintf("%s.%s.%s.%s", octets[3], octets[2], octets[1], octets[0])
+	return net.ParseIP(reversed)
+}
+
+// HandlePTR answers a reverse DNS lookup for a container's IP with its VIC-assigned name,
+// mirroring the forward lookups in HandleVIC.
+func (s *Server) HandlePTR(w mdns.ResponseWriter, r *mdns.Msg) (bool, error) {
+	defer trace.End(trace.Begin(""))
+
+	question := r.Question[0]
+
+	netCtx := network.DefaultContext
+	if netCtx == nil {
+		log.Errorf("DefaultContext is not initialized")
+		return false, fmt.Errorf("DefaultContext is not initialized")
+	}
+
+	ip := ptrQuestionToIP(question.Name)
+	if ip == nil {
+		return false, fmt.Errorf("could not parse PTR question %q", question.Name)
+	}
+
+	e := netCtx.ContainerByAddr(ip)
+	if e == nil {
+		return false, fmt.Errorf("could not find a container with ip %s", ip)
+	}
+
+	m := &mdns.Msg{
+		MsgHdr: mdns.MsgHdr{
+			Authoritative:      true,
+			RecursionAvailable: true,
+		},
+		Compress: true,
+	}
+	m.SetReply(r)
+
+	m.Answer = append(m.Answer, &mdns.PTR{
+		Hdr: mdns.RR_Header{
+			Name:   question.Name,
+			Rrtype: mdns.TypePTR,
+			Class:  mdns.ClassINET,
+			Ttl:    uint32(DefaultTTL.Seconds()),
+		},
+		Ptr: fmt.Sprintf("%s.%s.", e.Container().Name(), e.Scope().Name()),
+	})
+
+	if err := w.WriteMsg(m); err != nil {
+		log.Errorf("Error writing response, %s", err)
+		return true, err
+	}
+	w.Close()
+
+	return true, nil
+}
+
 // ServeDNS implements the handler interface
 func (s *Server) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
 	defer trace.End(trace.Begin(""))
@@ -577,7 +647,7 @@ func (s *Server) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
 	}
 
 	// Check VIC first
-	// Currently VIC can only answer ipv4 "A" queries
+	// Currently VIC can only answer ipv4 "A" and PTR queries
 	if q.Qtype == mdns.TypeA {
 		ok, err := s.HandleVIC(w, r)
 		if ok {
@@ -588,6 +658,16 @@ func (s *Server) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
 		}
 	}
 
+	if q.Qtype == mdns.TypePTR {
+		ok, err := s.HandlePTR(w, r)
+		if ok {
+			if err != nil {
+				log.Errorf("HandlePTR returned: %q", err)
+			}
+			return
+		}
+	}
+
 	// Do we have the response in our cache?
 	ok, err := s.SeenBefore(w, r)
 	if ok {
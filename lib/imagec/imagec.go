@@ -113,6 +113,11 @@ type Options struct {
 	// If true, do not bother portlayer or persona
 	Standalone bool
 
+	// ContentTrustDigests, if non-empty, is the only set of manifest digests that may
+	// be pulled - any other digest is refused before its layers are downloaded and
+	// written to the image store.
+	ContentTrustDigests []string
+
 	// image store name or url
 	ImageStore string
 }
@@ -496,6 +501,10 @@ func (ic *ImageC) PullImage(ctx context.Context) error {
 	}
 	log.Infof("Manifest for image = %#v", ic.ImageManifestSchema1)
 
+	if err := ic.verifyContentTrust(); err != nil {
+		return err
+	}
+
 	// Get layers to download from manifest
 	layers, err := ic.LayersToDownload(op)
 	if err != nil {
@@ -511,6 +520,25 @@ func (ic *ImageC) PullImage(ctx context.Context) error {
 	return nil
 }
 
+// verifyContentTrust enforces the content trust policy, if one is configured. It's a
+// simple checksum-manifest style of trust rather than full Notary/DCT: an operator
+// vets an image once, out of band, and records its manifest digest; from then on
+// only that exact digest may be pulled, so a compromised or retagged upstream image
+// can never end up as a running container's parent.
+func (ic *ImageC) verifyContentTrust() error {
+	if len(ic.Options.ContentTrustDigests) == 0 {
+		return nil
+	}
+
+	for _, trusted := range ic.Options.ContentTrustDigests {
+		if trusted == ic.ManifestDigest {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("content trust: manifest digest %s for %s is not in the trusted digest list", ic.ManifestDigest, ic.Image)
+}
+
 // ListLayer prints out the layers for an image to progress.  This is used by imagec standalone binary
 // for debug/validation.
 func (ic *ImageC) ListLayers(ctx context.Context) error {
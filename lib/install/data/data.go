@@ -65,6 +65,7 @@ type Data struct {
 
 	InsecureRegistries  []string `cmd:"insecure-registry"`
 	WhitelistRegistries []string `cmd:"whitelist-registry"`
+	ContentTrustDigests []string `cmd:"content-trust-digest"`
 
 	HTTPSProxy *url.URL `cmd:"https-proxy"`
 	HTTPProxy  *url.URL `cmd:"http-proxy"`
@@ -376,6 +377,14 @@ func (d *Data) CopyNonEmpty(src *Data) error {
 	d.SyslogConfig = src.SyslogConfig
 
 	d.ContainerConfig.ContainerNameConvention = src.ContainerConfig.ContainerNameConvention
+	d.ContainerConfig.GuestInfoPrefix = src.ContainerConfig.GuestInfoPrefix
+	d.ContainerConfig.WebhookURL = src.ContainerConfig.WebhookURL
+	d.ContainerConfig.WebhookSecret = src.ContainerConfig.WebhookSecret
+	d.ContainerConfig.AdmissionHookURL = src.ContainerConfig.AdmissionHookURL
+	d.ContainerConfig.DefaultNumCPUs = src.ContainerConfig.DefaultNumCPUs
+	d.ContainerConfig.DefaultMemoryMB = src.ContainerConfig.DefaultMemoryMB
+	d.ContainerConfig.MaxContainerNumCPUs = src.ContainerConfig.MaxContainerNumCPUs
+	d.ContainerConfig.MaxContainerMemoryMB = src.ContainerConfig.MaxContainerMemoryMB
 
 	return nil
 }
@@ -301,6 +301,24 @@ func (v *Validator) firewallEnabled(op trace.Operation, host *object.HostSystem)
 	return false, nil
 }
 
+// enableMatchingRuleset looks for a ruleset that matches requiredRule but is disabled, and enables
+// it via the ESXi firewall system. It returns true if a disabled ruleset was found and enabled.
+func (v *Validator) enableMatchingRuleset(op trace.Operation, fs *object.HostFirewallSystem, rs object.HostFirewallRulesetList, requiredRule types.HostFirewallRule) (bool, error) {
+	disabled := rs.ByRule(requiredRule).Disabled()
+	if len(disabled) == 0 {
+		return false, nil
+	}
+
+	for _, ruleset := range disabled {
+		op.Infof("Enabling firewall ruleset %q to permit tether traffic", ruleset.Key)
+		if err := fs.EnableRuleset(op, ruleset.Key); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 // getMgmtIP finds the management network IP in config
 func (v *Validator) getMgmtIP(conf *config.VirtualContainerHostConfigSpec) net.IPNet {
 	var mgmtIP net.IPNet
@@ -339,7 +357,21 @@ func (v *Validator) managementNetAllowed(ctx context.Context, mgmtIP net.IPNet,
 	rs := object.HostFirewallRulesetList(info.Ruleset)
 	filteredRules, err := rs.EnabledByRule(requiredRule, true) // find matching rules that are enabled
 	if err != nil {                                            // rule not enabled (fw is misconfigured)
-		return false, &firewallMisconfiguredError{Host: host.InventoryPath, Rule: requiredRule}
+		// the ruleset that matches this rule exists but is disabled - enable it
+		// automatically rather than forcing the admin to configure the host by hand.
+		if enabled, eerr := v.enableMatchingRuleset(op, fs, rs, requiredRule); eerr != nil {
+			op.Warnf("Unable to automatically enable firewall ruleset for %q: %s", host.InventoryPath, eerr)
+		} else if enabled {
+			// re-fetch and re-filter now that the ruleset has been enabled
+			if info, err = fs.Info(op); err == nil && info != nil {
+				rs = object.HostFirewallRulesetList(info.Ruleset)
+				filteredRules, err = rs.EnabledByRule(requiredRule, true)
+			}
+		}
+
+		if err != nil {
+			return false, &firewallMisconfiguredError{Host: host.InventoryPath, Rule: requiredRule}
+		}
 	}
 	op.Debugf("filtered rules: %v", filteredRules)
 
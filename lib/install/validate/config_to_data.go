@@ -220,6 +220,7 @@ func NewDataFromConfig(ctx context.Context, finder Finder, conf *config.VirtualC
 	setVolumeLocations(op, d, conf)
 	d.InsecureRegistries = conf.InsecureRegistries
 	d.WhitelistRegistries = conf.RegistryWhitelist
+	d.ContentTrustDigests = conf.ContentTrustDigests
 	if d.ScratchSize, err = getHumanSize(conf.ScratchSize, "KB"); err != nil {
 		return
 	}
@@ -230,6 +231,14 @@ func NewDataFromConfig(ctx context.Context, finder Finder, conf *config.VirtualC
 	}
 
 	d.ContainerNameConvention = conf.ContainerNameConvention
+	d.GuestInfoPrefix = conf.GuestInfoPrefix
+	d.WebhookURL = conf.WebhookURL
+	d.WebhookSecret = conf.WebhookSecret
+	d.AdmissionHookURL = conf.AdmissionHookURL
+	d.DefaultNumCPUs = conf.DefaultNumCPUs
+	d.DefaultMemoryMB = conf.DefaultMemoryMB
+	d.MaxContainerNumCPUs = conf.MaxContainerNumCPUs
+	d.MaxContainerMemoryMB = conf.MaxContainerMemoryMB
 	d.UseVMGroup = conf.UseVMGroup
 	if conf.StorageQuota != 0 {
 		quotaGB := (int)(conf.StorageQuota / units.GiB)
@@ -0,0 +1,78 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/rbac"
+)
+
+// clusterPrivileges and datastorePrivileges list the privileges create actually needs on
+// each entity. They intentionally don't try to enumerate everything a VCH ever touches -
+// just the ones whose absence produces the most confusing mid-create failures.
+var (
+	clusterPrivileges   = []string{"VirtualMachine.Inventory.Create", "Resource.AssignVMToPool"}
+	datastorePrivileges = []string{"Datastore.AllocateSpace", "Datastore.Browse"}
+)
+
+// checkPrivileges verifies that the session's user holds the privileges create will need on
+// the target cluster and datastore, and reports exactly which are missing rather than
+// letting the user hit an opaque permission fault partway through create.
+func (v *Validator) checkPrivileges(ctx context.Context) {
+	op := trace.FromContext(ctx, "checkPrivileges")
+	defer trace.End(trace.Begin("", op))
+
+	if !v.sessionValid(op, "Privilege check SKIPPED") {
+		return
+	}
+
+	am := rbac.NewAuthzManager(op, v.session.Vim25())
+
+	type check struct {
+		name   string
+		entity types.ManagedObjectReference
+		privs  []string
+	}
+
+	var checks []check
+	if v.session.Cluster != nil {
+		checks = append(checks, check{"cluster", v.session.Cluster.Reference(), clusterPrivileges})
+	}
+	if v.session.Datastore != nil {
+		checks = append(checks, check{"datastore", v.session.Datastore.Reference(), datastorePrivileges})
+	}
+
+	username := ""
+	if v.session.User != nil {
+		username = v.session.User.Username()
+	}
+
+	for _, c := range checks {
+		missing, err := am.MissingPrivileges(op, c.entity, c.privs)
+		if err != nil {
+			// Not fatal - some ESXi versions/roles don't support this check, so we don't
+			// want to block create over a diagnostic-only failure.
+			op.Debugf("Privilege check on %s SKIPPED: %s", c.name, err)
+			continue
+		}
+		if len(missing) > 0 {
+			v.NoteIssue(fmt.Errorf("user %q is missing required privileges on the %s: %v", username, c.name, missing))
+		}
+	}
+}
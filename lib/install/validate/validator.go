@@ -307,6 +307,7 @@ func (v *Validator) Validate(ctx context.Context, input *data.Data, allowEmptyDC
 	}
 	v.checkFirewall(op, conf)
 	v.checkPersistNetworkBacking(op, false)
+	v.checkPrivileges(op)
 	v.CheckLicense(op)
 	v.checkDRS(op, input)
 	v.checkVMGroup(op, input, conf) // Depends on a side-effect of the checkDRS method.
@@ -466,6 +467,49 @@ func (v *Validator) basics(op trace.Operation, input *data.Data, conf *config.Vi
 	}
 
 	conf.ContainerNameConvention = input.ContainerNameConvention
+
+	if input.GuestInfoPrefix != "" && !strings.HasSuffix(input.GuestInfoPrefix, ".") {
+		input.GuestInfoPrefix += "."
+	}
+	conf.GuestInfoPrefix = input.GuestInfoPrefix
+
+	if input.WebhookURL != "" {
+		if _, err := url.ParseRequestURI(input.WebhookURL); err != nil {
+			v.NoteIssue(errors.Errorf("Webhook URL %q is not valid: %s", input.WebhookURL, err))
+		}
+	}
+	conf.WebhookURL = input.WebhookURL
+	conf.WebhookSecret = input.WebhookSecret
+
+	if input.AdmissionHookURL != "" {
+		if _, err := url.ParseRequestURI(input.AdmissionHookURL); err != nil {
+			v.NoteIssue(errors.Errorf("Admission hook URL %q is not valid: %s", input.AdmissionHookURL, err))
+		}
+	}
+	conf.AdmissionHookURL = input.AdmissionHookURL
+
+	if input.DefaultNumCPUs < 0 {
+		v.NoteIssue(errors.Errorf("Default number of CPUs must not be negative, got %d", input.DefaultNumCPUs))
+	}
+	if input.DefaultMemoryMB < 0 {
+		v.NoteIssue(errors.Errorf("Default memory (MB) must not be negative, got %d", input.DefaultMemoryMB))
+	}
+	if input.MaxContainerNumCPUs < 0 {
+		v.NoteIssue(errors.Errorf("Maximum container CPUs must not be negative, got %d", input.MaxContainerNumCPUs))
+	}
+	if input.MaxContainerMemoryMB < 0 {
+		v.NoteIssue(errors.Errorf("Maximum container memory (MB) must not be negative, got %d", input.MaxContainerMemoryMB))
+	}
+	if input.MaxContainerNumCPUs > 0 && input.DefaultNumCPUs > input.MaxContainerNumCPUs {
+		v.NoteIssue(errors.Errorf("Default number of CPUs (%d) exceeds the maximum container CPUs (%d)", input.DefaultNumCPUs, input.MaxContainerNumCPUs))
+	}
+	if input.MaxContainerMemoryMB > 0 && input.DefaultMemoryMB > input.MaxContainerMemoryMB {
+		v.NoteIssue(errors.Errorf("Default memory (%d MB) exceeds the maximum container memory (%d MB)", input.DefaultMemoryMB, input.MaxContainerMemoryMB))
+	}
+	conf.DefaultNumCPUs = input.DefaultNumCPUs
+	conf.DefaultMemoryMB = input.DefaultMemoryMB
+	conf.MaxContainerNumCPUs = input.MaxContainerNumCPUs
+	conf.MaxContainerMemoryMB = input.MaxContainerMemoryMB
 }
 
 func (v *Validator) checkSessionSet() []string {
@@ -692,6 +736,11 @@ func (v *Validator) registries(op trace.Operation, input *data.Data, conf *confi
 		op.Info(msg)
 	}
 
+	conf.ContentTrustDigests = input.ContentTrustDigests
+	if len(conf.ContentTrustDigests) > 0 {
+		op.Infof("Content trust enabled - only %d trusted image manifest digest(s) will be pulled", len(conf.ContentTrustDigests))
+	}
+
 	if len(input.RegistryCAs) == 0 {
 		return
 	}
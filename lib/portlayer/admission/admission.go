@@ -0,0 +1,200 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admission gives operators a hook into container creation. A Hook can
+// reject a create outright (e.g. an image that isn't on an allow-list) or mutate
+// it (e.g. clamp memoryMB to a site-wide cap) before the portlayer acts on it.
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Request describes the container create being reviewed. It carries only the
+// fields a policy is likely to care about, not the full ContainerCreateConfig.
+type Request struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	NumCPUs     int64             `json:"numCPUs"`
+	MemoryMB    int64             `json:"memoryMB"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Decision is a Hook's verdict on a Request. MemoryMB, if non-zero, replaces the
+// requested value, letting a Hook clamp resource requests rather than reject them
+// outright.
+type Decision struct {
+	Allow    bool   `json:"allow"`
+	Reason   string `json:"reason,omitempty"`
+	MemoryMB int64  `json:"memoryMB,omitempty"`
+}
+
+// Hook reviews a container create request. An error is treated as a rejection so
+// that a misbehaving or unreachable policy service fails closed rather than open.
+type Hook interface {
+	Review(op trace.Operation, req Request) (*Decision, error)
+}
+
+var hook Hook
+
+// Register installs h as the admission hook consulted by Review. Registering a nil
+// Hook (the default) disables admission control entirely.
+func Register(h Hook) {
+	hook = h
+}
+
+// CapacityError is returned by Review when a create is rejected solely because the
+// VCH has reached its configured maximum container count - independent of the
+// registered Hook or any per-project quota. It's a distinct type, rather than just
+// a rejecting Decision, so a caller like the docker personality or an external
+// scheduler can recognize "this VCH is full" specifically and spill the create to
+// another VCH instead of treating it the same as a policy rejection.
+type CapacityError struct {
+	Max int
+}
+
+func (e CapacityError) Error() string {
+	return fmt.Sprintf("VCH is at capacity: %d containers already exist, the configured maximum", e.Max)
+}
+
+var (
+	maxContainers  int
+	containerCount func() int
+
+	// reservationTTL bounds how long a Reserve grant counts against maxContainers before
+	// it's treated as abandoned and pruned. A create that's admitted here doesn't land in
+	// containerCount() until it's actually committed, and Commit happens in a separate,
+	// later API call than Review - so a client that never follows through (crash, dropped
+	// connection between Create and Commit) can't wedge the cap shut forever.
+	reservationTTL = 2 * time.Minute
+
+	reservationsMu sync.Mutex
+	reservations   = map[string]time.Time{}
+)
+
+// SetMaxContainers configures a hard cap on the number of containers Review will
+// admit, checked ahead of the registered Hook. count is called to get the current
+// number of live containers. max of 0 (the default) disables the cap.
+func SetMaxContainers(max int, count func() int) {
+	maxContainers = max
+	containerCount = count
+}
+
+// Release drops the reservation held for req.ID, if any. Callers must call Release
+// exactly once for every req.ID that was passed to a successful Review - either once
+// the container has been committed (containerCount() now covers it) or once the
+// create attempt has failed and will never reach commit.
+func Release(id string) {
+	reservationsMu.Lock()
+	delete(reservations, id)
+	reservationsMu.Unlock()
+}
+
+// pruneReservationsLocked drops reservations older than reservationTTL. Callers must
+// hold reservationsMu.
+func pruneReservationsLocked() {
+	cutoff := time.Now().Add(-reservationTTL)
+	for id, t := range reservations {
+		if t.Before(cutoff) {
+			delete(reservations, id)
+		}
+	}
+}
+
+// Review runs the configured maximum-container-count check, then the registered
+// Hook, if any, against req. A nil Decision with a nil error means neither is
+// configured and the request is unconditionally allowed.
+//
+// A create admitted by the count check reserves a slot until the caller releases it
+// via Release - without this, concurrent creates could all observe the same
+// pre-create count and all pass the check, letting the VCH overshoot maxContainers by
+// up to len(concurrent creates)-1.
+func Review(op trace.Operation, req Request) (*Decision, error) {
+	if maxContainers > 0 && containerCount != nil {
+		reservationsMu.Lock()
+		pruneReservationsLocked()
+		if containerCount()+len(reservations) >= maxContainers {
+			reservationsMu.Unlock()
+			return nil, CapacityError{Max: maxContainers}
+		}
+		reservations[req.ID] = time.Now()
+		reservationsMu.Unlock()
+	}
+
+	if hook == nil {
+		return nil, nil
+	}
+
+	d, err := hook.Review(op, req)
+	if err != nil {
+		Release(req.ID)
+		return nil, err
+	}
+	if !d.Allow {
+		Release(req.ID)
+		if d.Reason == "" {
+			d.Reason = "rejected by admission policy"
+		}
+	}
+	return d, nil
+}
+
+// HTTPHook is a Hook that delegates the decision to an operator-run HTTP service:
+// it POSTs the Request as JSON and expects a Decision as the JSON response body.
+type HTTPHook struct {
+	URL    string
+	client *http.Client
+}
+
+// NewHTTPHook returns a Hook that calls out to url for every container create.
+func NewHTTPHook(url string) *HTTPHook {
+	return &HTTPHook{
+		URL:    url,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Review implements Hook.
+func (h *HTTPHook) Review(op trace.Operation, req Request) (*Decision, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Post(h.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("admission hook %s unreachable: %s", h.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("admission hook %s returned status %d", h.URL, resp.StatusCode)
+	}
+
+	var d Decision
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("admission hook %s returned an invalid decision: %s", h.URL, err)
+	}
+
+	return &d, nil
+}
@@ -81,7 +81,11 @@ func toggle(handle *exec.Handle, id string, connected bool) (*exec.Handle, error
 	c := serial.GetVirtualDevice().Connectable
 	b := serial.GetVirtualDevice().Backing.(*types.VirtualSerialPortURIBackingInfo)
 
-	serviceURI := fmt.Sprintf("tcp://127.0.0.1:%d", constants.AttachServerPort)
+	// keep a connector address set on the backing at Join time, falling back to the global one
+	svcURI := b.ServiceURI
+	if svcURI == "" {
+		svcURI = serviceURI()
+	}
 	proxyURI := fmt.Sprintf("telnet://%s:%d", ip, constants.SerialOverLANPort)
 
 	if b.ProxyURI == proxyURI && c.Connected == connected {
@@ -97,8 +101,8 @@ func toggle(handle *exec.Handle, id string, connected bool) (*exec.Handle, error
 		c.StartConnected = handle.ExecConfig.Sessions[handle.ExecConfig.ID].Attach
 	}
 
-	log.Debugf("Setting ServiceURI to %s", serviceURI)
-	b.ServiceURI = serviceURI
+	log.Debugf("Setting ServiceURI to %s", svcURI)
+	b.ServiceURI = svcURI
 
 	log.Debugf("Setting the ProxyURI to %s", proxyURI)
 	b.ProxyURI = proxyURI
@@ -105,6 +105,24 @@ func ContainerIDs(conn ssh.Conn) ([]string, error) {
 	return ids.IDs, nil
 }
 
+// SessionCreate asks the tether at the other end of conn to start a new session directly over
+// the attach channel, bypassing the usual ExtraConfig/guestinfo reconfigure round-trip. This is
+// only viable for non-persistent exec-style sessions - anything the guest needs to remember
+// across a tether restart still has to go through the config.
+func SessionCreate(conn ssh.Conn, msg *msgs.SessionCreateMsg) error {
+	defer trace.End(trace.Begin(msg.ID))
+
+	ok, reply, err := conn.SendRequest(msgs.SessionCreateReq, true, msg.Marshal())
+	if err != nil {
+		return fmt.Errorf("session create request error: %s", err)
+	}
+	if !ok {
+		return fmt.Errorf("session create failed: %s", string(reply))
+	}
+
+	return nil
+}
+
 // NewSSHInteraction returns a stream connection to the requested session
 // The ssh conn is assumed to be connected to the Executor hosting the session
 func NewSSHInteraction(conn ssh.Conn, id string, version uint32) (SessionInteractor, error) {
@@ -36,6 +36,8 @@ type Server struct {
 	m sync.RWMutex
 	l *net.TCPListener
 	c *Connector
+
+	throttle *sessionThrottle
 }
 
 // NewServer returns a Server instance
@@ -43,8 +45,9 @@ func NewServer(ip string, port int) *Server {
 	defer trace.End(trace.Begin(""))
 
 	return &Server{
-		ip:   ip,
-		port: port,
+		ip:       ip,
+		port:     port,
+		throttle: newSessionThrottle(DefaultMaxSessionsPerContainer),
 	}
 }
 
@@ -118,3 +121,15 @@ func (n *Server) RemoveInteraction(id string) error {
 
 	return n.c.RemoveInteraction(id)
 }
+
+// AcquireSession reserves a concurrent attach/exec stream slot for the given container,
+// returning TooManySessionsError if it is already at its limit. Every successful
+// AcquireSession must be paired with a ReleaseSession once the stream ends.
+func (n *Server) AcquireSession(id string) error {
+	return n.throttle.Acquire(id)
+}
+
+// ReleaseSession frees a stream slot reserved by a prior successful AcquireSession.
+func (n *Server) ReleaseSession(id string) {
+	n.throttle.Release(id)
+}
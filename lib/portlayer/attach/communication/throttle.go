@@ -0,0 +1,82 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package communication
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxSessionsPerContainer is used when a Server is constructed without an explicit
+// limit. The serial-over-LAN channel to a containerVM has a fixed 115200bps ceiling shared
+// by every attach/exec stream multiplexed onto it, so a handful of concurrent streams is
+// already enough to saturate it.
+const DefaultMaxSessionsPerContainer = 4
+
+// TooManySessionsError is returned by Server.AcquireSession when a container already has
+// its maximum number of concurrent attach/exec streams open.
+type TooManySessionsError struct {
+	ID  string
+	Max int
+}
+
+func (e TooManySessionsError) Error() string {
+	return fmt.Sprintf("container %s already has the maximum of %d concurrent attach sessions", e.ID, e.Max)
+}
+
+// sessionThrottle bounds the number of concurrent attach/exec streams (stdin, stdout,
+// stderr each count separately) a single container may have open at once.
+type sessionThrottle struct {
+	mu    sync.Mutex
+	open  map[string]int
+	limit int
+}
+
+func newSessionThrottle(limit int) *sessionThrottle {
+	if limit <= 0 {
+		limit = DefaultMaxSessionsPerContainer
+	}
+
+	return &sessionThrottle{
+		open:  make(map[string]int),
+		limit: limit,
+	}
+}
+
+// Acquire reserves a session slot for id, failing with TooManySessionsError if id is
+// already at the limit. Every successful Acquire must be matched with a Release.
+func (t *sessionThrottle) Acquire(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.open[id] >= t.limit {
+		return TooManySessionsError{ID: id, Max: t.limit}
+	}
+
+	t.open[id]++
+	return nil
+}
+
+// Release frees a session slot reserved by a prior successful Acquire.
+func (t *sessionThrottle) Release(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.open[id] <= 1 {
+		delete(t.open, id)
+		return
+	}
+	t.open[id]--
+}
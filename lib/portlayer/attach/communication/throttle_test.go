@@ -0,0 +1,50 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package communication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionThrottleEnforcesLimit(t *testing.T) {
+	th := newSessionThrottle(2)
+
+	assert.NoError(t, th.Acquire("c1"))
+	assert.NoError(t, th.Acquire("c1"))
+
+	err := th.Acquire("c1")
+	assert.Error(t, err)
+	assert.IsType(t, TooManySessionsError{}, err)
+
+	// a different container is unaffected by c1's limit
+	assert.NoError(t, th.Acquire("c2"))
+}
+
+func TestSessionThrottleReleaseFreesSlot(t *testing.T) {
+	th := newSessionThrottle(1)
+
+	assert.NoError(t, th.Acquire("c1"))
+	assert.Error(t, th.Acquire("c1"))
+
+	th.Release("c1")
+	assert.NoError(t, th.Acquire("c1"))
+}
+
+func TestSessionThrottleDefaultsWhenLimitNotPositive(t *testing.T) {
+	th := newSessionThrottle(0)
+	assert.Equal(t, DefaultMaxSessionsPerContainer, th.limit)
+}
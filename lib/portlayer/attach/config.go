@@ -0,0 +1,46 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attach
+
+import (
+	"fmt"
+
+	"github.com/vmware/vic/lib/constants"
+)
+
+// Configuration holds package-wide options for the attach subsystem, set
+// once by the port layer during startup.
+type Configuration struct {
+	// ServiceURI is the address embedded in a container VM's serial port
+	// backing that it dials back to in order to reach this port layer's
+	// attach server. Empty means the default of tcp://127.0.0.1:<AttachServerPort>,
+	// which only works when the attach server is co-resident with the port
+	// layer on that exact port; set this explicitly for a split or
+	// multi-VCH deployment.
+	ServiceURI string
+}
+
+// Config is the attach package's live configuration - see Configuration.
+var Config Configuration
+
+// serviceURI returns the configured attach ServiceURI, falling back to the
+// co-resident default when none has been set.
+func serviceURI() string {
+	if Config.ServiceURI != "" {
+		return Config.ServiceURI
+	}
+
+	return fmt.Sprintf("tcp://127.0.0.1:%d", constants.AttachServerPort)
+}
@@ -23,8 +23,12 @@ import (
 	"github.com/vmware/vic/pkg/trace"
 )
 
-// Join adds network backed serial port to the caller and configures them
-func Join(h interface{}) (interface{}, error) {
+// Join adds network backed serial port to the caller and configures them.
+// connectorURI, if non-empty, overrides the address the container VM dials
+// back to reach the attach server in place of Config.ServiceURI - intended
+// for test environments that need a one-off override rather than changing
+// the port layer's global configuration.
+func Join(h interface{}, connectorURI string) (interface{}, error) {
 	defer trace.End(trace.Begin(""))
 
 	handle, ok := h.(*exec.Handle)
@@ -32,6 +36,16 @@ func Join(h interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("Type assertion failed for %#+v", handle)
 	}
 
+	if !anySessionAttachable(handle) {
+		// no session wants attach, so there's nothing to dial back to -
+		// don't add a serial device that will never be used
+		return handle, nil
+	}
+
+	if connectorURI == "" {
+		connectorURI = serviceURI()
+	}
+
 	// Tether serial port - backed by network
 	serial := &types.VirtualSerialPort{
 		VirtualDevice: types.VirtualDevice{
@@ -40,7 +54,7 @@ func Join(h interface{}) (interface{}, error) {
 					Direction: string(types.VirtualDeviceURIBackingOptionDirectionClient),
 					ProxyURI:  fmt.Sprintf("telnet://0.0.0.0:%d", constants.SerialOverLANPort),
 					// Set it to 0.0.0.0 during Join call, VCH IP will be set when we call Bind
-					ServiceURI: fmt.Sprintf("tcp://127.0.0.1:%d", constants.AttachServerPort),
+					ServiceURI: connectorURI,
 				},
 			},
 			Connectable: &types.VirtualDeviceConnectInfo{
@@ -59,3 +73,15 @@ func Join(h interface{}) (interface{}, error) {
 
 	return handle, nil
 }
+
+// anySessionAttachable reports whether at least one of handle's sessions has
+// attach enabled.
+func anySessionAttachable(handle *exec.Handle) bool {
+	for _, session := range handle.ExecConfig.Sessions {
+		if session.Attach {
+			return true
+		}
+	}
+
+	return false
+}
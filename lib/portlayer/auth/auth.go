@@ -0,0 +1,85 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth lets the portlayer API gate requests on something more than the
+// mutual-TLS client certificate the generated server already requires. A Provider
+// resolves a bearer credential taken off a request to an Identity, or rejects it.
+// This is deliberately independent of the transport-level client cert check -
+// providers here answer "who is this caller" for logging and future
+// authorization decisions, not "is this connection trusted enough to reach the
+// API at all".
+package auth
+
+import (
+	"fmt"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// Identity is the caller a credential resolved to.
+type Identity struct {
+	// Subject is the caller's name/username/DN, as reported by the Provider that
+	// authenticated them.
+	Subject string
+
+	// Provider is the name of the Provider that authenticated the caller, so an
+	// audit trail can distinguish "static token" callers from "LDAP" callers.
+	Provider string
+}
+
+// Provider resolves a bearer credential to an Identity, or returns an error if
+// the credential doesn't belong to it. Returning an error rather than a nil
+// Identity lets Authenticate tell "not mine, try the next provider" apart from
+// "mine, but invalid" in a future where that distinction matters for logging.
+type Provider interface {
+	Name() string
+	Authenticate(op trace.Operation, credential string) (*Identity, error)
+}
+
+var providers []Provider
+
+// Register appends p to the list of providers consulted by Authenticate, in
+// registration order. Registering no providers (the default) leaves the API
+// gated by mutual TLS alone, unchanged from before this package existed.
+func Register(p Provider) {
+	providers = append(providers, p)
+}
+
+// Registered reports whether any Provider has been registered, so callers can
+// tell "no auth configured" apart from "every configured provider rejected
+// this credential".
+func Registered() bool {
+	return len(providers) > 0
+}
+
+// Authenticate tries credential against every registered Provider in turn and
+// returns the first successful Identity. It fails closed: if no provider
+// accepts the credential, or none are registered but Authenticate is called
+// anyway, the caller is rejected rather than treated as anonymous.
+func Authenticate(op trace.Operation, credential string) (*Identity, error) {
+	if !Registered() {
+		return nil, fmt.Errorf("auth: no provider configured")
+	}
+
+	var lastErr error
+	for _, p := range providers {
+		id, err := p.Authenticate(op, credential)
+		if err == nil {
+			return id, nil
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("auth: credential rejected by all providers, last error: %s", lastErr)
+}
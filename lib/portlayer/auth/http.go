@@ -0,0 +1,94 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+const requestTimeout = 10 * time.Second
+
+// httpRequest is what HTTPProvider POSTs to its backing service.
+type httpRequest struct {
+	Credential string `json:"credential"`
+}
+
+// httpResponse is what HTTPProvider expects back on success.
+type httpResponse struct {
+	Subject string `json:"subject"`
+}
+
+// HTTPProvider delegates authentication to an operator-run service, the same
+// way admission.HTTPHook delegates create decisions. This is how LDAP/AD-backed
+// lookups are supported without vendoring an LDAP client into the port layer
+// itself: an operator runs a small bridge service in front of their directory
+// (or any other identity system) and points HTTPProvider at it.
+type HTTPProvider struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider returns a Provider named name that authenticates by POSTing
+// the credential to url and expecting {"subject": "..."} on success. name
+// distinguishes multiple HTTPProviders (e.g. "ldap", "corp-sso") from each
+// other in logs.
+func NewHTTPProvider(name, url string) *HTTPProvider {
+	return &HTTPProvider{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Name implements Provider.
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+// Authenticate implements Provider.
+func (p *HTTPProvider) Authenticate(op trace.Operation, credential string) (*Identity, error) {
+	body, err := json.Marshal(httpRequest{Credential: credential})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth backend %s unreachable: %s", p.name, p.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: auth backend %s rejected credential (status %d)", p.name, p.url, resp.StatusCode)
+	}
+
+	var r httpResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s: auth backend %s returned an invalid response: %s", p.name, p.url, err)
+	}
+
+	if r.Subject == "" {
+		return nil, fmt.Errorf("%s: auth backend %s returned no subject", p.name, p.url)
+	}
+
+	return &Identity{Subject: r.Subject, Provider: p.name}, nil
+}
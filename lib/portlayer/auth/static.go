@@ -0,0 +1,50 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// StaticTokenProvider authenticates callers against a fixed, in-memory table of
+// bearer tokens. It exists for the simple cases - a CI system or a single
+// orchestrator with a long-lived credential - where standing up a full identity
+// backend would be overkill.
+type StaticTokenProvider struct {
+	tokens map[string]string
+}
+
+// NewStaticTokenProvider returns a Provider that accepts any token in tokens,
+// mapping it to the associated subject name.
+func NewStaticTokenProvider(tokens map[string]string) *StaticTokenProvider {
+	return &StaticTokenProvider{tokens: tokens}
+}
+
+// Name implements Provider.
+func (p *StaticTokenProvider) Name() string {
+	return "static-token"
+}
+
+// Authenticate implements Provider.
+func (p *StaticTokenProvider) Authenticate(op trace.Operation, credential string) (*Identity, error) {
+	subject, ok := p.tokens[credential]
+	if !ok {
+		return nil, fmt.Errorf("static-token: unknown token")
+	}
+
+	return &Identity{Subject: subject, Provider: p.Name()}, nil
+}
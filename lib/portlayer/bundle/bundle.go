@@ -0,0 +1,186 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle creates a set of interdependent networks, volumes and
+// containers as a single unit, so a docker-compose style deployment doesn't
+// have to be sequenced by hand over several separate portlayer calls. Members
+// are created in dependency order and, if any member fails, everything
+// already created for the bundle is torn down again - the bundle either lands
+// whole or not at all.
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// Kind identifies what sort of resource a Resource in a bundle describes.
+type Kind string
+
+const (
+	KindNetwork   Kind = "network"
+	KindVolume    Kind = "volume"
+	KindContainer Kind = "container"
+)
+
+// Resource is one member of a bundle. Name must be unique within the bundle
+// and is how other Resources reference it via DependsOn. Spec is passed
+// through, uninterpreted, to the Creator for Kind.
+type Resource struct {
+	Name      string
+	Kind      Kind
+	DependsOn []string
+	Spec      interface{}
+}
+
+// Creator creates and destroys one kind of bundle member. Destroy is called,
+// best-effort, to roll back a Resource that Create already succeeded for when
+// a later member of the same bundle fails.
+type Creator interface {
+	Create(op trace.Operation, r Resource) (id string, err error)
+	Destroy(op trace.Operation, r Resource, id string) error
+}
+
+// Spec is the full set of resources to create as one bundle.
+type Spec struct {
+	Resources []Resource
+}
+
+// Result maps each resource's name to the id its Creator returned.
+type Result struct {
+	IDs map[string]string
+}
+
+// Error reports that a bundle failed partway through, and that everything
+// created before the failing Resource has already been rolled back.
+type Error struct {
+	Resource string
+	Cause    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bundle: creating %s: %s (bundle rolled back)", e.Resource, e.Cause)
+}
+
+// Create resolves a dependency order for spec.Resources and creates each one
+// in turn via creators[resource.Kind]. If any Resource fails to create, every
+// Resource already created for this bundle is destroyed, in reverse creation
+// order, before Create returns.
+func Create(op trace.Operation, creators map[Kind]Creator, spec Spec) (*Result, error) {
+	order, err := resolveOrder(spec.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{IDs: make(map[string]string, len(order))}
+	created := make([]Resource, 0, len(order))
+
+	for _, r := range order {
+		c, ok := creators[r.Kind]
+		if !ok {
+			rollback(op, creators, created, res)
+			return nil, &Error{Resource: r.Name, Cause: fmt.Errorf("no creator registered for kind %q", r.Kind)}
+		}
+
+		id, err := c.Create(op, r)
+		if err != nil {
+			rollback(op, creators, created, res)
+			return nil, &Error{Resource: r.Name, Cause: err}
+		}
+
+		res.IDs[r.Name] = id
+		created = append(created, r)
+	}
+
+	return res, nil
+}
+
+// rollback destroys already-created members in reverse order. It's
+// best-effort: a Destroy failure is logged and the rollback continues, since
+// stopping partway through would leave even more of the bundle stranded.
+func rollback(op trace.Operation, creators map[Kind]Creator, created []Resource, res *Result) {
+	for i := len(created) - 1; i >= 0; i-- {
+		r := created[i]
+		id := res.IDs[r.Name]
+
+		if err := creators[r.Kind].Destroy(op, r, id); err != nil {
+			op.Errorf("bundle: rollback: failed to destroy %s %q (%s): %s", r.Kind, r.Name, id, err)
+		}
+	}
+}
+
+// resolveOrder returns spec's Resources in an order where every Resource
+// comes after everything it DependsOn, via a standard Kahn's algorithm. It
+// rejects unknown dependency names and dependency cycles up front, before any
+// resource is created.
+func resolveOrder(resources []Resource) ([]Resource, error) {
+	byName := make(map[string]Resource, len(resources))
+	for _, r := range resources {
+		if _, dup := byName[r.Name]; dup {
+			return nil, fmt.Errorf("bundle: duplicate resource name %q", r.Name)
+		}
+		byName[r.Name] = r
+	}
+
+	for _, r := range resources {
+		for _, dep := range r.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("bundle: %s %q depends on unknown resource %q", r.Kind, r.Name, dep)
+			}
+		}
+	}
+
+	remaining := make(map[string]Resource, len(resources))
+	for _, r := range resources {
+		remaining[r.Name] = r
+	}
+
+	ordered := make([]Resource, 0, len(resources))
+	for len(remaining) > 0 {
+		progressed := false
+
+		for _, r := range resources {
+			if _, done := remaining[r.Name]; !done {
+				continue
+			}
+
+			ready := true
+			for _, dep := range r.DependsOn {
+				if _, waiting := remaining[dep]; waiting {
+					ready = false
+					break
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			ordered = append(ordered, r)
+			delete(remaining, r.Name)
+			progressed = true
+		}
+
+		if !progressed {
+			names := make([]string, 0, len(remaining))
+			for name := range remaining {
+				names = append(names, name)
+			}
+			return nil, fmt.Errorf("bundle: dependency cycle among resources %v", names)
+		}
+	}
+
+	return ordered, nil
+}
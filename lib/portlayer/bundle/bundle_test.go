@@ -0,0 +1,137 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// fakeCreator creates resources by appending their name to created, failing
+// for any resource named in failOn. Destroy appends to destroyed.
+type fakeCreator struct {
+	created   *[]string
+	destroyed *[]string
+	failOn    map[string]bool
+}
+
+func (f *fakeCreator) Create(op trace.Operation, r Resource) (string, error) {
+	if f.failOn[r.Name] {
+		return "", fmt.Errorf("induced failure for %s", r.Name)
+	}
+	*f.created = append(*f.created, r.Name)
+	return "id-" + r.Name, nil
+}
+
+func (f *fakeCreator) Destroy(op trace.Operation, r Resource, id string) error {
+	*f.destroyed = append(*f.destroyed, r.Name)
+	return nil
+}
+
+func newCreators(created, destroyed *[]string, failOn ...string) map[Kind]Creator {
+	failSet := make(map[string]bool, len(failOn))
+	for _, n := range failOn {
+		failSet[n] = true
+	}
+
+	c := &fakeCreator{created: created, destroyed: destroyed, failOn: failSet}
+	return map[Kind]Creator{
+		KindNetwork:   c,
+		KindVolume:    c,
+		KindContainer: c,
+	}
+}
+
+func TestCreateOrdersByDependency(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	spec := Spec{Resources: []Resource{
+		{Name: "web", Kind: KindContainer, DependsOn: []string{"appnet", "webdata"}},
+		{Name: "appnet", Kind: KindNetwork},
+		{Name: "webdata", Kind: KindVolume},
+		{Name: "worker", Kind: KindContainer, DependsOn: []string{"appnet", "web"}},
+	}}
+
+	var created, destroyed []string
+	res, err := Create(op, newCreators(&created, &destroyed), spec)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Empty(t, destroyed)
+	assert.Equal(t, 4, len(res.IDs))
+	assert.Equal(t, "id-appnet", res.IDs["appnet"])
+
+	// every resource must be created after everything it depends on
+	pos := make(map[string]int, len(created))
+	for i, name := range created {
+		pos[name] = i
+	}
+	assert.True(t, pos["appnet"] < pos["web"])
+	assert.True(t, pos["webdata"] < pos["web"])
+	assert.True(t, pos["web"] < pos["worker"])
+}
+
+func TestCreateUnknownDependency(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	spec := Spec{Resources: []Resource{
+		{Name: "web", Kind: KindContainer, DependsOn: []string{"ghost"}},
+	}}
+
+	var created, destroyed []string
+	_, err := Create(op, newCreators(&created, &destroyed), spec)
+	assert.Error(t, err)
+	assert.Empty(t, created)
+}
+
+func TestCreateDependencyCycle(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	spec := Spec{Resources: []Resource{
+		{Name: "a", Kind: KindContainer, DependsOn: []string{"b"}},
+		{Name: "b", Kind: KindContainer, DependsOn: []string{"a"}},
+	}}
+
+	var created, destroyed []string
+	_, err := Create(op, newCreators(&created, &destroyed), spec)
+	assert.Error(t, err)
+	assert.Empty(t, created)
+}
+
+func TestCreateRollsBackOnFailure(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	spec := Spec{Resources: []Resource{
+		{Name: "appnet", Kind: KindNetwork},
+		{Name: "webdata", Kind: KindVolume, DependsOn: []string{"appnet"}},
+		{Name: "web", Kind: KindContainer, DependsOn: []string{"appnet", "webdata"}},
+	}}
+
+	var created, destroyed []string
+	_, err := Create(op, newCreators(&created, &destroyed, "web"), spec)
+	if !assert.Error(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"appnet", "webdata"}, created)
+	// rollback happens in reverse creation order
+	assert.Equal(t, []string{"webdata", "appnet"}, destroyed)
+}
@@ -124,6 +124,8 @@ func init() {
 		(*types.VmMigratedEvent)(nil),
 		(*types.DrsVmMigratedEvent)(nil),
 		(*types.VmRelocatedEvent)(nil),
+		(*types.VmMacConflictEvent)(nil),
+		(*types.VmStaticMacConflictEvent)(nil),
 	}
 
 	for _, event := range events {
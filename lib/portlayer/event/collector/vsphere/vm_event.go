@@ -47,6 +47,8 @@ func NewVMEvent(be types.BaseEvent) *VMEvent {
 		ee = events.ContainerMigratedByDrs
 	case *types.VmRelocatedEvent:
 		ee = events.ContainerRelocated
+	case *types.VmMacConflictEvent, *types.VmStaticMacConflictEvent:
+		ee = events.ContainerIPConflict
 	default:
 		panic("Unknown event")
 	}
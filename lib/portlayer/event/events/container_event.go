@@ -15,20 +15,34 @@
 package events
 
 const (
-	ContainerCreated       = "Created"
-	ContainerFailed        = "Failed"
-	ContainerMigrated      = "Migrated"
-	ContainerMigratedByDrs = "MigratedByDrs"
-	ContainerPoweredOff    = "PoweredOff"
-	ContainerPoweredOn     = "PoweredOn"
-	ContainerReconfigured  = "Reconfigured"
-	ContainerRelocated     = "Relocated"
-	ContainerRemoved       = "Removed"
-	ContainerResumed       = "Resumed"
-	ContainerShutdown      = "Shutdown"
-	ContainerStarted       = "Started"
-	ContainerStopped       = "Stopped"
-	ContainerSuspended     = "Suspended"
+	ContainerCreated         = "Created"
+	ContainerFailed          = "Failed"
+	ContainerIPConflict      = "IPConflict"
+	ContainerMemoryWatermark = "MemoryWatermarkExceeded"
+	ContainerMigrated        = "Migrated"
+	ContainerMigratedByDrs   = "MigratedByDrs"
+	ContainerPoweredOff      = "PoweredOff"
+	ContainerPoweredOn       = "PoweredOn"
+	ContainerReconfigured    = "Reconfigured"
+	ContainerRelocated       = "Relocated"
+	ContainerRemoved         = "Removed"
+	ContainerRestored        = "Restored"
+	ContainerResumed         = "Resumed"
+	ContainerShutdown        = "Shutdown"
+	ContainerStarted         = "Started"
+	ContainerStopped         = "Stopped"
+	ContainerSuspended       = "Suspended"
+	ContainerTrashed         = "Trashed"
+
+	// The stage events below span the several separate API calls a client makes to create
+	// a container (Create, AddContainer to a scope, Commit) so a CLI can report meaningful
+	// progress instead of a silent multi-second wait, without those calls being merged into
+	// one. ContainerCreated remains the terminal event marking the whole sequence done.
+	ContainerCreateValidating = "CreateValidating"
+	ContainerCreateNetworking = "CreateNetworking"
+	ContainerCreatePlacing    = "CreatePlacing"
+	ContainerCreatingVM       = "CreateCreatingVM"
+	ContainerCreateFinalizing = "CreateFinalizing"
 )
 
 type ContainerEvent struct {
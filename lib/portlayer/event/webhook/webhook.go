@@ -0,0 +1,105 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook POSTs container lifecycle events to an operator-configured HTTP
+// endpoint, so external orchestrators and chatops tooling can react without polling
+// the portlayer API.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/vmware/vic/lib/portlayer/event"
+	"github.com/vmware/vic/lib/portlayer/event/events"
+)
+
+const (
+	subscriberName = "webhook"
+	requestTimeout = 10 * time.Second
+
+	// SignatureHeader carries the hex-encoded HMAC-SHA256 signature of the request
+	// body, computed with the configured secret, so receivers can authenticate delivery.
+	SignatureHeader = "X-VIC-Signature"
+)
+
+// containerEvent is the JSON body POSTed for every container lifecycle event.
+type containerEvent struct {
+	ID      string    `json:"id"`
+	Ref     string    `json:"ref"`
+	Event   string    `json:"event"`
+	Detail  string    `json:"detail"`
+	Created time.Time `json:"created"`
+}
+
+// Register subscribes to container lifecycle events on mgr and POSTs each one to url
+// as it occurs. If secret is non-empty, the request body is signed with HMAC-SHA256
+// and the signature carried in SignatureHeader so the receiver can authenticate it.
+// A no-op if url is empty.
+func Register(mgr *event.Manager, url string, secret string) {
+	if mgr == nil || url == "" {
+		return
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+
+	mgr.Subscribe(events.NewEventType(events.ContainerEvent{}).Topic(), subscriberName, func(e events.Event) {
+		deliver(client, url, secret, e)
+	})
+}
+
+func deliver(client *http.Client, url string, secret string, e events.Event) {
+	body, err := json.Marshal(containerEvent{
+		ID:      e.EventID(),
+		Ref:     e.Reference(),
+		Event:   e.String(),
+		Detail:  e.Message(),
+		Created: e.Created(),
+	})
+	if err != nil {
+		log.Errorf("webhook: unable to marshal event %s: %s", e.EventID(), err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("webhook: unable to build request for event %s: %s", e.EventID(), err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warnf("webhook: delivery failed for event %s: %s", e.EventID(), err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Warnf("webhook: delivery for event %s got status %d", e.EventID(), resp.StatusCode)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig/vmomi"
+	"github.com/vmware/vic/pkg/vsphere/session"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+	"github.com/vmware/vic/pkg/vsphere/vm"
+)
+
+// AdoptVM takes an existing, unmanaged VM - typically hand built from the bootstrap image
+// outside of the normal Create/Commit path - and turns it into a container the portlayer
+// manages like any other: it stamps the extraConfig identity metadata onto the VM and
+// registers it in the container cache.
+//
+// The returned Handle behaves exactly like one returned from Create: the caller can still
+// join it to a network scope via the usual AddContainer call and Commit the result, the only
+// difference being that Commit will reconfigure the already-existing VM rather than create a
+// new one.
+func AdoptVM(ctx context.Context, sess *session.Session, moref types.ManagedObjectReference, metadata *executor.ExecutorConfig) (*Handle, error) {
+	op := trace.FromContext(ctx, "AdoptVM")
+	defer trace.End(trace.Begin(metadata.ID, op))
+
+	if metadata == nil || metadata.ID == "" {
+		return nil, fmt.Errorf("adopted container requires an ID")
+	}
+
+	if Containers.Container(metadata.ID) != nil {
+		return nil, fmt.Errorf("a container already exists in the cache with id %s", metadata.ID)
+	}
+
+	cvm := vm.NewVirtualMachine(op, sess, moref)
+
+	var o mo.VirtualMachine
+	if err := cvm.Properties(op, cvm.Reference(), []string{"config"}, &o); err != nil {
+		return nil, fmt.Errorf("unable to read configuration of VM to adopt: %s", err)
+	}
+
+	cfg := make(map[string]string)
+	extraconfig.Encode(extraconfig.MapSink(cfg), metadata)
+
+	spec := types.VirtualMachineConfigSpec{
+		ChangeVersion: o.Config.ChangeVersion,
+		ExtraConfig:   vmomi.OptionValueFromMap(cfg, true),
+	}
+
+	if _, err := cvm.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+		return cvm.Reconfigure(op, spec)
+	}); err != nil {
+		return nil, fmt.Errorf("unable to stamp container metadata while adopting VM %s: %s", moref, err)
+	}
+
+	base := &containerBase{vm: cvm, ExecConfig: &executor.ExecutorConfig{}}
+	if err := base.refresh(op); err != nil {
+		return nil, fmt.Errorf("unable to read back adopted VM %s after stamping metadata: %s", moref, err)
+	}
+
+	if base.ExecConfig.ID != metadata.ID {
+		return nil, fmt.Errorf("adopted VM %s reports id %q after stamping, expected %q", moref, base.ExecConfig.ID, metadata.ID)
+	}
+
+	c := newContainer(base)
+	Containers.Put(c)
+
+	cfm := object.NewCustomFieldsManager(sess.Vim25())
+	stampContainerMetadata(op, sess, cfm, c, cvm)
+
+	op.Infof("Adopted unmanaged VM %s as container %s", moref, c.ExecConfig.ID)
+
+	return newHandle(c), nil
+}
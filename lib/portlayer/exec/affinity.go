@@ -0,0 +1,87 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+)
+
+// SetContainerAffinity creates (or updates) a DRS VM-VM affinity rule keeping the
+// container and target on the same host. This is useful for latency-sensitive
+// containers whose traffic hairpins through the VCH endpoint VM for port mapping,
+// or that need to stay close to another named container.
+//
+// The rule is named after the container id. Every call adds a new rule; an existing
+// rule for the same container is not looked up, edited or replaced. Removing the container also
+// leaves its rule behind - DRS will simply have nothing left to enforce, and the
+// cluster will remove the rule once one of the referenced VMs is gone.
+func SetContainerAffinity(op trace.Operation, id string, target types.ManagedObjectReference) error {
+	defer trace.End(trace.Begin(id, op))
+
+	c := Containers.Container(id)
+	if c == nil {
+		return NotFoundError{}
+	}
+
+	c.m.Lock()
+	vm := c.vm
+	c.m.Unlock()
+
+	if vm == nil {
+		return NotFoundError{}
+	}
+
+	if Config.Cluster == nil {
+		return fmt.Errorf("DRS VM-VM affinity requires a cluster-backed VCH")
+	}
+
+	spec := &types.ClusterConfigSpecEx{
+		RulesSpec: []types.ClusterRuleSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationAdd,
+				},
+				Info: &types.ClusterAffinityRuleSpec{
+					ClusterRuleInfo: types.ClusterRuleInfo{
+						Name:    affinityRuleName(id),
+						Enabled: types.NewBool(true),
+					},
+					Vm: []types.ManagedObjectReference{vm.Reference(), target},
+				},
+			},
+		},
+	}
+
+	_, err := tasks.WaitForResultAndRetryIf(op, func(ctx context.Context) (tasks.Task, error) {
+		return Config.Cluster.Reconfigure(ctx, spec, true)
+	}, tasks.IsTransientError)
+	if err != nil {
+		return fmt.Errorf("unable to set affinity for container %s: %s", id, err)
+	}
+
+	op.Infof("Set DRS affinity between container %s and %s", id, target)
+
+	return nil
+}
+
+func affinityRuleName(id string) string {
+	return fmt.Sprintf("vic-affinity-%s", id)
+}
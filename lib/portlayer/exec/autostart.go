@@ -0,0 +1,132 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// autoStartCommitWaitTime bounds how long a single container's start is allowed to
+// wait for its backing VM operation during VCH boot autostart.
+var autoStartCommitWaitTime = int32(60)
+
+// autoStartRunningWaitTime bounds how long AutoStart waits for a container to reach
+// StateRunning after a successful Commit. AutoStart's caller has no deadline of its own
+// (it runs once, synchronously, during portlayer Init), so without this bound a missed
+// running-state event would hang a wave - and everything behind it - forever.
+const autoStartRunningWaitTime = 60 * time.Second
+
+// AutoStart powers on every container flagged with ExecConfig.AutoStart, mirroring
+// docker's live-restore+restart behavior. It's intended to run once, after the
+// container cache has been reconstructed from vSphere inventory during portlayer
+// Init - starting containers before the cache is populated would race the sync.
+//
+// Containers are grouped into ascending StartOrder waves; every container in a wave
+// is started concurrently and must reach StateRunning before the next wave begins.
+// After a wave reaches StateRunning, AutoStart waits the wave's StartDelay (the
+// largest StartDelay set by any container in that wave) before moving on. A failure
+// starting one container in a wave doesn't block its wave-mates, but does stop
+// AutoStart from proceeding to the next wave.
+func AutoStart(op trace.Operation, sess *session.Session) error {
+	var toStart []*Container
+	for _, c := range Containers.Containers(nil) {
+		if c.ExecConfig.AutoStart && c.CurrentState() != StateRunning {
+			toStart = append(toStart, c)
+		}
+	}
+
+	waves := waveByStartOrder(toStart)
+	for _, wave := range waves {
+		delay := 0
+		errs := make(chan error, len(wave))
+		for _, c := range wave {
+			if c.ExecConfig.StartDelay > delay {
+				delay = c.ExecConfig.StartDelay
+			}
+
+			go func(c *Container) {
+				errs <- autoStartOne(op, sess, c)
+			}(c)
+		}
+
+		failed := 0
+		for range wave {
+			if err := <-errs; err != nil {
+				op.Errorf("AutoStart: %s", err.Error())
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("AutoStart: %d container(s) failed to start, later waves not started", failed)
+		}
+
+		if delay > 0 {
+			time.Sleep(time.Duration(delay) * time.Second)
+		}
+	}
+
+	return nil
+}
+
+func autoStartOne(op trace.Operation, sess *session.Session, c *Container) error {
+	h, err := c.NewHandle(context.Background())
+	if err != nil {
+		return err
+	}
+
+	h.SetTargetState(StateRunning)
+	if err := h.Commit(op, sess, &autoStartCommitWaitTime); err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(op, autoStartRunningWaitTime)
+	defer cancel()
+
+	select {
+	case <-c.WaitForState(StateRunning):
+	case <-waitCtx.Done():
+		return fmt.Errorf("container %s: timed out waiting for state %s: %s", c.ExecConfig.ID, StateRunning, waitCtx.Err())
+	}
+
+	return nil
+}
+
+// waveByStartOrder groups containers by ascending ExecConfig.StartOrder.
+func waveByStartOrder(containers []*Container) [][]*Container {
+	byOrder := make(map[int][]*Container)
+	for _, c := range containers {
+		byOrder[c.ExecConfig.StartOrder] = append(byOrder[c.ExecConfig.StartOrder], c)
+	}
+
+	orders := make([]int, 0, len(byOrder))
+	for order := range byOrder {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+
+	waves := make([][]*Container, 0, len(orders))
+	for _, order := range orders {
+		waves = append(waves, byOrder[order])
+	}
+
+	return waves
+}
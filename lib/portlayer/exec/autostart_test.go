@@ -0,0 +1,61 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func putAutoStartTestContainer(id string, autoStart bool, startOrder int) *Container {
+	c := newTestContainer(id)
+	c.ExecConfig.AutoStart = autoStart
+	c.ExecConfig.StartOrder = startOrder
+	addTestVM(c)
+	Containers.Put(c)
+	return c
+}
+
+func TestWaveByStartOrderGroupsAndSorts(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	web := putAutoStartTestContainer("web", true, 1)
+	app := putAutoStartTestContainer("app", true, 0)
+	db := putAutoStartTestContainer("db", true, 0)
+
+	waves := waveByStartOrder([]*Container{web, app, db})
+
+	assert.Len(t, waves, 2)
+	assert.ElementsMatch(t, []*Container{app, db}, waves[0])
+	assert.Equal(t, []*Container{web}, waves[1])
+}
+
+func TestAutoStartSkipsContainersNotFlagged(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	putAutoStartTestContainer("skip-me", false, 0)
+
+	var toStart []*Container
+	for _, c := range Containers.Containers(nil) {
+		if c.ExecConfig.AutoStart {
+			toStart = append(toStart, c)
+		}
+	}
+
+	assert.Empty(t, toStart)
+}
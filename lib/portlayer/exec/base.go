@@ -23,6 +23,8 @@ import (
 
 	"golang.org/x/crypto/ssh"
 
+	log "github.com/Sirupsen/logrus"
+
 	"github.com/vmware/govmomi/guest"
 	"github.com/vmware/govmomi/task"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -30,7 +32,9 @@ import (
 	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/lib/migration"
 	"github.com/vmware/vic/lib/tether/shared"
+	"github.com/vmware/vic/pkg/retry"
 	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/datastore"
 	"github.com/vmware/vic/pkg/vsphere/extraconfig"
 	"github.com/vmware/vic/pkg/vsphere/extraconfig/vmomi"
 	"github.com/vmware/vic/pkg/vsphere/tasks"
@@ -86,6 +90,13 @@ func newBase(vm *vm.VirtualMachine, c *types.VirtualMachineConfigInfo, r *types.
 		base.DataVersion, _ = migration.ContainerDataVersion(containerExecKeyValues)
 		migratedConf, base.Migrated, base.MigrationError = migration.MigrateContainerConfig(containerExecKeyValues)
 		extraconfig.Decode(extraconfig.MapSource(migratedConf), base.ExecConfig)
+
+		// The container's identity comes exclusively from ExtraConfig - it must never be
+		// inferred from the containerVM's vSphere inventory name, which a user is free to
+		// change independently of the container ID stamped at create time.
+		if base.ExecConfig.ID == "" {
+			log.Warnf("newBase: containerVM %s has ExtraConfig but no decodable container ID - it will not be resolvable by ID", vm.Reference())
+		}
 	}
 
 	return base
@@ -122,6 +133,22 @@ func (c *containerBase) GetContainerVM() *vm.VirtualMachine {
 	return c.vm
 }
 
+// DatastoreName returns the name of the datastore holding this container's VM files, derived
+// from the already-cached VM config rather than a fresh vSphere query. Used for datastore-affine
+// placement of resources, e.g. volumes that should live alongside the container they're created for.
+func (c *containerBase) DatastoreName() (string, error) {
+	if c.Config == nil || c.Config.Files.VmPathName == "" {
+		return "", fmt.Errorf("container %s has no VM path recorded", c)
+	}
+
+	p, err := datastore.PathFromString(c.Config.Files.VmPathName)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Datastore, nil
+}
+
 // updates acquires updates from the infrastructure without holding a lock
 func (c *containerBase) updates(op trace.Operation) (*containerBase, error) {
 	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
@@ -196,6 +223,36 @@ func (c *containerBase) hasStarted(op trace.Operation) bool {
 	return false
 }
 
+// Tether/guest status strings surfaced via inspect and list, distinct from the container's
+// lifecycle State - a powered-on VM can be Starting because its tether hasn't reported in yet
+// (Booting), because it reported in and then went quiet (Unresponsive), or because it's up and
+// heartbeating normally (Connected).
+const (
+	TetherStopped      = "stopped"
+	TetherBooting      = "booting"
+	TetherConnected    = "tether-connected"
+	TetherUnresponsive = "unresponsive"
+)
+
+// TetherStatus reports the tether's last known heartbeat state for the containerVM, derived
+// from the same per-session Started flag that State uses to distinguish a genuinely running
+// container from one that's merely powered on.
+func (c *containerBase) TetherStatus(op trace.Operation) string {
+	if c.Runtime == nil || c.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		return TetherStopped
+	}
+
+	if c.cleanStart(op) {
+		return TetherConnected
+	}
+
+	if c.hasStarted(op) {
+		return TetherUnresponsive
+	}
+
+	return TetherBooting
+}
+
 // State returns the state of the containerVM based on data in the handle, with no refresh
 func (c *containerBase) State(op trace.Operation) State {
 	powerState := c.Runtime.PowerState
@@ -289,7 +346,30 @@ func (c *containerBase) start(op trace.Operation) error {
 		return NotYetExistError{c.ExecConfig.ID}
 	}
 
-	return c.vm.PowerOn(op)
+	// HA failover and DRS storms can make PowerOn fail transiently - e.g. HostCommunication
+	// while a host is being fenced off, or TaskInProgress while the cluster is still settling.
+	// Retry with backoff instead of failing the container immediately; the caller sees the
+	// container remain in StateStarting for the duration rather than bouncing to Stopped.
+	conf := retry.NewBackoffConfig()
+	conf.MaxElapsedTime = 2 * time.Minute
+
+	release := acquireCreateSlot(op)
+	defer release()
+
+	err := retry.DoWithConfig(op, func() error {
+		return c.vm.PowerOn(op)
+	}, func(e error) bool {
+		return tasks.IsTransientError(op, e)
+	}, conf)
+
+	if err != nil && c.vm.IsAlreadyPoweredOnError(err) {
+		// docker treats start on a running container as success, and retried start
+		// calls should be able to rely on the same behavior here
+		op.Debugf("%s is already powered on, treating start as a no-op", c)
+		return nil
+	}
+
+	return err
 }
 
 func (c *containerBase) stop(op trace.Operation, waitTime *int32) error {
@@ -311,6 +391,22 @@ func (c *containerBase) stop(op trace.Operation, waitTime *int32) error {
 	return c.poweroff(op)
 }
 
+// suspend snapshots the container VM's memory and device state so it can be resumed with
+// PowerOn without re-running the container's entrypoint - a docker "pause" implemented as a
+// vSphere suspend rather than a cgroup freeze.
+func (c *containerBase) suspend(op trace.Operation) error {
+	// make sure we have vm
+	if c.vm == nil {
+		return NotYetExistError{c.ExecConfig.ID}
+	}
+
+	_, err := c.vm.WaitForResult(op, func(ctx context.Context) (tasks.Task, error) {
+		return c.vm.Suspend(ctx)
+	})
+
+	return err
+}
+
 func (c *containerBase) kill(op trace.Operation) error {
 	// make sure we have vm
 	if c.vm == nil {
@@ -428,6 +524,11 @@ func (c *containerBase) poweroff(op trace.Operation) error {
 		return NotYetExistError{c.ExecConfig.ID}
 	}
 
+	// stop is interactive - it should not sit behind a backlog of queued bulk creates for a
+	// vCenter task slot
+	release := acquireInteractiveSlot(op)
+	defer release()
+
 	_, err := c.vm.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
 		return c.vm.PowerOff(op)
 	})
@@ -0,0 +1,49 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+func baseWithSession(powerState types.VirtualMachinePowerState, started string, startTime int64) *containerBase {
+	return &containerBase{
+		ExecConfig: &executor.ExecutorConfig{
+			Sessions: map[string]*executor.SessionConfig{
+				"session1": {
+					Started: started,
+					Detail:  executor.Detail{StartTime: startTime},
+				},
+			},
+		},
+		Runtime: &types.VirtualMachineRuntimeInfo{PowerState: powerState},
+	}
+}
+
+func TestTetherStatus(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	assert.Equal(t, TetherStopped, baseWithSession(types.VirtualMachinePowerStatePoweredOff, "true", 1).TetherStatus(op))
+	assert.Equal(t, TetherConnected, baseWithSession(types.VirtualMachinePowerStatePoweredOn, "true", 1).TetherStatus(op))
+	assert.Equal(t, TetherUnresponsive, baseWithSession(types.VirtualMachinePowerStatePoweredOn, "false", 1).TetherStatus(op))
+	assert.Equal(t, TetherBooting, baseWithSession(types.VirtualMachinePowerStatePoweredOn, "false", 0).TetherStatus(op))
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// batchMetadataConcurrency bounds how many containers are reconfigured at once by
+// BatchPatchMetadata, so a fleet-wide re-tag doesn't open thousands of simultaneous
+// reconfigure tasks against vCenter.
+const batchMetadataConcurrency = 16
+
+// BatchMetadataOptions selects the containers to patch and the changes to make.
+type BatchMetadataOptions struct {
+	// IDs restricts the patch to these containers. If empty, every cached container is
+	// considered.
+	IDs []string
+
+	// LabelFilter, if non-empty, further restricts the patch to containers whose docker
+	// labels contain every key/value pair given here.
+	LabelFilter map[string]string
+
+	// Labels is merged into each matched container's docker labels. A key mapped to ""
+	// is left as-is - BatchPatchMetadata only adds or overwrites labels, it doesn't
+	// remove them.
+	Labels map[string]string
+
+	// Annotations is merged into each matched container's ExecConfig.Annotations,
+	// alongside the docker labels change above, if any.
+	Annotations map[string]string
+}
+
+// BatchMetadataError records the failure to patch a single container out of a batch.
+type BatchMetadataError struct {
+	ID  string
+	Err error
+}
+
+func (e *BatchMetadataError) Error() string {
+	return fmt.Sprintf("container %s: %s", e.ID, e.Err)
+}
+
+// BatchPatchMetadata patches labels and/or annotations on every container matching
+// opts, reconfiguring the matched containers concurrently. It returns one
+// BatchMetadataError per container that failed to match or patch; a nil or empty
+// return means every matched container was updated successfully.
+func BatchPatchMetadata(op trace.Operation, sess *session.Session, opts BatchMetadataOptions) []*BatchMetadataError {
+	ids := opts.IDs
+	if len(ids) == 0 {
+		for _, c := range Containers.Containers(nil) {
+			ids = append(ids, c.ExecConfig.ID)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []*BatchMetadataError
+
+	fail := func(id string, err error) {
+		mu.Lock()
+		errs = append(errs, &BatchMetadataError{ID: id, Err: err})
+		mu.Unlock()
+	}
+
+	sem := make(chan struct{}, batchMetadataConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := patchContainerMetadata(op, sess, id, opts); err != nil {
+				fail(id, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// matchesLabelFilter reports whether labels contains every key/value pair in filter.
+// An empty or nil filter matches everything.
+func matchesLabelFilter(labels, filter map[string]string) bool {
+	for k, v := range filter {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// patchContainerMetadata applies opts to a single container, skipping it without error
+// if it doesn't match opts.LabelFilter.
+func patchContainerMetadata(op trace.Operation, sess *session.Session, id string, opts BatchMetadataOptions) error {
+	c := Containers.Container(id)
+	if c == nil {
+		return fmt.Errorf("not found")
+	}
+
+	h, err := c.NewHandle(context.Background())
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	labels, err := decodeDockerLabels(h.ExecConfig.Annotations)
+	if err != nil {
+		return fmt.Errorf("decoding existing labels: %s", err)
+	}
+
+	if !matchesLabelFilter(labels, opts.LabelFilter) {
+		return nil
+	}
+
+	if len(opts.Labels) > 0 {
+		if labels == nil {
+			labels = make(map[string]string, len(opts.Labels))
+		}
+		for k, v := range opts.Labels {
+			if v == "" {
+				continue
+			}
+			labels[k] = v
+		}
+
+		blob, err := encodeDockerLabels(labels)
+		if err != nil {
+			return fmt.Errorf("encoding updated labels: %s", err)
+		}
+
+		if h.ExecConfig.Annotations == nil {
+			h.ExecConfig.Annotations = make(map[string]string)
+		}
+		h.ExecConfig.Annotations[dockerLabelsAnnotation] = blob
+	}
+
+	for k, v := range opts.Annotations {
+		if h.ExecConfig.Annotations == nil {
+			h.ExecConfig.Annotations = make(map[string]string)
+		}
+		h.ExecConfig.Annotations[k] = v
+	}
+
+	if len(opts.Labels) == 0 && len(opts.Annotations) == 0 {
+		return nil
+	}
+
+	return h.Commit(op, sess, nil)
+}
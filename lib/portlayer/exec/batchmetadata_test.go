@@ -0,0 +1,47 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerLabelsRoundTrip(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "storage"}
+
+	blob, err := encodeDockerLabels(labels)
+	assert.NoError(t, err)
+
+	decoded, err := decodeDockerLabels(map[string]string{dockerLabelsAnnotation: blob})
+	assert.NoError(t, err)
+	assert.Equal(t, labels, decoded)
+}
+
+func TestDecodeDockerLabelsMissingIsNilNotError(t *testing.T) {
+	decoded, err := decodeDockerLabels(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestMatchesLabelFilter(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "storage"}
+
+	assert.True(t, matchesLabelFilter(labels, nil))
+	assert.True(t, matchesLabelFilter(labels, map[string]string{"env": "prod"}))
+	assert.False(t, matchesLabelFilter(labels, map[string]string{"env": "staging"}))
+	assert.False(t, matchesLabelFilter(labels, map[string]string{"missing": "key"}))
+}
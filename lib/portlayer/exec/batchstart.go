@@ -0,0 +1,139 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// batchStartCommitWaitTime bounds how long a single container's start is allowed to
+// wait for its backing VM operation during a batch start.
+var batchStartCommitWaitTime = int32(60)
+
+// batchStartRunningWaitTime bounds how long BatchStart waits for a container to reach
+// StateRunning after a successful Commit. BatchStartHandler builds its trace.Operation
+// from context.Background(), so without this bound a missed running-state event (a
+// property-collector hiccup, or a container that fails to actually come up after Commit
+// reports success) would hang the handler goroutine forever and block every remaining
+// container in the batch.
+const batchStartRunningWaitTime = 60 * time.Second
+
+// startOrder topologically sorts ids by each container's ExecConfig.DependsOn, so a
+// container is only started once everything it depends on has already started.
+// Dependencies outside ids are ignored - they're assumed to already be running,
+// since BatchStart only manages the containers it was given. Returns an error if
+// ids contains a dependency cycle.
+func startOrder(ids []string) ([]string, error) {
+	inBatch := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inBatch[id] = true
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(ids))
+	ordered := make([]string, 0, len(ids))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at container %s", id)
+		}
+
+		state[id] = visiting
+
+		c := Containers.Container(id)
+		if c != nil {
+			for _, dep := range c.ExecConfig.DependsOn {
+				if !inBatch[dep] {
+					continue
+				}
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[id] = visited
+		ordered = append(ordered, id)
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// BatchStart starts every container in ids, in dependency order, waiting for each to
+// reach StateRunning before starting whatever depends on it. It stops at the first
+// failure, leaving any container not yet reached in ids at its current state.
+//
+// Health checks aren't part of the wait - the port layer has no health check engine
+// today (HEALTHCHECK is evaluated by the docker personality, not tracked here), so
+// "running" is the closest available readiness signal. If a health-check engine ever
+// lands in the port layer, this is the natural place to wait on it instead.
+func BatchStart(op trace.Operation, sess *session.Session, ids []string) error {
+	ordered, err := startOrder(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ordered {
+		c := Containers.Container(id)
+		if c == nil {
+			return fmt.Errorf("container %s not found", id)
+		}
+
+		if c.CurrentState() == StateRunning {
+			continue
+		}
+
+		h, err := c.NewHandle(context.Background())
+		if err != nil {
+			return fmt.Errorf("container %s: %s", id, err)
+		}
+
+		h.SetTargetState(StateRunning)
+		if err := h.Commit(op, sess, &batchStartCommitWaitTime); err != nil {
+			return fmt.Errorf("container %s: %s", id, err)
+		}
+
+		waitCtx, cancel := context.WithTimeout(op, batchStartRunningWaitTime)
+		select {
+		case <-c.WaitForState(StateRunning):
+		case <-waitCtx.Done():
+			cancel()
+			return fmt.Errorf("container %s: timed out waiting for state %s: %s", id, StateRunning, waitCtx.Err())
+		}
+		cancel()
+	}
+
+	return nil
+}
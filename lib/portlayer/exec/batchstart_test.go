@@ -0,0 +1,73 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func putTestContainer(id string, dependsOn ...string) {
+	c := newTestContainer(id)
+	c.ExecConfig.DependsOn = dependsOn
+	addTestVM(c)
+	Containers.Put(c)
+}
+
+func indexOf(ordered []string, id string) int {
+	for i, o := range ordered {
+		if o == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestStartOrder(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	putTestContainer("db")
+	putTestContainer("app", "db")
+	putTestContainer("web", "app")
+
+	ordered, err := startOrder([]string{"web", "app", "db"})
+	assert.NoError(t, err)
+	assert.True(t, indexOf(ordered, "db") < indexOf(ordered, "app"))
+	assert.True(t, indexOf(ordered, "app") < indexOf(ordered, "web"))
+}
+
+func TestStartOrderIgnoresDependencyOutsideBatch(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	putTestContainer("app", "not-in-this-batch")
+
+	ordered, err := startOrder([]string{"app"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, ordered)
+}
+
+func TestStartOrderDetectsCycle(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	putTestContainer("a", "b")
+	putTestContainer("b", "a")
+
+	_, err := startOrder([]string{"a", "b"})
+	assert.Error(t, err)
+}
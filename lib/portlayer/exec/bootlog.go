@@ -0,0 +1,72 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// tetherDebugLogName is the datastore file the tether's own debug logging is
+// written to, alongside containerLogName for the container's stdout/stderr.
+const tetherDebugLogName = "tether.debug"
+
+// addBootLogSerialPorts adds the file-backed serial ports that capture the
+// container VM's serial output - including tether debug logging and, via
+// containerLogName, anything written before the tether attaches over the
+// network-backed serial port (kernel panics, a missing bootstrap ISO) -
+// starting from power-on. It's a no-op if h.Spec already carries them, so
+// it's safe to call more than once against the same handle.
+func addBootLogSerialPorts(h *Handle) {
+	for _, change := range h.Spec.Spec().DeviceChange {
+		serial, ok := change.GetVirtualDeviceConfigSpec().Device.(*types.VirtualSerialPort)
+		if !ok {
+			continue
+		}
+
+		if _, ok := serial.Backing.(*types.VirtualSerialPortFileBackingInfo); ok {
+			return
+		}
+	}
+
+	logFilePath := h.Spec.VMPathName()
+	if strings.HasSuffix(logFilePath, ".vmx") {
+		logFilePath = logFilePath[:strings.LastIndex(logFilePath, "/")]
+	} else {
+		logFilePath = fmt.Sprintf("%s/%s", logFilePath, h.Spec.Spec().Name)
+	}
+
+	for _, logFile := range []string{tetherDebugLogName, containerLogName} {
+		serial := &types.VirtualSerialPort{
+			VirtualDevice: types.VirtualDevice{
+				Backing: &types.VirtualSerialPortFileBackingInfo{
+					VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+						FileName: fmt.Sprintf("%s/%s", logFilePath, logFile),
+					},
+				},
+				Connectable: &types.VirtualDeviceConnectInfo{
+					Connected:         true,
+					StartConnected:    true,
+					AllowGuestControl: true,
+				},
+			},
+			YieldOnPoll: true,
+		}
+
+		h.Spec.AddVirtualDevice(serial)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// HostDensity reports how heavily a single cluster host is loaded with this
+// VCH's container VMs, for capacity planning and external schedulers layered
+// on top of VIC.
+type HostDensity struct {
+	// HostName is the host's name, as displayed in vCenter.
+	HostName string
+
+	// Containers is the number of this VCH's containers currently running on the host.
+	Containers int
+
+	// ReservedMemoryMB is the sum of the configured memory size of those containers. VIC
+	// doesn't set an explicit memory reservation per container, so this is the same figure
+	// used to size the containerVM itself.
+	ReservedMemoryMB int64
+
+	// ReservedCPU is the sum of the configured number of vCPUs of those containers.
+	ReservedCPU int64
+
+	// FreeMemoryMB is the host's remaining, unreserved memory headroom.
+	FreeMemoryMB int64
+
+	// FreeCPUMhz is the host's remaining, unreserved CPU headroom.
+	FreeCPUMhz int64
+}
+
+// HostCapacity reports, per cluster host backing the VCH, how many of this VCH's
+// container VMs are running there, their aggregate reservations, and the host's
+// remaining headroom. It only produces a meaningful breakdown for a DRS-disabled
+// cluster with more than one host - the same case SelectPlacementHost makes an
+// explicit choice for - since otherwise vSphere doesn't expose per-host placement
+// of the VCH's own containers.
+func HostCapacity(op trace.Operation, sess *session.Session) ([]HostDensity, error) {
+	if Config.Cluster == nil {
+		return nil, fmt.Errorf("unable to report host capacity: no cluster compute resource is configured")
+	}
+
+	hosts, err := Config.Cluster.Hosts(op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate hosts for capacity reporting: %s", err)
+	}
+
+	density := make(map[types.ManagedObjectReference]*HostDensity, len(hosts))
+	order := make([]types.ManagedObjectReference, 0, len(hosts))
+	for _, h := range hosts {
+		var mh mo.HostSystem
+		if err := h.Properties(op, h.Reference(), []string{"name", "summary"}, &mh); err != nil {
+			op.Warnf("Unable to retrieve capacity info for host %s, excluding it from the report: %s", h.Name(), err)
+			continue
+		}
+
+		var freeMemoryMB, freeCPUMhz int64
+		if hw := mh.Summary.Hardware; hw != nil {
+			freeMemoryMB = hw.MemorySize/1024/1024 - int64(mh.Summary.QuickStats.OverallMemoryUsage)
+			freeCPUMhz = int64(hw.CpuMhz)*int64(hw.NumCpuCores) - int64(mh.Summary.QuickStats.OverallCpuUsage)
+		}
+
+		ref := h.Reference()
+		density[ref] = &HostDensity{
+			HostName:     mh.Name,
+			FreeMemoryMB: freeMemoryMB,
+			FreeCPUMhz:   freeCPUMhz,
+		}
+		order = append(order, ref)
+	}
+
+	for _, c := range Containers.Containers([]State{StateRunning}) {
+		info := c.Info()
+		if info.Runtime == nil || info.Runtime.Host == nil {
+			continue
+		}
+
+		hd, ok := density[*info.Runtime.Host]
+		if !ok {
+			// container is running on a host this VCH's cluster no longer reports
+			continue
+		}
+
+		hd.Containers++
+		hd.ReservedMemoryMB += int64(info.MemorySizeMB)
+		hd.ReservedCPU += int64(info.NumCPU)
+	}
+
+	report := make([]HostDensity, 0, len(order))
+	for _, ref := range order {
+		report = append(report, *density[ref])
+	}
+
+	return report, nil
+}
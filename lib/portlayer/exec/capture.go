@@ -0,0 +1,168 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+)
+
+// captureSessionPrefix names every mirror session this package creates, so they can be told
+// apart from VSPAN sessions an operator or another tool manages directly on the DVS.
+const captureSessionPrefix = "vic-capture-"
+
+// CaptureSession describes an active port mirror set up by StartPacketCapture.
+//
+// This mirrors traffic at the DVS - it does not itself produce a pcap stream. The port layer
+// has no presence in the packet path and no host-side capture agent to hand a stream back
+// from, so getting bytes still requires something listening on DestinationPortKey (a
+// promiscuous-mode monitoring VM's vNIC, for example) to actually write the capture file.
+// What this does provide is the ability to stand that mirror up and, critically, guarantee it
+// tears back down again without an operator needing shell access to the host to do either.
+type CaptureSession struct {
+	Key                string
+	ContainerID        string
+	Network            string
+	SourcePortKey      string
+	DestinationPortKey string
+	Expires            time.Time
+
+	dvs types.ManagedObjectReference
+}
+
+var (
+	captureSessionsLock sync.Mutex
+	captureSessions     = make(map[string]*CaptureSession)
+)
+
+// StartPacketCapture mirrors a container's traffic on networkName to destinationPortKey - a
+// DVS port an operator has already pointed a capture tool at - for duration, after which the
+// mirror is automatically removed. It returns immediately once the mirror is in place; the
+// caller directs the actual capture at destinationPortKey themselves.
+func StartPacketCapture(op trace.Operation, id, networkName, destinationPortKey string, duration time.Duration) (*CaptureSession, error) {
+	defer trace.End(trace.Begin(id, op))
+
+	port, err := resolveContainerDVSPort(op, id, networkName)
+	if err != nil {
+		return nil, err
+	}
+	dvs := port.DVS
+
+	var dvso mo.DistributedVirtualSwitch
+	if err := dvs.Properties(op, dvs.Reference(), []string{"config"}, &dvso); err != nil {
+		return nil, fmt.Errorf("unable to read distributed virtual switch config: %s", err)
+	}
+
+	key := fmt.Sprintf("%s%d", captureSessionPrefix, time.Now().UnixNano())
+
+	spec := &types.VMwareDVSConfigSpec{
+		DVSConfigSpec: types.DVSConfigSpec{
+			ConfigVersion: dvso.Config.GetDVSConfigInfo().ConfigVersion,
+		},
+		VspanConfigSpec: []types.VMwareDVSVspanConfigSpec{
+			{
+				Operation: string(types.ConfigSpecOperationAdd),
+				VspanSession: types.VMwareVspanSession{
+					Key:                   key,
+					Name:                  key,
+					Enabled:               true,
+					NormalTrafficAllowed:  true,
+					SessionType:           string(types.VMwareDVSVspanSessionTypeMixedDestMirror),
+					SourcePortTransmitted: &types.VMwareVspanPort{PortKey: []string{port.Card.Port.PortKey}},
+					SourcePortReceived:    &types.VMwareVspanPort{PortKey: []string{port.Card.Port.PortKey}},
+					DestinationPort:       &types.VMwareVspanPort{PortKey: []string{destinationPortKey}},
+				},
+			},
+		},
+	}
+
+	_, err = tasks.WaitForResultAndRetryIf(op, func(ctx context.Context) (tasks.Task, error) {
+		return dvs.Reconfigure(ctx, spec)
+	}, tasks.IsTransientError)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start packet capture for container %s: %s", id, err)
+	}
+
+	session := &CaptureSession{
+		Key:                key,
+		ContainerID:        id,
+		Network:            networkName,
+		SourcePortKey:      port.Card.Port.PortKey,
+		DestinationPortKey: destinationPortKey,
+		Expires:            time.Now().Add(duration),
+		dvs:                dvs.Reference(),
+	}
+
+	captureSessionsLock.Lock()
+	captureSessions[key] = session
+	captureSessionsLock.Unlock()
+
+	time.AfterFunc(duration, func() {
+		stopOp := trace.NewOperation(context.Background(), "packet capture expiry %s", key)
+		if err := StopPacketCapture(stopOp, key); err != nil {
+			stopOp.Warnf("Failed to automatically stop expired packet capture %s: %s", key, err)
+		}
+	})
+
+	op.Infof("Started packet capture %s on container %s (%s), mirroring to port %s until %s", key, id, networkName, destinationPortKey, session.Expires)
+	return session, nil
+}
+
+// StopPacketCapture removes the mirror session identified by key, whether that's because its
+// time box expired or an operator asked to stop it early.
+func StopPacketCapture(op trace.Operation, key string) error {
+	captureSessionsLock.Lock()
+	session, ok := captureSessions[key]
+	if ok {
+		delete(captureSessions, key)
+	}
+	captureSessionsLock.Unlock()
+
+	if !ok {
+		return NotFoundError{}
+	}
+
+	dvs := object.NewDistributedVirtualSwitch(Config.ResourcePool.Client(), session.dvs)
+
+	spec := &types.VMwareDVSConfigSpec{
+		VspanConfigSpec: []types.VMwareDVSVspanConfigSpec{
+			{
+				Operation: string(types.ConfigSpecOperationRemove),
+				VspanSession: types.VMwareVspanSession{
+					Key: session.Key,
+				},
+			},
+		},
+	}
+
+	_, err := tasks.WaitForResultAndRetryIf(op, func(ctx context.Context) (tasks.Task, error) {
+		return dvs.Reconfigure(ctx, spec)
+	}, tasks.IsTransientError)
+	if err != nil {
+		return fmt.Errorf("unable to stop packet capture %s: %s", key, err)
+	}
+
+	op.Infof("Stopped packet capture %s", key)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/lib/portlayer/event/events"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig/vmomi"
+	"github.com/vmware/vic/pkg/vsphere/session"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+	"github.com/vmware/vic/pkg/vsphere/vm"
+)
+
+// CloneConfig describes a request to clone an existing, committed container into a new one.
+type CloneConfig struct {
+	// Name is the new container's name. It must not collide with an existing container.
+	Name string
+}
+
+// cloneExecConfig returns a copy of src stamped with a new identity - same image/command as
+// the source, but a new ID and name and no network endpoints or running execs of its own, since
+// those belong to the source container's clone-time state, not the new one.
+func cloneExecConfig(src *executor.ExecutorConfig, id, name string) *executor.ExecutorConfig {
+	clone := *src
+	clone.ID = id
+	clone.Name = name
+	clone.CreateTime = time.Now().UTC().Unix()
+	clone.Networks = make(map[string]*executor.NetworkEndpoint)
+	clone.Execs = make(map[string]*executor.SessionConfig)
+
+	return &clone
+}
+
+// Clone creates a new container as a full copy of an existing one's delta disk and config,
+// under a new identity - useful for pulling aside a container's exact on-disk state for
+// debugging without disturbing the original. The clone comes up detached from any network
+// scope; the caller drives the usual AddContainer/BindContainer flow to give it fresh
+// endpoints and IPs, the same as it would for a container freshly created from an image.
+func Clone(ctx context.Context, sess *session.Session, h *Handle, config *CloneConfig) (*Handle, error) {
+	op := trace.FromContext(ctx, "Clone")
+	defer trace.End(trace.Begin(h.ExecConfig.ID, op))
+
+	if h.vm == nil {
+		return nil, fmt.Errorf("cannot clone %s: container has not been created", h.ExecConfig.ID)
+	}
+	if config.Name == "" {
+		return nil, fmt.Errorf("a name must be provided for the cloned container")
+	}
+
+	id := uid.New().String()
+	biosUUID, err := instanceUUID(id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance UUID for cloned container: %s", err)
+	}
+
+	folder, err := h.vm.Folder(op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine folder for cloned container: %s", err)
+	}
+
+	res, err := tasks.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+		return h.vm.Clone(op, folder, config.Name, types.VirtualMachineCloneSpec{
+			PowerOn:  false,
+			Template: false,
+			Config: &types.VirtualMachineConfigSpec{
+				Uuid: biosUUID,
+			},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone container %s: %s", h.ExecConfig.ID, err)
+	}
+
+	clonedVM := vm.NewVirtualMachine(op, sess, res.Result.(types.ManagedObjectReference))
+	clonedVM.DisableDestroy(op)
+
+	newConfig := cloneExecConfig(h.ExecConfig, id, config.Name)
+
+	cfg := make(map[string]string)
+	extraconfig.Encode(extraconfig.MapSink(cfg), newConfig)
+
+	reconfigInfo, err := tasks.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+		return clonedVM.Reconfigure(op, types.VirtualMachineConfigSpec{
+			ExtraConfig: vmomi.OptionValueFromMap(cfg, true),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to stamp identity onto cloned container %s: %s", newConfig.ID, err)
+	}
+
+	base := &containerBase{
+		ExecConfig: newConfig,
+		vm:         clonedVM,
+	}
+
+	c := newContainer(base)
+	Containers.Put(c)
+	c.recordTask(res.Task.Value)
+	c.recordTask(reconfigInfo.Task.Value)
+
+	publishContainerEvent(op, c.ExecConfig.ID, time.Now().UTC(), events.ContainerCreated)
+
+	// newHandle decodes identity from con.Config's ExtraConfig, which we haven't refreshed
+	// from vSphere since the reconfigure above - build the handle directly from what we
+	// already know instead, the same way Create does for a brand new container.
+	return &Handle{
+		key:           newHandleKey(),
+		targetState:   StateCreated,
+		containerBase: *base,
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/lib/config/executor"
+)
+
+func TestCloneExecConfig(t *testing.T) {
+	src := &executor.ExecutorConfig{
+		ExecutorConfigCommon: executor.ExecutorConfigCommon{
+			ID:   "source-id",
+			Name: "source-name",
+		},
+		CreateTime: 1,
+		Networks: map[string]*executor.NetworkEndpoint{
+			"bridge": {},
+		},
+		Execs: map[string]*executor.SessionConfig{
+			"source-id": {},
+		},
+	}
+
+	clone := cloneExecConfig(src, "clone-id", "clone-name")
+
+	assert.Equal(t, "clone-id", clone.ID)
+	assert.Equal(t, "clone-name", clone.Name)
+	assert.NotEqual(t, src.CreateTime, clone.CreateTime)
+	assert.Empty(t, clone.Networks)
+	assert.Empty(t, clone.Execs)
+
+	// the source must be left untouched
+	assert.Equal(t, "source-id", src.ID)
+	assert.NotEmpty(t, src.Networks)
+	assert.NotEmpty(t, src.Execs)
+}
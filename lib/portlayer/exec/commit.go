@@ -16,13 +16,19 @@ package exec
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vic/lib/portlayer/admission"
 	"github.com/vmware/vic/lib/portlayer/event/events"
+	"github.com/vmware/vic/lib/portlayer/journal"
 	"github.com/vmware/vic/pkg/retry"
 	"github.com/vmware/vic/pkg/trace"
 	"github.com/vmware/vic/pkg/vsphere/session"
@@ -30,6 +36,51 @@ import (
 	"github.com/vmware/vic/pkg/vsphere/vm"
 )
 
+// dockerLabelsAnnotation is the ExecutorConfig.Annotations key under which docker labels are
+// stashed as a base64'd json blob - this must stay in sync with AnnotationKeyLabels in
+// lib/apiservers/engine/backends/convert.
+const dockerLabelsAnnotation = "docker.labels"
+
+// Phases journaled for a container create. They exist purely for the journal reconciler's
+// log messages - recovery decisions are made on whether the VM made it into inventory, not
+// on which of these phases was last recorded.
+const (
+	journalPhasePlacing     = journal.Phase("placing")
+	journalPhaseRegistering = journal.Phase("registering")
+)
+
+// decodeDockerLabels extracts the docker labels stashed in annotations by the engine
+// personality layer. It returns a nil map, not an error, if no labels are present.
+func decodeDockerLabels(annotations map[string]string) (map[string]string, error) {
+	blob, ok := annotations[dockerLabelsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal(raw, &labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// encodeDockerLabels is the inverse of decodeDockerLabels, for callers that need to
+// write an updated label set back into annotations.
+func encodeDockerLabels(labels map[string]string) (string, error) {
+	raw, err := json.Marshal(labels)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
 // Commit executes the requires steps on the handle
 func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int32) error {
 	op := trace.FromContext(ctx, "Commit")
@@ -56,18 +107,44 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 			return fmt.Errorf("a container already exists in the cache with this ID")
 		}
 
+		// release the admission reservation taken out for this ID in admission.Review,
+		// regardless of outcome - on success the container is in Containers by the time
+		// this runs, so containerCount() covers it from here on; on failure it never will.
+		defer admission.Release(h.ExecConfig.ID)
+
+		publishContainerEvent(op, h.ExecConfig.ID, time.Now().UTC(), events.ContainerCreatePlacing)
+
+		host, err := SelectPlacementHost(op, sess)
+		if err != nil {
+			return err
+		}
+
+		if err := checkImageStoreHealth(op, sess); err != nil {
+			return err
+		}
+
+		if Journal != nil {
+			if jerr := Journal.Begin(op, journal.OpCreate, h.ExecConfig.ID, journalPhasePlacing); jerr != nil {
+				op.Warnf("Failed to journal create of %s, continuing without crash recovery for this operation: %s", h.ExecConfig.ID, jerr)
+			}
+		}
+
+		release := acquireCreateSlot(op)
+		defer release()
+
 		var res *types.TaskInfo
-		var err error
+
+		publishContainerEvent(op, h.ExecConfig.ID, time.Now().UTC(), events.ContainerCreatingVM)
 
 		if sess.IsVC() && Config.VirtualApp.ResourcePool != nil {
 			// Create the vm
 			res, err = tasks.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
-				return Config.VirtualApp.CreateChildVM(op, *h.Spec.Spec(), nil)
+				return Config.VirtualApp.CreateChildVM(op, *h.Spec.Spec(), host)
 			})
 		} else {
 			// Create the vm
 			res, err = tasks.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
-				return sess.VCHFolder.CreateVM(op, *h.Spec.Spec(), Config.ResourcePool, nil)
+				return sess.VCHFolder.CreateVM(op, *h.Spec.Spec(), Config.ResourcePool, host)
 			})
 		}
 
@@ -77,7 +154,22 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 		}
 		h.vm = vm.NewVirtualMachine(op, sess, res.Result.(types.ManagedObjectReference))
 
+		if Journal != nil {
+			if jerr := Journal.Advance(op, h.ExecConfig.ID, journalPhaseRegistering); jerr != nil {
+				op.Warnf("Failed to advance create journal for %s: %s", h.ExecConfig.ID, jerr)
+			}
+		}
+
 		h.vm.DisableDestroy(op)
+
+		if h.NoConsole {
+			if err := stripVideoCard(op, h.vm); err != nil {
+				op.Warnf("Unable to remove virtual console for %s: %s", h.ExecConfig.ID, err)
+			}
+		}
+
+		publishContainerEvent(op, h.ExecConfig.ID, time.Now().UTC(), events.ContainerCreateFinalizing)
+
 		c = newContainer(&h.containerBase)
 
 		var o mo.VirtualMachine
@@ -87,9 +179,14 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 		}
 		c.MemorySizeMB = o.Summary.Config.MemorySizeMB
 		c.NumCPU = o.Summary.Config.NumCpu
+		c.recordTask(res.Task.Value)
 
 		Containers.Put(c)
 
+		cfm := object.NewCustomFieldsManager(sess.Vim25())
+		stampContainerMetadata(op, sess, cfm, c, h.vm)
+		syncContainerLabels(op, cfm, c, h.vm)
+
 		err = Config.addToVMGroup(op)
 		if err != nil {
 			op.Errorf("Failed to add VM to VMGroup: %s", err)
@@ -99,6 +196,12 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 		// inform of creation irrespective of remaining operations
 		publishContainerEvent(op, c.ExecConfig.ID, time.Now().UTC(), events.ContainerCreated)
 
+		if Journal != nil {
+			if jerr := Journal.Complete(op, h.ExecConfig.ID); jerr != nil {
+				op.Warnf("Failed to clear create journal for %s: %s", h.ExecConfig.ID, jerr)
+			}
+		}
+
 		// clear the spec as we've acted on it - this prevents a reconfigure from occurring in follow-on
 		// processing
 		h.Spec = nil
@@ -129,6 +232,20 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 		}
 	}
 
+	// suspend before any reconfigure, same rationale as the stop case above - ExtraConfig
+	// writes need a settled power state.
+	if h.TargetState() == StateSuspended {
+		if h.Runtime == nil {
+			op.Warnf("Commit called with incomplete runtime state for %s", h.ExecConfig.ID)
+		} else if h.Runtime.PowerState == types.VirtualMachinePowerStateSuspended {
+			op.Infof("Dropping duplicate suspend operation for %s", h.ExecConfig.ID)
+		} else if err := c.pause(op); err != nil {
+			return err
+		} else {
+			h.refresh(op)
+		}
+	}
+
 	// reconfigure operation
 	if h.Spec != nil {
 		if h.Runtime == nil {
@@ -166,9 +283,12 @@ func Commit(ctx context.Context, sess *session.Session, h *Handle, waitTime *int
 				return ConcurrentAccessError{errors.New(detail)}
 			}
 
-			_, err := h.vm.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+			info, err := h.vm.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
 				return h.vm.Reconfigure(op, *s)
 			})
+			if err == nil {
+				c.recordTask(info.Task.Value)
+			}
 			if err != nil {
 				op.Errorf("Reconfigure: failed update to %s with change version %s: %+v", h.ExecConfig.ID, s.ChangeVersion, err)
 
@@ -307,6 +427,96 @@ func reconfigureVMGroup(op trace.Operation) error {
 	return err
 }
 
+// stampContainerMetadata unconditionally sets a fixed set of vSphere custom fields (OwnerVCH,
+// CreatedBy, Image) on the container VM at creation time, so that in multi-VCH environments a
+// reconciliation job can attribute a stray container VM back to its owning VCH and image without
+// needing guest or ExtraConfig access.
+func stampContainerMetadata(op trace.Operation, sess *session.Session, cfm *object.CustomFieldsManager, c *Container, cvm *vm.VirtualMachine) {
+	// the portlayer runs inside the VCH appliance VM, so its own hostname identifies the
+	// owning VCH without an extra round-trip to look up the endpointVM's display name
+	ownerVCH, err := os.Hostname()
+	if err != nil {
+		op.Warnf("Unable to determine VCH hostname while stamping metadata for %s: %s", c.ExecConfig.ID, err)
+	}
+
+	fields := map[string]string{
+		"OwnerVCH":  ownerVCH,
+		"CreatedBy": sess.User.Username(),
+		"Image":     c.ExecConfig.ImageID,
+	}
+
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+
+		key, err := customFieldKey(op, cfm, name)
+		if err != nil {
+			op.Warnf("Unable to resolve custom field %q while stamping metadata for %s: %s", name, c.ExecConfig.ID, err)
+			continue
+		}
+
+		if err := cfm.Set(op, cvm.Reference(), key, value); err != nil {
+			op.Warnf("Unable to set custom field %q on %s: %s", name, c.ExecConfig.ID, err)
+		}
+	}
+}
+
+// syncContainerLabels mirrors the docker labels named in Config.Container.SyncContainerLabels
+// onto the container VM as vSphere custom fields, so vSphere-side automation (backup policies,
+// compliance scans) can target containers by label. This is best-effort - a failure to sync
+// should not fail container creation.
+func syncContainerLabels(op trace.Operation, cfm *object.CustomFieldsManager, c *Container, cvm *vm.VirtualMachine) {
+	if len(Config.Container.SyncContainerLabels) == 0 {
+		return
+	}
+
+	labels, err := decodeDockerLabels(c.ExecConfig.Annotations)
+	if err != nil {
+		op.Warnf("Unable to decode docker labels while syncing custom fields for %s: %s", c.ExecConfig.ID, err)
+		return
+	}
+	if labels == nil {
+		return
+	}
+
+	for _, name := range Config.Container.SyncContainerLabels {
+		value, ok := labels[name]
+		if !ok {
+			continue
+		}
+
+		key, err := customFieldKey(op, cfm, name)
+		if err != nil {
+			op.Warnf("Unable to resolve custom field %q while syncing labels for %s: %s", name, c.ExecConfig.ID, err)
+			continue
+		}
+
+		if err := cfm.Set(op, cvm.Reference(), key, value); err != nil {
+			op.Warnf("Unable to set custom field %q on %s: %s", name, c.ExecConfig.ID, err)
+		}
+	}
+}
+
+// customFieldKey returns the key for the named VirtualMachine custom field, creating the
+// field definition if it doesn't already exist.
+func customFieldKey(op trace.Operation, cfm *object.CustomFieldsManager, name string) (int32, error) {
+	key, err := cfm.FindKey(op, name)
+	if err == nil {
+		return key, nil
+	}
+	if err != object.ErrKeyNameNotFound {
+		return 0, err
+	}
+
+	def, err := cfm.Add(op, name, "VirtualMachine", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return def.Key, nil
+}
+
 // HELPER FUNCTIONS BELOW
 
 // reloadConfig is responsible for triggering a guest_reconfigure in order to perform an operation on a running cVM
@@ -0,0 +1,96 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+	"github.com/vmware/vic/pkg/vsphere/vm"
+)
+
+// findVideoCard returns the containerVM's virtual video card device, if it has one.
+func findVideoCard(devices []types.BaseVirtualDevice) (*types.VirtualMachineVideoCard, bool) {
+	for _, d := range devices {
+		if card, ok := d.(*types.VirtualMachineVideoCard); ok {
+			return card, true
+		}
+	}
+	return nil, false
+}
+
+// SetConsoleEnabled adds or removes the container's virtual video card, so a container
+// provisioned serial-only for density (see ContainerCreateConfig.NoConsole) can have a console
+// temporarily restored for interactive debugging, then removed again once done. Like
+// SetDiskIOLimit, this only mutates the handle - it's up to the caller to Commit it.
+func (h *Handle) SetConsoleEnabled(op trace.Operation, enabled bool) error {
+	defer trace.End(trace.Begin(h.ExecConfig.ID, op))
+
+	if h.Config == nil {
+		return fmt.Errorf("container %s has no committed configuration to reconfigure", h.ExecConfig.ID)
+	}
+
+	card, found := findVideoCard(h.Config.Hardware.Device)
+
+	if enabled {
+		if found {
+			return nil
+		}
+		h.Spec.AddVirtualDevice(&types.VirtualMachineVideoCard{})
+		return nil
+	}
+
+	if !found {
+		return nil
+	}
+
+	h.Spec.RemoveVirtualDevice(card)
+	return nil
+}
+
+// stripVideoCard removes a freshly created containerVM's virtual video card via its own
+// Reconfigure, for containers created with NoConsole set. The device vSphere adds to a new VM
+// isn't known until after creation completes, so unlike other devices it can't be excluded
+// from the initial create spec - this is the same create-then-reconfigure shape Clone uses to
+// stamp identity onto a cloned VM.
+func stripVideoCard(op trace.Operation, containerVM *vm.VirtualMachine) error {
+	var o mo.VirtualMachine
+	if err := containerVM.Properties(op, containerVM.Reference(), []string{"config.hardware.device"}, &o); err != nil {
+		return fmt.Errorf("unable to retrieve hardware devices: %s", err)
+	}
+
+	card, found := findVideoCard(o.Config.Hardware.Device)
+	if !found {
+		return nil
+	}
+
+	_, err := tasks.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+		return containerVM.Reconfigure(op, types.VirtualMachineConfigSpec{
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationRemove,
+					Device:    card,
+				},
+			},
+		})
+	})
+
+	return err
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/vmware/vic/lib/iolog"
 	"github.com/vmware/vic/lib/portlayer/event/events"
 	stateevents "github.com/vmware/vic/lib/portlayer/event/events/vsphere"
+	"github.com/vmware/vic/lib/portlayer/journal"
 	"github.com/vmware/vic/lib/tether/shared"
 	"github.com/vmware/vic/pkg/errors"
 	"github.com/vmware/vic/pkg/trace"
@@ -84,12 +85,56 @@ func (s State) String() string {
 		return "Stopping"
 	case StateStopped:
 		return "Stopped"
+	case StateSuspending:
+		return "Suspending"
+	case StateSuspended:
+		return "Suspended"
+	case StateCreating:
+		return "Creating"
 	case StateUnknown:
 		return "Unknown"
 	}
 	return ""
 }
 
+// InvalidStateTransitionError is returned when a caller requests a target state that
+// cannot legally be reached from a container's current state.
+type InvalidStateTransitionError struct {
+	From State
+	To   State
+}
+
+func (e InvalidStateTransitionError) Error() string {
+	return fmt.Sprintf("cannot transition container from %s to %s", e.From, e.To)
+}
+
+func IsInvalidStateTransitionError(err error) bool {
+	_, ok := err.(InvalidStateTransitionError)
+	return ok
+}
+
+// validTargetStates enumerates, for each target state a caller may request via
+// Handle.SetTargetState, the current states from which that request is legal.
+// StateRemoving/StateRemoved are excluded - those are only ever driven internally
+// by vSphere inventory events, never requested directly through the API.
+var validTargetStates = map[State][]State{
+	StateCreated:   {StateCreated},
+	StateRunning:   {StateCreated, StateStopped, StateStopping, StateRunning, StateStarting, StateSuspended},
+	StateStopped:   {StateCreated, StateStarting, StateRunning, StateStopping, StateStopped},
+	StateSuspended: {StateRunning, StateSuspending, StateSuspended},
+}
+
+// ValidateTargetState returns an InvalidStateTransitionError if a container currently
+// in current cannot legally be asked to transition to target.
+func ValidateTargetState(current, target State) error {
+	for _, allowed := range validTargetStates[target] {
+		if allowed == current {
+			return nil
+		}
+	}
+	return InvalidStateTransitionError{From: current, To: target}
+}
+
 // NotFoundError is returned when a types.ManagedObjectNotFound is returned from a vmomi call
 type NotFoundError struct {
 	err error
@@ -109,6 +154,18 @@ func IsNotFoundError(err error) bool {
 	return false
 }
 
+// IsDuplicateNameError returns true if err is a vSphere DuplicateName fault, as returned
+// by CreateVM when another VM already exists with the requested name.
+func IsDuplicateNameError(err error) bool {
+	if soap.IsSoapFault(err) {
+		fault := soap.ToSoapFault(err).VimFault()
+		if _, ok := fault.(types.DuplicateName); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // RemovePowerError is returned when attempting to remove a containerVM that is powered on
 type RemovePowerError struct {
 	err error
@@ -140,6 +197,17 @@ func (e DevicesInUseError) Error() string {
 	return fmt.Sprintf("device %s in use", strings.Join(e.Devices, ","))
 }
 
+// AmbiguousIDError is returned when an ID prefix or name given to
+// containerCache.Resolve matches more than one container.
+type AmbiguousIDError struct {
+	Query string
+	IDs   []string
+}
+
+func (e AmbiguousIDError) Error() string {
+	return fmt.Sprintf("ambiguous container reference %q matches %s", e.Query, strings.Join(e.IDs, ", "))
+}
+
 // Container is used to return data about a container during inspection calls
 // It is a copy rather than a live reflection and does not require locking
 type ContainerInfo struct {
@@ -152,6 +220,38 @@ type ContainerInfo struct {
 
 	// Size of the leaf (unused)
 	VMUnsharedDisk int64
+
+	// TaskHistory holds the vSphere task keys (types.TaskInfo.Task.Value) of the most recent
+	// operations performed against this container's VM, newest last, so an admin looking at
+	// inspect output can jump straight to the corresponding task/events in vCenter when
+	// investigating a failure. Bounded by maxTaskHistory.
+	TaskHistory []string
+
+	// Generation increments every time this container's cache entry is mutated (state
+	// transition, refresh from vSphere, or commit from a handle). Info() copies it out
+	// alongside the rest of ContainerInfo, so two inspects can be compared to tell whether
+	// anything changed between them without diffing the full payload.
+	Generation uint64
+}
+
+// maxTaskHistory bounds ContainerInfo.TaskHistory so a long-lived container doesn't
+// accumulate an unbounded task list.
+const maxTaskHistory = 20
+
+// recordTask appends a vSphere task key to the container's task history, trimming the
+// oldest entries once maxTaskHistory is exceeded.
+func (c *Container) recordTask(key string) {
+	if key == "" {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.TaskHistory = append(c.TaskHistory, key)
+	if len(c.TaskHistory) > maxTaskHistory {
+		c.TaskHistory = c.TaskHistory[len(c.TaskHistory)-maxTaskHistory:]
+	}
 }
 
 // Container is used for an entry in the container cache - this is a "live" representation
@@ -227,6 +327,19 @@ func GetContainer(ctx context.Context, id uid.UID) (*Handle, error) {
 	return nil, nil
 }
 
+// ResolveContainer is GetContainer for callers that only have a short ID prefix or a
+// friendly name rather than the full container ID, e.g. a client acting on
+// ContainerCreatedInfo.ID before it has queried anything else about the container.
+// Returns AmbiguousIDError if idOrName isn't specific enough to name one container.
+func ResolveContainer(ctx context.Context, idOrName string) (*Handle, error) {
+	container, err := Containers.Resolve(idOrName)
+	if err != nil || container == nil {
+		return nil, err
+	}
+
+	return container.NewHandle(ctx)
+}
+
 func (c *ContainerInfo) String() string {
 	return c.ExecConfig.ID
 }
@@ -269,6 +382,7 @@ func (c *Container) updateState(op trace.Operation, s State) State {
 	prevState := c.state
 	if s != c.state {
 		c.state = s
+		c.Generation++
 		if ch, ok := c.newStateEvents[s]; ok {
 			delete(c.newStateEvents, s)
 			close(ch)
@@ -343,6 +457,7 @@ func (c *Container) Refresh(op trace.Operation) error {
 	if err := c.refresh(op); err != nil {
 		return err
 	}
+	c.Generation++
 
 	// conditionally sync state (see issue 4872, 6372)
 	event := stateevents.NewStateEvent(op, c.containerBase.Runtime.PowerState, c.VMReference())
@@ -378,6 +493,7 @@ func (c *Container) RefreshFromHandle(op trace.Operation, h *Handle) {
 
 	// copy over the new state
 	c.containerBase = h.containerBase
+	c.Generation++
 	if c.Config != nil {
 		op.Debugf("Update: updated change version from handle: %s", c.Config.ChangeVersion)
 	}
@@ -481,6 +597,28 @@ func (c *Container) stop(op trace.Operation, waitTime *int32) error {
 	return nil
 }
 
+// pause suspends the container VM, preserving its memory and device state so resume is a
+// PowerOn rather than a full boot.
+func (c *Container) pause(op trace.Operation) error {
+	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
+
+	finalState := c.SetState(op, StateSuspending)
+
+	if err := c.containerBase.suspend(op); err != nil {
+		if stateErr := c.transitionState(op, StateSuspending, finalState); stateErr != nil {
+			op.Debugf(stateErr.Error())
+		}
+
+		return err
+	}
+
+	if err := c.transitionState(op, StateSuspending, StateSuspended); err != nil {
+		op.Debugf(err.Error())
+	}
+
+	return nil
+}
+
 func (c *Container) Signal(op trace.Operation, num int64) error {
 	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
 
@@ -495,6 +633,18 @@ func (c *Container) Signal(op trace.Operation, num int64) error {
 	return c.startGuestProgram(op, shared.GuestActionKill, fmt.Sprintf("%d", num))
 }
 
+// ConsoleTicket acquires a one-time console access ticket (e.g. "webmks") for this
+// container's VM, suitable for handing to a browser-based or VMRC console client.
+func (c *Container) ConsoleTicket(op trace.Operation, kind string) (*types.VirtualMachineTicket, error) {
+	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
+
+	if c.vm == nil {
+		return nil, fmt.Errorf("vm not set")
+	}
+
+	return c.vm.AcquireTicket(op, kind)
+}
+
 func (c *Container) onStop() {
 	lf := c.logFollowers
 	c.logFollowers = nil
@@ -588,6 +738,11 @@ func (c *Container) LogReader(op trace.Operation, tail int, follow bool, since i
 	return file, nil
 }
 
+// journalPhaseDeletingFiles is the only phase journaled for a container remove - unlike create,
+// remove doesn't have a meaningfully observable midpoint before the point of no return, so this
+// exists mainly so a pending remove entry logs something more informative than an empty phase.
+const journalPhaseDeletingFiles = journal.Phase("deleting-files")
+
 // Remove removes a containerVM after detaching the disks
 func (c *Container) Remove(op trace.Operation, sess *session.Session) error {
 	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
@@ -607,6 +762,12 @@ func (c *Container) Remove(op trace.Operation, sess *session.Session) error {
 	// if there's a failure we'll revert to existing
 	existingState := c.updateState(op, StateRemoving)
 
+	if Journal != nil {
+		if jerr := Journal.Begin(op, journal.OpRemove, c.ExecConfig.ID, journalPhaseDeletingFiles); jerr != nil {
+			op.Warnf("Failed to journal remove of %s, continuing without crash recovery for this operation: %s", c.ExecConfig.ID, jerr)
+		}
+	}
+
 	// get the folder the VM is in
 	url, err := c.vm.VMPathNameAsURL(op)
 	if err != nil {
@@ -614,6 +775,11 @@ func (c *Container) Remove(op trace.Operation, sess *session.Session) error {
 		// handle the out-of-band removal case
 		if IsNotFoundError(err) {
 			Containers.Remove(c.ExecConfig.ID)
+			if Journal != nil {
+				if jerr := Journal.Complete(op, c.ExecConfig.ID); jerr != nil {
+					op.Warnf("Failed to clear remove journal for %s: %s", c.ExecConfig.ID, jerr)
+				}
+			}
 			return NotFoundError{}
 		}
 
@@ -685,6 +851,12 @@ func (c *Container) Remove(op trace.Operation, sess *session.Session) error {
 	Containers.Remove(c.ExecConfig.ID)
 	publishContainerEvent(op, c.ExecConfig.ID, time.Now(), events.ContainerRemoved)
 
+	if Journal != nil {
+		if jerr := Journal.Complete(op, c.ExecConfig.ID); jerr != nil {
+			op.Warnf("Failed to clear remove journal for %s: %s", c.ExecConfig.ID, jerr)
+		}
+	}
+
 	return nil
 }
 
@@ -935,8 +1107,12 @@ func convertInfraContainers(ctx context.Context, sess *session.Session, vms []mo
 	var cons []*Container
 
 	for _, v := range vms {
-		vm := vm.NewVirtualMachine(ctx, sess, v.Reference())
-		base := newBase(vm, v.Config, &v.Runtime)
+		// Deliberately avoid vm.NewVirtualMachine here - it does a Finder.Element lookup to
+		// populate InventoryPath, which would turn this bulk conversion of an already-fetched
+		// property collector batch back into one round trip per VM. Nothing below needs
+		// InventoryPath; callers that do (e.g. VM removal) resolve it themselves.
+		vmObj := vm.NewVirtualMachineFromVM(ctx, sess, object.NewVirtualMachine(sess.Vim25(), v.Reference()))
+		base := newBase(vmObj, v.Config, &v.Runtime)
 		c := newContainer(base)
 
 		id := uid.Parse(c.ExecConfig.ID)
@@ -15,10 +15,13 @@
 package exec
 
 import (
+	"strings"
 	"sync"
 
 	"context"
 
+	log "github.com/Sirupsen/logrus"
+
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/vic/pkg/uid"
 	"github.com/vmware/vic/pkg/vsphere/session"
@@ -55,6 +58,48 @@ func (conCache *containerCache) Container(idOrRef string) *Container {
 	return conCache.cache[idOrRef]
 }
 
+// Resolve looks up a container the way a user would identify it: by exact ID or
+// moref, by an unambiguous ID prefix, or by its friendly name. It's the same
+// short-form resolution docker itself does for container IDs.
+//
+// A prefix or name that matches more than one container returns AmbiguousIDError
+// rather than picking one at random. A query that matches nothing returns nil, nil -
+// callers report that as NotFound with whatever detail they have.
+func (conCache *containerCache) Resolve(idOrName string) (*Container, error) {
+	if c := conCache.Container(idOrName); c != nil {
+		return c, nil
+	}
+
+	conCache.m.RLock()
+	defer conCache.m.RUnlock()
+
+	matches := make(map[string]*Container)
+	for id, con := range conCache.cache {
+		if !isContainerID(id) {
+			continue
+		}
+
+		if strings.HasPrefix(id, idOrName) || con.ExecConfig.Name == idOrName {
+			matches[id] = con
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		for _, con := range matches {
+			return con, nil
+		}
+	}
+
+	ids := make([]string, 0, len(matches))
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	return nil, AmbiguousIDError{Query: idOrName, IDs: ids}
+}
+
 func (conCache *containerCache) Containers(states []State) []*Container {
 	conCache.m.RLock()
 	defer conCache.m.RUnlock()
@@ -148,10 +193,15 @@ func (conCache *containerCache) sync(ctx context.Context, sess *session.Session)
 	}
 
 	conCache.cache = make(map[string]*Container)
+
+	byState := make(map[State]int)
 	for _, c := range cons {
 		conCache.put(c)
+		byState[c.State()]++
 	}
 
+	log.Infof("Container cache sync recovered %d containers from vSphere inventory: %v", len(cons), byState)
+
 	return nil
 }
 
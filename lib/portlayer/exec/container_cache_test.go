@@ -15,6 +15,7 @@
 package exec
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,67 @@ func TestIsContainerID(t *testing.T) {
 	assert.False(t, isContainerID(invalidID))
 }
 
+func TestResolveExactAndPrefix(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	id := uid.New().String()
+	container := newTestContainer(id)
+	addTestVM(container)
+	Containers.Put(container)
+
+	found, err := Containers.Resolve(id)
+	assert.NoError(t, err)
+	assert.Equal(t, container, found)
+
+	found, err = Containers.Resolve(id[:8])
+	assert.NoError(t, err)
+	assert.Equal(t, container, found)
+}
+
+func TestResolveByName(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	id := uid.New().String()
+	container := newTestContainer(id)
+	container.ExecConfig.Name = "my-container"
+	addTestVM(container)
+	Containers.Put(container)
+
+	found, err := Containers.Resolve("my-container")
+	assert.NoError(t, err)
+	assert.Equal(t, container, found)
+}
+
+func TestResolveAmbiguousPrefix(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	prefix := uid.New().String()[:8]
+
+	first := newTestContainer(prefix + strings.Repeat("1", 64-len(prefix)))
+	addTestVM(first)
+	Containers.Put(first)
+
+	second := newTestContainer(prefix + strings.Repeat("2", 64-len(prefix)))
+	addTestVM(second)
+	Containers.Put(second)
+
+	_, err := Containers.Resolve(prefix)
+	assert.Error(t, err)
+	assert.IsType(t, AmbiguousIDError{}, err)
+}
+
+func TestResolveNotFound(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	found, err := Containers.Resolve("does-not-exist")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
 // addTestVM will add a pseudo VM to the container
 func addTestVM(container *Container) {
 	mo := types.ManagedObjectReference{Type: "vm", Value: "12"}
@@ -15,11 +15,21 @@
 package exec
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig"
+	"github.com/vmware/vic/pkg/vsphere/extraconfig/vmomi"
+	"github.com/vmware/vic/pkg/vsphere/session"
 )
 
 func TestStateStringer(t *testing.T) {
@@ -43,6 +53,70 @@ func TestStateStringer(t *testing.T) {
 	assert.Equal(t, "Created", c.state.String())
 }
 
+func TestRecordTask(t *testing.T) {
+	c := &Container{}
+
+	c.recordTask("")
+	assert.Empty(t, c.TaskHistory)
+
+	for i := 0; i < maxTaskHistory+5; i++ {
+		c.recordTask(fmt.Sprintf("task-%d", i))
+	}
+
+	assert.Len(t, c.TaskHistory, maxTaskHistory)
+	assert.Equal(t, "task-5", c.TaskHistory[0])
+	assert.Equal(t, fmt.Sprintf("task-%d", maxTaskHistory+4), c.TaskHistory[len(c.TaskHistory)-1])
+}
+
+// vicVirtualMachine builds a mo.VirtualMachine carrying the vic executor
+// metadata a real container VM would have in ExtraConfig.
+func vicVirtualMachine(id string) mo.VirtualMachine {
+	metadata := &executor.ExecutorConfig{}
+	metadata.ID = id
+
+	cfg := make(map[string]string)
+	extraconfig.Encode(extraconfig.MapSink(cfg), metadata)
+
+	return mo.VirtualMachine{
+		ManagedEntity: mo.ManagedEntity{
+			ExtensibleManagedObject: mo.ExtensibleManagedObject{
+				Self: types.ManagedObjectReference{Type: "VirtualMachine", Value: id},
+			},
+		},
+		Config: &types.VirtualMachineConfigInfo{
+			ExtraConfig: vmomi.OptionValueFromMap(cfg, true),
+		},
+	}
+}
+
+// TestConvertInfraContainersIsPureTransform verifies convertInfraContainers only
+// interprets the mo.VirtualMachine batch it's handed - it must not make any
+// additional per-VM calls (e.g. a Finder lookup) - and that VMs carrying no vic
+// metadata are skipped rather than fed into the cache as bogus containers.
+func TestConvertInfraContainersIsPureTransform(t *testing.T) {
+	vms := []mo.VirtualMachine{
+		vicVirtualMachine("deadbeefcafe"),
+		{
+			// a plain, non-container VM sharing the same folder
+			ManagedEntity: mo.ManagedEntity{
+				ExtensibleManagedObject: mo.ExtensibleManagedObject{
+					Self: types.ManagedObjectReference{Type: "VirtualMachine", Value: "not-a-container"},
+				},
+			},
+			Config: &types.VirtualMachineConfigInfo{},
+		},
+	}
+
+	// A session with no real vim25.Client - convertInfraContainers must not touch it beyond
+	// storing a reference, since nothing here should dial back out to vCenter per VM.
+	sess := &session.Session{Client: &govmomi.Client{}}
+
+	cons := convertInfraContainers(context.Background(), sess, vms)
+
+	assert.Len(t, cons, 1)
+	assert.Equal(t, "deadbeefcafe", cons[0].ExecConfig.ID)
+}
+
 func NewContainer(id uid.UID) *Handle {
 	con := &Container{
 		ContainerInfo: ContainerInfo{
@@ -0,0 +1,101 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// ParseCPUSet parses a docker cpuset-cpus style string, e.g. "0-2,4", into the
+// sorted, deduplicated list of physical CPU IDs it names. An empty string yields
+// a nil slice - no affinity requested.
+func ParseCPUSet(cpuset string) ([]int32, error) {
+	if cpuset == "" {
+		return nil, nil
+	}
+
+	seen := make(map[int32]bool)
+	var ids []int32
+	for _, part := range strings.Split(cpuset, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid cpuset %q: empty entry", cpuset)
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		lo, err := strconv.Atoi(bounds[0])
+		if err != nil || lo < 0 {
+			return nil, fmt.Errorf("invalid cpuset %q: bad cpu id %q", cpuset, bounds[0])
+		}
+
+		hi := lo
+		if len(bounds) == 2 {
+			hi, err = strconv.Atoi(bounds[1])
+			if err != nil || hi < lo {
+				return nil, fmt.Errorf("invalid cpuset %q: bad range %q", cpuset, part)
+			}
+		}
+
+		for id := lo; id <= hi; id++ {
+			if !seen[int32(id)] {
+				seen[int32(id)] = true
+				ids = append(ids, int32(id))
+			}
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
+
+// requireSingleHostForAffinity rejects CPU pinning requests on a multi-host compute
+// resource. Physical CPU IDs are host-specific, and DRS is free to vMotion a container
+// between hosts at any time - on a cluster that would silently repin the container to
+// the wrong physical cores, or to cores that don't exist, rather than honoring the
+// request. A standalone host (or a one-host cluster) has nowhere else to move to, so
+// the pinning is guaranteed to keep meaning what the caller asked for.
+func requireSingleHostForAffinity(op trace.Operation) error {
+	return requireSingleHost(op, "CPU pinning")
+}
+
+// requireSingleHost rejects a request to use a feature that ties a container VM to
+// specifics of the host it happens to land on (pinned physical CPUs, a disk that only
+// exists on one host's local storage). DRS is free to vMotion a container between
+// hosts of a cluster at any time, which would silently invalidate that kind of request.
+// A standalone host (or a one-host cluster) has nowhere else to move to, so the request
+// is guaranteed to keep meaning what the caller asked for. feature is named in the
+// returned error to identify which request triggered the check.
+func requireSingleHost(op trace.Operation, feature string) error {
+	if Config.Cluster == nil {
+		return fmt.Errorf("%s requires a known compute resource", feature)
+	}
+
+	hosts, err := Config.Cluster.Hosts(op)
+	if err != nil {
+		return fmt.Errorf("unable to determine hosts for %s: %s", feature, err)
+	}
+
+	if len(hosts) != 1 {
+		return fmt.Errorf("%s is only supported on a single-host VCH; this VCH's compute resource has %d hosts", feature, len(hosts))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPUSetEmpty(t *testing.T) {
+	ids, err := ParseCPUSet("")
+	assert.NoError(t, err)
+	assert.Nil(t, ids)
+}
+
+func TestParseCPUSetSingleAndRange(t *testing.T) {
+	ids, err := ParseCPUSet("4,0-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2, 4}, ids)
+}
+
+func TestParseCPUSetDedups(t *testing.T) {
+	ids, err := ParseCPUSet("0-2,1")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2}, ids)
+}
+
+func TestParseCPUSetRejectsInvalid(t *testing.T) {
+	_, err := ParseCPUSet("0-")
+	assert.Error(t, err)
+
+	_, err = ParseCPUSet("a")
+	assert.Error(t, err)
+
+	_, err = ParseCPUSet("2-1")
+	assert.Error(t, err)
+}
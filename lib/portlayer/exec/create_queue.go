@@ -0,0 +1,145 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"sync"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// priority classifies a createGate waiter so that a handful of interactive lifecycle
+// operations - namely stop - aren't stuck behind a large wave of queued bulk creates.
+type priority int
+
+const (
+	// priorityInteractive is used by operations a human or CLI is actively waiting on.
+	priorityInteractive priority = iota
+	// priorityBatch is used by bulk operations, e.g. CreateVM/PowerOn during Commit.
+	priorityBatch
+)
+
+// createGate bounds how many CreateVM/PowerOn/PowerOff tasks Commit and stop will have
+// outstanding against vCenter at once, per Config.MaxConcurrentCreates, so a large parallel
+// deployment wave can't overwhelm vCenter with thousands of simultaneous tasks. Callers queue
+// for a slot via acquire; among waiters, priorityInteractive is always dispatched ahead of
+// priorityBatch, so an interactive stop doesn't wait behind a backlog of queued creates.
+type createGate struct {
+	mu       sync.Mutex
+	capacity int
+	active   int
+
+	interactive []chan struct{}
+	batch       []chan struct{}
+}
+
+// newCreateGate builds a createGate that admits up to size concurrent holders. size <= 0 means
+// unbounded - acquire always succeeds immediately and reports position 0.
+func newCreateGate(size int) *createGate {
+	return &createGate{capacity: size}
+}
+
+// acquire blocks until a slot is available, returning the caller's queue position at the time
+// it started waiting (0 if it didn't have to wait at all) and a release func the caller must
+// call once its task has been submitted.
+func (g *createGate) acquire(prio priority) (position int, release func()) {
+	if g.capacity <= 0 {
+		return 0, func() {}
+	}
+
+	g.mu.Lock()
+	ready := make(chan struct{})
+	if prio == priorityInteractive {
+		g.interactive = append(g.interactive, ready)
+	} else {
+		g.batch = append(g.batch, ready)
+	}
+	position = len(g.interactive) + len(g.batch)
+	g.dispatch()
+	select {
+	case <-ready:
+		// admitted straight away - the caller never waited
+		position = 0
+	default:
+	}
+	g.mu.Unlock()
+
+	<-ready
+
+	return position, func() {
+		g.mu.Lock()
+		g.active--
+		g.dispatch()
+		g.mu.Unlock()
+	}
+}
+
+// dispatch admits as many queued waiters as the current capacity allows, always preferring
+// interactive waiters over batch ones. Callers must hold g.mu.
+func (g *createGate) dispatch() {
+	for g.active < g.capacity {
+		var next chan struct{}
+		switch {
+		case len(g.interactive) > 0:
+			next, g.interactive = g.interactive[0], g.interactive[1:]
+		case len(g.batch) > 0:
+			next, g.batch = g.batch[0], g.batch[1:]
+		default:
+			return
+		}
+
+		g.active++
+		close(next)
+	}
+}
+
+// createQueue is the gate Commit and stop acquire before submitting a CreateVM/PowerOn/
+// PowerOff task. It's built lazily from Config.MaxConcurrentCreates the first time it's
+// needed, since Config isn't fully populated until Init has run.
+var (
+	createQueue     *createGate
+	createQueueOnce sync.Once
+)
+
+func createSlotGate() *createGate {
+	createQueueOnce.Do(func() {
+		createQueue = newCreateGate(Config.MaxConcurrentCreates)
+	})
+	return createQueue
+}
+
+// acquireCreateSlot waits for a batch-priority slot per Config.MaxConcurrentCreates, logging
+// the caller's queue position if it had to wait, and returns a release func to call once the
+// create/power-on task has been submitted.
+func acquireCreateSlot(op trace.Operation) func() {
+	position, release := createSlotGate().acquire(priorityBatch)
+	if position > 0 {
+		op.Infof("create queued behind %d other task(s), waiting for a slot", position)
+	}
+
+	return release
+}
+
+// acquireInteractiveSlot waits for an interactive-priority slot per Config.MaxConcurrentCreates,
+// jumping ahead of any queued batch (create) waiters, and returns a release func to call once
+// the task has been submitted.
+func acquireInteractiveSlot(op trace.Operation) func() {
+	position, release := createSlotGate().acquire(priorityInteractive)
+	if position > 0 {
+		op.Infof("task queued behind %d other interactive task(s), waiting for a slot", position)
+	}
+
+	return release
+}
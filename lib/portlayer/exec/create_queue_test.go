@@ -0,0 +1,126 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGateUnboundedDoesNotBlock(t *testing.T) {
+	g := newCreateGate(0)
+
+	position, release := g.acquire(priorityBatch)
+	assert.Equal(t, 0, position)
+	release()
+}
+
+func TestCreateGateLimitsConcurrency(t *testing.T) {
+	g := newCreateGate(2)
+
+	_, release1 := g.acquire(priorityBatch)
+	_, release2 := g.acquire(priorityBatch)
+
+	acquired := make(chan struct{})
+	go func() {
+		_, release3 := g.acquire(priorityBatch)
+		close(acquired)
+		release3()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire() returned before a slot was released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release1()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("acquire() did not unblock after a slot was released")
+	}
+
+	release2()
+}
+
+func TestCreateGateReportsQueuePosition(t *testing.T) {
+	g := newCreateGate(1)
+
+	_, release1 := g.acquire(priorityBatch)
+
+	var wg sync.WaitGroup
+	positions := make([]int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			position, release := g.acquire(priorityBatch)
+			positions[i] = position
+			release()
+		}(i)
+	}
+
+	// give the waiters time to queue up behind the held slot
+	time.Sleep(50 * time.Millisecond)
+	release1()
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{1, 2}, positions)
+}
+
+func TestCreateGatePrefersInteractiveOverQueuedBatch(t *testing.T) {
+	g := newCreateGate(1)
+
+	// hold the only slot so batch and interactive waiters both have to queue
+	_, release1 := g.acquire(priorityBatch)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, release := g.acquire(priorityBatch)
+		mu.Lock()
+		order = append(order, "batch")
+		mu.Unlock()
+		release()
+	}()
+
+	// give the batch waiter time to queue up first
+	time.Sleep(50 * time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, release := g.acquire(priorityInteractive)
+		mu.Lock()
+		order = append(order, "interactive")
+		mu.Unlock()
+		release()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	release1()
+	wg.Wait()
+
+	assert.Equal(t, []string{"interactive", "batch"}, order)
+}
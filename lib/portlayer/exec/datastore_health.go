@@ -0,0 +1,86 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/datastore"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// DatastoreUnhealthyError reports that an image datastore failed the pre-create health
+// check - either vSphere reports it inaccessible, or it has less free space than
+// Config.MinDatastoreFreeSpaceMB allows.
+type DatastoreUnhealthyError struct {
+	Datastore string
+	Reason    string
+}
+
+func (e DatastoreUnhealthyError) Error() string {
+	return fmt.Sprintf("datastore %s is not usable for container create: %s", e.Datastore, e.Reason)
+}
+
+// checkImageStoreHealth verifies that Config.ImageStores are accessible and, if
+// Config.MinDatastoreFreeSpaceMB is set, have at least that much free space. It's called
+// before CreateVM so a storage problem fails the create immediately with a
+// DatastoreUnhealthyError instead of timing out inside the CreateVM task.
+func checkImageStoreHealth(op trace.Operation, sess *session.Session) error {
+	for i := range Config.ImageStores {
+		u := Config.ImageStores[i]
+
+		helper, err := datastore.NewHelperFromURL(op, sess, &u)
+		if err != nil {
+			return DatastoreUnhealthyError{Datastore: u.String(), Reason: err.Error()}
+		}
+
+		summary, err := helper.Summary(op)
+		if err != nil {
+			return DatastoreUnhealthyError{Datastore: u.String(), Reason: fmt.Sprintf("unable to retrieve datastore summary: %s", err)}
+		}
+
+		if err := evaluateDatastoreHealth(u.String(), summary, Config.MinDatastoreFreeSpaceMB); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evaluateDatastoreHealth is the pure decision logic behind checkImageStoreHealth, split out so
+// it can be unit tested against fabricated summaries instead of a live vCenter connection.
+// minFreeMB <= 0 disables the free space check.
+func evaluateDatastoreHealth(dsName string, summary *types.DatastoreSummary, minFreeMB int64) error {
+	if !summary.Accessible {
+		return DatastoreUnhealthyError{Datastore: dsName, Reason: "datastore is reported inaccessible"}
+	}
+
+	if minFreeMB <= 0 {
+		return nil
+	}
+
+	freeMB := summary.FreeSpace / 1024 / 1024
+	if freeMB < minFreeMB {
+		return DatastoreUnhealthyError{
+			Datastore: dsName,
+			Reason:    fmt.Sprintf("only %d MB free, below the configured %d MB reserve", freeMB, minFreeMB),
+		}
+	}
+
+	return nil
+}
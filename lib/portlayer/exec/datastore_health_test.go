@@ -0,0 +1,53 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestEvaluateDatastoreHealthInaccessible(t *testing.T) {
+	summary := &types.DatastoreSummary{Accessible: false, FreeSpace: 1 << 40}
+
+	err := evaluateDatastoreHealth("ds1", summary, 0)
+	if _, ok := err.(DatastoreUnhealthyError); !ok {
+		t.Fatalf("evaluateDatastoreHealth() => %T, want DatastoreUnhealthyError", err)
+	}
+}
+
+func TestEvaluateDatastoreHealthBelowReserve(t *testing.T) {
+	summary := &types.DatastoreSummary{Accessible: true, FreeSpace: 100 * 1024 * 1024} // 100MB
+
+	err := evaluateDatastoreHealth("ds1", summary, 500)
+	if _, ok := err.(DatastoreUnhealthyError); !ok {
+		t.Fatalf("evaluateDatastoreHealth() => %T, want DatastoreUnhealthyError", err)
+	}
+}
+
+func TestEvaluateDatastoreHealthOK(t *testing.T) {
+	summary := &types.DatastoreSummary{Accessible: true, FreeSpace: 10 * 1024 * 1024 * 1024} // 10GB
+
+	assert.NoError(t, evaluateDatastoreHealth("ds1", summary, 500))
+}
+
+func TestEvaluateDatastoreHealthReserveDisabled(t *testing.T) {
+	summary := &types.DatastoreSummary{Accessible: true, FreeSpace: 0}
+
+	assert.NoError(t, evaluateDatastoreHealth("ds1", summary, 0))
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// deadlineReapInterval is how often the background reaper scans for containers whose
+// ExecConfig.Deadline has passed - CI sandboxes and batch jobs with a TTL shouldn't have
+// to wait longer than this past their deadline to be stopped.
+const deadlineReapInterval = 30 * time.Second
+
+// deadlineCommitWaitTime bounds how long a single container's stop (and, if requested,
+// removal) is allowed to wait for its backing VM operation.
+var deadlineCommitWaitTime = int32(60)
+
+// StartDeadlineReaper runs reapDeadlines on deadlineReapInterval until ctx is done.
+func StartDeadlineReaper(ctx context.Context, sess *session.Session) {
+	go func() {
+		ticker := time.NewTicker(deadlineReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reapDeadlines(ctx, sess)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// reapDeadlines stops (and, if flagged, removes) every running container whose
+// ExecConfig.Deadline has passed.
+func reapDeadlines(ctx context.Context, sess *session.Session) {
+	op := trace.NewOperation(ctx, "deadline reaper")
+
+	due := dueDeadlines(Containers.Containers(nil), time.Now().Unix())
+	for _, c := range due {
+		if err := reapOne(op, sess, c); err != nil {
+			op.Errorf("deadline reaper: %s: %s", c.ExecConfig.ID, err.Error())
+		}
+	}
+}
+
+// dueDeadlines filters to running containers with a non-zero Deadline at or before now.
+func dueDeadlines(containers []*Container, now int64) []*Container {
+	var due []*Container
+	for _, c := range containers {
+		if c.ExecConfig.Deadline == 0 || c.ExecConfig.Deadline > now {
+			continue
+		}
+
+		if c.CurrentState() != StateRunning {
+			continue
+		}
+
+		due = append(due, c)
+	}
+
+	return due
+}
+
+func reapOne(op trace.Operation, sess *session.Session, c *Container) error {
+	op.Infof("container %s reached its deadline, stopping it", c.ExecConfig.ID)
+
+	h, err := c.NewHandle(context.Background())
+	if err != nil {
+		return err
+	}
+
+	h.SetTargetState(StateStopped)
+	if err := h.Commit(op, sess, &deadlineCommitWaitTime); err != nil {
+		return err
+	}
+
+	if !c.ExecConfig.RemoveOnDeadline {
+		return nil
+	}
+
+	op.Infof("container %s is flagged for removal on deadline, removing it", c.ExecConfig.ID)
+	return c.Remove(op, sess)
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func putDeadlineTestContainer(id string, deadline int64, state State) *Container {
+	c := newTestContainer(id)
+	c.ExecConfig.Deadline = deadline
+	c.state = state
+	addTestVM(c)
+	Containers.Put(c)
+	return c
+}
+
+func TestDueDeadlinesFiltersByDeadlineAndState(t *testing.T) {
+	NewContainerCache()
+	defer NewContainerCache()
+
+	const now = int64(1000)
+
+	expired := putDeadlineTestContainer("expired", now-1, StateRunning)
+	putDeadlineTestContainer("no-deadline", 0, StateRunning)
+	putDeadlineTestContainer("not-yet-due", now+60, StateRunning)
+	putDeadlineTestContainer("already-stopped", now-1, StateStopped)
+
+	due := dueDeadlines(Containers.Containers(nil), now)
+
+	assert.Equal(t, []*Container{expired}, due)
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+	"github.com/vmware/vic/pkg/vsphere/vm"
+)
+
+// ContainerStorageUsage returns the vSphere-committed disk usage, in bytes, of
+// every cached container with a backing VM, keyed by container ID. It's a
+// single batched property collector round trip rather than one per container,
+// so it stays cheap enough to call from a "docker system df" style aggregate
+// endpoint. Containers with no VM cached yet (still being created) are omitted
+// rather than reported as zero.
+func ContainerStorageUsage(op trace.Operation, sess *session.Session) (map[string]int64, error) {
+	containers := Containers.Containers(nil)
+
+	refs := make([]types.ManagedObjectReference, 0, len(containers))
+	idsByRef := make(map[types.ManagedObjectReference]string, len(containers))
+	for _, c := range containers {
+		if c.vm == nil {
+			continue
+		}
+
+		ref := c.vm.Reference()
+		refs = append(refs, ref)
+		idsByRef[ref] = c.ExecConfig.ID
+	}
+
+	usage := make(map[string]int64, len(refs))
+	if len(refs) == 0 {
+		return usage, nil
+	}
+
+	mos, err := vm.Attributes(op, sess, refs, "summary.storage")
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve container disk usage: %s", err)
+	}
+
+	for _, m := range mos {
+		id, ok := idsByRef[m.Self]
+		if !ok || m.Summary.Storage == nil {
+			continue
+		}
+		usage[id] = m.Summary.Storage.Committed
+	}
+
+	return usage, nil
+}
@@ -27,15 +27,26 @@ import (
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
 
+	"github.com/vmware/vic/lib/portlayer/admission"
 	"github.com/vmware/vic/lib/portlayer/event"
 	"github.com/vmware/vic/lib/portlayer/event/collector/vsphere"
 	"github.com/vmware/vic/lib/portlayer/event/events"
+	"github.com/vmware/vic/lib/portlayer/event/webhook"
+	"github.com/vmware/vic/lib/portlayer/journal"
+	"github.com/vmware/vic/lib/portlayer/store"
+	"github.com/vmware/vic/pkg/kvstore"
 	"github.com/vmware/vic/pkg/trace"
 	"github.com/vmware/vic/pkg/vsphere/compute"
 	"github.com/vmware/vic/pkg/vsphere/extraconfig"
 	"github.com/vmware/vic/pkg/vsphere/session"
 )
 
+// Journal records the intent behind an in-flight container create or remove, so a portlayer
+// restart after a crash mid-operation has more to recover from than a plain vSphere inventory
+// diff. Exported on the package for the same reason as Containers - callers throughout this
+// package journal their own multi-step mutations directly.
+var Journal *journal.Journal
+
 var (
 	initializer struct {
 		err  error
@@ -65,6 +76,11 @@ func Init(ctx context.Context, sess *session.Session, source extraconfig.DataSou
 		extraconfig.Decode(source, &Config)
 
 		log.Debugf("Decoded VCH config for execution: %#v", Config)
+
+		if Config.GuestInfoPrefix != "" {
+			log.Infof("Namespacing containerVM extraConfig under guestinfo prefix %q", Config.GuestInfoPrefix)
+			extraconfig.SetPrefix(Config.GuestInfoPrefix)
+		}
 		ccount := len(Config.ComputeResources)
 		if ccount != 1 {
 			err = fmt.Errorf("expected singular compute resource element, found %d", ccount)
@@ -123,9 +139,35 @@ func Init(ctx context.Context, sess *session.Session, source extraconfig.DataSou
 		// instantiate the container cache now
 		NewContainerCache()
 
+		// journal container create/remove intent to the datastore so a crash mid-operation
+		// can be reconciled deterministically on the next start, rather than only ever
+		// surfacing as a bare orphaned VM
+		var kv kvstore.KeyValueStore
+		kv, err = store.NewDatastoreKeyValue(ctx, sess, "exec.journal")
+		if err != nil {
+			err = fmt.Errorf("could not initialize container operation journal: %s", err)
+			log.Error(err)
+			return
+		}
+		Journal = journal.NewJournal(kv)
+
 		// create the event manager &  register the existing collector
 		Config.EventManager = event.NewEventManager(ec)
 
+		// wire up the outbound webhook sink, if the VCH was configured with one
+		webhook.Register(Config.EventManager, Config.Container.WebhookURL, Config.Container.WebhookSecret)
+
+		// wire up the container create admission hook, if the VCH was configured with one
+		if Config.Container.AdmissionHookURL != "" {
+			admission.Register(admission.NewHTTPHook(Config.Container.AdmissionHookURL))
+		}
+
+		// enforce the VCH-wide hard cap on container count, if configured, ahead of
+		// the admission hook and independent of any per-project quota
+		admission.SetMaxContainers(Config.Container.ContainerCount, func() int {
+			return len(Containers.Containers(nil))
+		})
+
 		// subscribe the exec layer to the event stream for Vm events
 		vmSub := Config.EventManager.Subscribe(events.NewEventType(vsphere.VMEvent{}).Topic(), "exec", func(e events.Event) {
 			if c := Containers.Container(e.Reference()); c != nil {
@@ -155,6 +197,29 @@ func Init(ctx context.Context, sess *session.Session, source extraconfig.DataSou
 			return
 		}
 
+		// periodically re-adopt container VMs that show up in vSphere inventory but are
+		// missing from the cache, e.g. after a portlayer crash mid-create
+		startOrphanReconciler(ctx, sess)
+
+		// periodically stop (and optionally remove) containers whose create-time TTL has
+		// elapsed
+		StartDeadlineReaper(ctx, sess)
+
+		// periodically sample running containers' memory usage and raise an event for any
+		// that have been sustained above the watermark long enough to risk an in-guest OOM
+		startWatermarkMonitor(ctx, sess)
+
+		// periodically purge trashed containers (see Container.Trash) whose retention
+		// window has expired
+		StartTrashReaper(ctx, sess)
+
+		// power on any container flagged for autostart, now that the cache reflects
+		// the actual state of the VCH's containers
+		startOp := trace.NewOperation(ctx, "AutoStart")
+		if err := AutoStart(startOp, sess); err != nil {
+			log.Errorf("Error encountered during container autostart: %s", err)
+		}
+
 		if Config.UseVMGroup {
 			vmGroupChan := make(chan chan error, batchingLimit)
 			Config.addToVMGroup = func(op trace.Operation) error {
@@ -191,6 +256,14 @@ func Finalize(ctx context.Context) error {
 	return nil
 }
 
+// PublishContainerEvent publishes a ContainerEvent to the vic event stream on behalf of
+// callers outside this package - the network and apiservers/portlayer packages, notably,
+// which report the network and validation stages of a container create that happen before
+// or between the exec-package steps that publish their own stage events directly.
+func PublishContainerEvent(op trace.Operation, id string, created time.Time, eventType string) {
+	publishContainerEvent(op, id, created, eventType)
+}
+
 // publishContainerEvent will publish a ContainerEvent to the vic event stream
 func publishContainerEvent(op trace.Operation, id string, created time.Time, eventType string) {
 	if Config.EventManager == nil || eventType == "" {
@@ -0,0 +1,104 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// ExportOVF exports a stopped container VM's disks and metadata as an OVF package
+// (descriptor + disks) into destDir, which must already exist. It returns the path
+// to the OVF descriptor on success.
+//
+// This does not produce a single-file OVA - callers that want one can tar up destDir
+// themselves, mirroring govc's export.ovf/export.ova split.
+func (c *Container) ExportOVF(op trace.Operation, destDir string) (string, error) {
+	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.vm == nil {
+		return "", NotFoundError{}
+	}
+
+	// exporting a running container would yield disks that are still being written to -
+	// require the same "not running" precondition Remove uses.
+	if c.state == StateRunning {
+		return "", RemovePowerError{fmt.Errorf("Container %s is powered on", c)}
+	}
+
+	lease, err := c.vm.Export(op)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := lease.Wait(op, nil)
+	if err != nil {
+		return "", err
+	}
+	defer lease.Complete(op)
+
+	updater := lease.StartUpdater(op, info)
+	defer updater.Done()
+
+	cdp := types.OvfCreateDescriptorParams{
+		Name: c.ExecConfig.Name,
+	}
+
+	for _, item := range info.Items {
+		if filepath.Ext(item.Path) != ".vmdk" {
+			continue
+		}
+
+		if !strings.HasPrefix(item.Path, c.ExecConfig.Name) {
+			item.Path = c.ExecConfig.Name + "-" + item.Path
+		}
+
+		if err := lease.DownloadFile(op, filepath.Join(destDir, item.Path), item, soap.Download{}); err != nil {
+			return "", err
+		}
+
+		cdp.OvfFiles = append(cdp.OvfFiles, item.File())
+	}
+
+	m := ovf.NewManager(c.vm.Client())
+	desc, err := m.CreateDescriptor(op, c.vm, cdp)
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(destDir, c.ExecConfig.Name+".ovf")
+	f, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(desc.OvfDescriptor); err != nil {
+		return "", err
+	}
+
+	return target, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -45,6 +46,18 @@ import (
 type Resources struct {
 	NumCPUs  int64
 	MemoryMB int64
+
+	// CPUSet is a docker cpuset-cpus style request, e.g. "0-2,4", mapped onto the
+	// containerVM's CPU scheduling affinity (see ParseCPUSet and spec.CPUAffinity).
+	// Requires a single-host VCH - see Create's use of requireSingleHostForAffinity.
+	CPUSet string
+
+	// ScratchDiskMB, if non-zero, provisions an additional disk on host-local storage
+	// (rather than the VCH's shared image/volume datastore) for temp-heavy workloads.
+	// Container removal does not delete the disk file when it sits on a different datastore
+	// from the container VM - see addHostLocalScratchDisk.
+	// Requires a single-host VCH - see Create's use of requireSingleHost.
+	ScratchDiskMB int64
 }
 
 // ContainerCreateConfig defines the parameters for Create call
@@ -52,6 +65,16 @@ type ContainerCreateConfig struct {
 	Metadata *executor.ExecutorConfig
 
 	Resources Resources
+
+	// DryRun requests that Create perform validation, placement and spec composition as usual,
+	// but skip registering the resulting Handle - the caller gets the composed Handle.Spec back
+	// to inspect, but has no way to Commit it afterwards.
+	DryRun bool
+
+	// NoConsole requests that the container be provisioned without a virtual video card,
+	// leaving it with serial-only console access to reduce per-VM overhead at high density.
+	// SetConsoleEnabled can temporarily restore one later for interactive debugging.
+	NoConsole bool
 }
 
 var handles *lru.Cache
@@ -84,6 +107,10 @@ type Handle struct {
 	// should this change trigger a reload in the target container
 	reload bool
 
+	// NoConsole carries a create-time request to strip the virtual video card vSphere adds to
+	// a freshly created VM, once creation completes - see ContainerCreateConfig.NoConsole.
+	NoConsole bool
+
 	// allow for passing outside of the process
 	key string
 }
@@ -156,6 +183,44 @@ func (h *Handle) Rename(op trace.Operation, newName string) *Handle {
 	return h
 }
 
+// SetDiskIOLimit updates the aggregate read+write IOPS limit on the container's rw layer disk,
+// so storage QoS can be adjusted for a container that has already been created, not just at
+// ImageJoin time. A limit of 0 or less clears any existing cap.
+//
+// This only reconfigures the rw layer disk added by storage/image/vsphere.Join - it has no
+// effect on any volumes attached to the container, which have no equivalent throttling yet.
+func (h *Handle) SetDiskIOLimit(op trace.Operation, limit int64) error {
+	defer trace.End(trace.Begin(h.ExecConfig.ID, op))
+
+	if h.Config == nil {
+		return fmt.Errorf("container %s has no committed configuration to reconfigure", h.ExecConfig.ID)
+	}
+
+	rwlayer := fmt.Sprintf("%s/%s.vmdk", h.ExecConfig.ID, h.ExecConfig.ID)
+
+	for _, d := range h.Config.Hardware.Device {
+		disk, ok := d.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+
+		backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		if !ok || !strings.HasSuffix(backing.FileName, rwlayer) {
+			continue
+		}
+
+		spec.SetIOPSLimit(disk, limit)
+		h.Spec.DeviceChange = append(h.Spec.DeviceChange, &types.VirtualDeviceConfigSpec{
+			Device:    disk,
+			Operation: types.VirtualDeviceConfigSpecOperationEdit,
+		})
+
+		return nil
+	}
+
+	return fmt.Errorf("unable to find rw layer disk for container %s", h.ExecConfig.ID)
+}
+
 // GetHandle finds and returns the handle that is referred by key
 func GetHandle(key string) *Handle {
 	handlesLock.Lock()
@@ -236,6 +301,15 @@ func (h *Handle) Commit(op trace.Operation, sess *session.Session, waitTime *int
 		h.changes = append(s.ExtraConfig, vmomi.OptionValueUpdatesFromMap(h.Config.ExtraConfig, cfg)...)
 	} else {
 		h.changes = append(s.ExtraConfig, vmomi.OptionValueFromMap(cfg, true)...)
+
+		// stamp the namespace override at a fixed, unprefixed key so the tether can discover it
+		// before it knows which namespace the rest of its configuration lives under
+		if Config.GuestInfoPrefix != "" {
+			h.changes = append(h.changes, &types.OptionValue{
+				Key:   extraconfig.PrefixOverrideKey,
+				Value: Config.GuestInfoPrefix,
+			})
+		}
 	}
 	s.ExtraConfig = h.changes
 
@@ -312,15 +386,38 @@ func Create(ctx context.Context, vmomiSession *session.Session, config *Containe
 		return nil, errors.New(detail)
 	}
 
+	cpuAffinity, err := ParseCPUSet(config.Resources.CPUSet)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cpuAffinity) > 0 {
+		if err := requireSingleHostForAffinity(op); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Resources.ScratchDiskMB > 0 {
+		if err := requireSingleHost(op, "a host-local scratch disk"); err != nil {
+			return nil, err
+		}
+	}
+
+	bootMediaPath, err := Config.BootstrapImage(config.Metadata.BootstrapVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	specconfig := &spec.VirtualMachineConfigSpecConfig{
-		NumCPUs:  int32(config.Resources.NumCPUs),
-		MemoryMB: config.Resources.MemoryMB,
+		NumCPUs:     int32(config.Resources.NumCPUs),
+		MemoryMB:    config.Resources.MemoryMB,
+		CPUAffinity: cpuAffinity,
 
 		ID:       config.Metadata.ID,
 		Name:     config.Metadata.Name,
 		BiosUUID: uuid,
 
-		BootMediaPath: Config.BootstrapImagePath,
+		BootMediaPath: bootMediaPath,
 		VMPathName:    fmt.Sprintf("[%s]", vmomiSession.Datastore.Name()),
 
 		Metadata: config.Metadata,
@@ -363,6 +460,27 @@ func Create(ctx context.Context, vmomiSession *session.Session, config *Containe
 
 	h.Guest = linux
 	h.Spec = linux.Spec()
+	h.NoConsole = config.NoConsole
+
+	if config.Resources.ScratchDiskMB > 0 {
+		if err := addHostLocalScratchDisk(op, vmomiSession, h, config.Resources.ScratchDiskMB); err != nil {
+			log.Errorf("Failed to add host-local scratch disk during create of %s: %s", config.Metadata.ID, err)
+			return nil, err
+		}
+	}
+
+	// Always capture serial output to a datastore file from power-on, independent of
+	// whether the creating client remembers to make a separate logging.Join call - a
+	// container that panics before the tether ever connects still needs a diagnosable
+	// output.log. addBootLogSerialPorts is idempotent, so a client's own logging.Join
+	// call later in the same handle's lifecycle is a harmless no-op.
+	addBootLogSerialPorts(h)
+
+	if config.DryRun {
+		// the caller only wants the composed spec - don't register the handle since it
+		// can never legitimately be looked up or committed
+		return h, nil
+	}
 
 	handlesLock.Lock()
 	defer handlesLock.Unlock()
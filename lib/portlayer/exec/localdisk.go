@@ -0,0 +1,97 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// scratchDiskSuffix names the file backing a container's host-local scratch disk.
+const scratchDiskSuffix = "-scratch.vmdk"
+
+// addHostLocalScratchDisk adds a fresh, empty disk of sizeMB to h's still-uncommitted
+// create spec, backed by a datastore local to the host the container will land on
+// rather than the VCH's shared image/volume datastore. Container removal does not
+// delete it: Remove destroys the VM with DeleteExceptDisks and then deletes only the
+// VM's folder on the container's own datastore, so a scratch disk file on a different,
+// host-local datastore is left in place.
+func addHostLocalScratchDisk(op trace.Operation, sess *session.Session, h *Handle, sizeMB int64) error {
+	ds, err := selectHostLocalDatastore(op, sess)
+	if err != nil {
+		return err
+	}
+
+	disk := h.Guest.NewDisk()
+	moref := ds.Reference()
+
+	disk.CapacityInKB = sizeMB * 1024
+	disk.GetVirtualDevice().Backing = &types.VirtualDiskFlatVer2BackingInfo{
+		DiskMode:        string(types.VirtualDiskModePersistent),
+		ThinProvisioned: types.NewBool(true),
+
+		VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+			FileName:  ds.Path(fmt.Sprintf("%s/%s%s", h.ExecConfig.ID, h.ExecConfig.ID, scratchDiskSuffix)),
+			Datastore: &moref,
+		},
+	}
+
+	h.Spec.AddVirtualDisk(disk)
+
+	return nil
+}
+
+// selectHostLocalDatastore finds a datastore attached to the VCH's host that isn't
+// shared with any other host - i.e. local SSD/NVMe rather than the shared image/volume
+// datastore. Callers must have already established that the VCH's compute resource has
+// exactly one host (see requireSingleHost): on a cluster, DRS could vMotion the
+// container away from the host holding this disk at any time, silently stranding it.
+func selectHostLocalDatastore(op trace.Operation, sess *session.Session) (*object.Datastore, error) {
+	hosts, err := Config.Cluster.Hosts(op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate hosts for host-local scratch disk: %s", err)
+	}
+
+	if len(hosts) != 1 {
+		return nil, fmt.Errorf("host-local scratch disk requires a single-host VCH; this VCH's compute resource has %d hosts", len(hosts))
+	}
+
+	var mh mo.HostSystem
+	if err := hosts[0].Properties(op, hosts[0].Reference(), []string{"datastore"}, &mh); err != nil {
+		return nil, fmt.Errorf("unable to enumerate datastores for host-local scratch disk: %s", err)
+	}
+
+	for _, ref := range mh.Datastore {
+		ds := object.NewDatastore(sess.Vim25(), ref)
+
+		var mds mo.Datastore
+		if err := ds.Properties(op, ref, []string{"summary"}, &mds); err != nil {
+			op.Warnf("Unable to retrieve datastore info for %s, excluding it from consideration for a host-local scratch disk: %s", ref, err)
+			continue
+		}
+
+		if mds.Summary.MultipleHostAccess == nil || !*mds.Summary.MultipleHostAccess {
+			return ds, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no host-local datastore found on this VCH's host")
+}
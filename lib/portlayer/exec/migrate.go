@@ -0,0 +1,74 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+)
+
+// MigrateVM relocates a container VM into pool (and optionally onto host), the resource pool
+// and folder of the VCH taking ownership of it, without powering the VM off. It relies on the
+// same vMotion machinery govc uses for object.VirtualMachine.Migrate - the container's disks and
+// network state move with it, so no attach/network re-homing is required on our side: the portlayer
+// on the far end simply needs to see the VM appear in its resource pool.
+//
+// Once the relocate task completes, the OwnerVCH custom field is restamped to newOwnerVCH and the
+// container is dropped from this portlayer's cache - the destination portlayer is expected to pick
+// it up via its own resource pool inventory scan (see infraContainers) and AdoptVM it into its cache.
+func MigrateVM(ctx context.Context, sess *session.Session, id string, pool *object.ResourcePool, host *object.HostSystem, newOwnerVCH string) error {
+	op := trace.FromContext(ctx, "MigrateVM")
+	defer trace.End(trace.Begin(id, op))
+
+	c := Containers.Container(id)
+	if c == nil {
+		return NotFoundError{}
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.vm == nil {
+		return NotFoundError{}
+	}
+
+	_, err := c.vm.WaitForResult(op, func(op context.Context) (tasks.Task, error) {
+		return c.vm.Migrate(op, pool, host, types.VirtualMachineMovePriorityDefaultPriority, "")
+	})
+	if err != nil {
+		return fmt.Errorf("unable to migrate container %s: %s", id, err)
+	}
+
+	cfm := object.NewCustomFieldsManager(sess.Vim25())
+	key, err := customFieldKey(op, cfm, "OwnerVCH")
+	if err != nil {
+		op.Warnf("Unable to resolve OwnerVCH custom field while migrating %s: %s", id, err)
+	} else if err := cfm.Set(op, c.vm.Reference(), key, newOwnerVCH); err != nil {
+		op.Warnf("Unable to restamp OwnerVCH on %s after migration: %s", id, err)
+	}
+
+	Containers.Remove(id)
+
+	op.Infof("Migrated container %s to new owner %s", id, newOwnerVCH)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// DVSPort is a container's vNIC port together with the distributed virtual switch and
+// portgroup it belongs to, resolved by asking vSphere directly rather than inferring it
+// from another object's moref.
+type DVSPort struct {
+	Card *types.VirtualEthernetCardDistributedVirtualPortBackingInfo
+	DVS  *object.DistributedVirtualSwitch
+
+	// PortgroupName and PortgroupMoref identify the portgroup owning Card.Port, for
+	// callers that need to report the backing rather than just act on it.
+	PortgroupName  string
+	PortgroupMoref types.ManagedObjectReference
+}
+
+// resolveContainerDVSPort finds the vNIC a container has on networkName and resolves the
+// distributed virtual switch and portgroup backing it. Both ApplyShaping and
+// StartPacketCapture need this same resolution, so it lives here rather than being
+// duplicated between them.
+func resolveContainerDVSPort(op trace.Operation, id, networkName string) (*DVSPort, error) {
+	c := Containers.Container(id)
+	if c == nil {
+		return nil, NotFoundError{}
+	}
+
+	c.m.Lock()
+	v := c.vm
+	c.m.Unlock()
+
+	if v == nil {
+		return nil, NotFoundError{}
+	}
+
+	devices, err := v.Device(op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate devices for container %s: %s", id, err)
+	}
+
+	var card *types.VirtualEthernetCardDistributedVirtualPortBackingInfo
+	for _, d := range devices {
+		nic, ok := d.(types.BaseVirtualEthernetCard)
+		if !ok {
+			continue
+		}
+
+		eth := nic.GetVirtualEthernetCard()
+		if eth.DeviceInfo == nil || eth.DeviceInfo.GetDescription().Label != networkName {
+			continue
+		}
+
+		card, ok = eth.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+		if !ok {
+			return nil, fmt.Errorf("container %s's %s network is not backed by a distributed virtual switch", id, networkName)
+		}
+		break
+	}
+
+	if card == nil {
+		return nil, fmt.Errorf("container %s has no vNIC on network %s", id, networkName)
+	}
+
+	// the port's backing only carries the portgroup key and the DVS's uuid, not the DVS's
+	// moref, so it has to be resolved via the portgroup's own properties rather than
+	// parsed out of an identifier that was never guaranteed to encode it.
+	var pgo mo.DistributedVirtualPortgroup
+	pgref := object.NewDistributedVirtualPortgroup(v.Session.Vim25(), types.ManagedObjectReference{
+		Type:  "DistributedVirtualPortgroup",
+		Value: card.Port.PortgroupKey,
+	})
+	if err := pgref.Properties(op, pgref.Reference(), []string{"name", "config"}, &pgo); err != nil {
+		return nil, fmt.Errorf("unable to resolve distributed virtual switch for container %s: %s", id, err)
+	}
+	if pgo.Config.DistributedVirtualSwitch == nil {
+		return nil, fmt.Errorf("portgroup %s has no owning distributed virtual switch", card.Port.PortgroupKey)
+	}
+
+	return &DVSPort{
+		Card:           card,
+		DVS:            object.NewDistributedVirtualSwitch(v.Session.Vim25(), *pgo.Config.DistributedVirtualSwitch),
+		PortgroupName:  pgo.Name,
+		PortgroupMoref: pgref.Reference(),
+	}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// candidateHost is the subset of a host's state that placement ranks on. It's
+// separated from *object.HostSystem so ranking can be unit tested against
+// fabricated data instead of a live vCenter connection.
+type candidateHost struct {
+	host              *object.HostSystem
+	inMaintenanceMode bool
+	connected         bool
+	freeMemoryMB      int64
+	freeCPUMhz        int64
+}
+
+// SelectPlacementHost picks a host to pass to CreateVM/CreateChildVM for a new
+// container VM. It only makes an explicit choice when the compute resource backing
+// the VCH is a DRS-disabled cluster - in every other case (DRS enabled, or a
+// standalone-host compute resource) it returns a nil host so vSphere's own
+// placement is used, exactly as before this existed.
+//
+// Hosts in maintenance mode or not connected are never eligible. Among the rest,
+// the configured Placer chooses which to use - CapacityPlacer, preferring the host
+// with the most free memory, unless a WithPlacer option says otherwise. An error is
+// returned instead of picking arbitrarily when no eligible host exists.
+func SelectPlacementHost(op trace.Operation, sess *session.Session, opts ...PlacementOption) (*object.HostSystem, error) {
+	options := placementOptions{placer: CapacityPlacer{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if sess.DRSEnabled != nil && *sess.DRSEnabled {
+		return nil, nil
+	}
+
+	if Config.Cluster == nil {
+		return nil, nil
+	}
+
+	hosts, err := Config.Cluster.Hosts(op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate hosts for placement: %s", err)
+	}
+
+	if len(hosts) <= 1 {
+		// nothing to choose between - let CreateVM use the implicit single host
+		return nil, nil
+	}
+
+	candidates := make([]candidateHost, 0, len(hosts))
+	for _, h := range hosts {
+		var mh mo.HostSystem
+		if err := h.Properties(op, h.Reference(), []string{"runtime", "summary"}, &mh); err != nil {
+			op.Warnf("Unable to retrieve placement info for host %s, excluding it from consideration: %s", h.Name(), err)
+			continue
+		}
+
+		hw := mh.Summary.Hardware
+		var freeMemoryMB, freeCPUMhz int64
+		if hw != nil {
+			freeMemoryMB = hw.MemorySize/1024/1024 - int64(mh.Summary.QuickStats.OverallMemoryUsage)
+			freeCPUMhz = int64(hw.CpuMhz)*int64(hw.NumCpuCores) - int64(mh.Summary.QuickStats.OverallCpuUsage)
+		}
+
+		candidates = append(candidates, candidateHost{
+			host:              h,
+			inMaintenanceMode: mh.Runtime.InMaintenanceMode,
+			connected:         mh.Runtime.ConnectionState == types.HostSystemConnectionStateConnected,
+			freeMemoryMB:      freeMemoryMB,
+			freeCPUMhz:        freeCPUMhz,
+		})
+	}
+
+	eligible := make([]candidateHost, 0, len(candidates))
+	for _, c := range candidates {
+		if !c.inMaintenanceMode && c.connected {
+			eligible = append(eligible, c)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, fmt.Errorf("no eligible host found for container placement: all hosts are in maintenance mode, disconnected, or unreachable")
+	}
+
+	best := options.placer.Place(op, eligible)
+	if best == nil {
+		// the placer deliberately declined to choose (e.g. DRSPlacer) - let vSphere place it
+		return nil, nil
+	}
+
+	return best.host, nil
+}
+
+// rankHosts returns the most eligible candidate, or nil if none are eligible.
+func rankHosts(candidates []candidateHost) *candidateHost {
+	var best *candidateHost
+	for i := range candidates {
+		c := &candidates[i]
+		if c.inMaintenanceMode || !c.connected {
+			continue
+		}
+
+		if best == nil || c.freeMemoryMB > best.freeMemoryMB ||
+			(c.freeMemoryMB == best.freeMemoryMB && c.freeCPUMhz > best.freeCPUMhz) {
+			best = c
+		}
+	}
+
+	return best
+}
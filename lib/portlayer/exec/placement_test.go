@@ -0,0 +1,62 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankHostsExcludesMaintenanceAndDisconnected(t *testing.T) {
+	candidates := []candidateHost{
+		{inMaintenanceMode: true, connected: true, freeMemoryMB: 100000},
+		{inMaintenanceMode: false, connected: false, freeMemoryMB: 90000},
+		{inMaintenanceMode: false, connected: true, freeMemoryMB: 1000},
+	}
+
+	best := rankHosts(candidates)
+	assert.NotNil(t, best)
+	assert.Equal(t, int64(1000), best.freeMemoryMB)
+}
+
+func TestRankHostsPrefersMoreFreeMemory(t *testing.T) {
+	candidates := []candidateHost{
+		{connected: true, freeMemoryMB: 2000, freeCPUMhz: 5000},
+		{connected: true, freeMemoryMB: 8000, freeCPUMhz: 1000},
+	}
+
+	best := rankHosts(candidates)
+	assert.Equal(t, int64(8000), best.freeMemoryMB)
+}
+
+func TestRankHostsBreaksTiesOnFreeCPU(t *testing.T) {
+	candidates := []candidateHost{
+		{connected: true, freeMemoryMB: 4000, freeCPUMhz: 500},
+		{connected: true, freeMemoryMB: 4000, freeCPUMhz: 1500},
+	}
+
+	best := rankHosts(candidates)
+	assert.Equal(t, int64(1500), best.freeCPUMhz)
+}
+
+func TestRankHostsNoneEligible(t *testing.T) {
+	candidates := []candidateHost{
+		{inMaintenanceMode: true, connected: true},
+		{inMaintenanceMode: false, connected: false},
+	}
+
+	assert.Nil(t, rankHosts(candidates))
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"math/rand"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// Placer ranks a set of eligible placement candidates and picks one, or returns
+// nil to defer to vSphere's own placement. SelectPlacementHost has already
+// filtered out hosts in maintenance mode or disconnected by the time a Placer
+// sees the candidate list.
+type Placer interface {
+	Place(op trace.Operation, candidates []candidateHost) *candidateHost
+}
+
+// CapacityPlacer picks the eligible host with the most free memory, breaking ties
+// on free CPU. It's the default SelectPlacementHost has always used.
+type CapacityPlacer struct{}
+
+// Place implements the Placer interface.
+func (CapacityPlacer) Place(op trace.Operation, candidates []candidateHost) *candidateHost {
+	return rankHosts(candidates)
+}
+
+// RandomPlacer picks uniformly at random among eligible hosts, spreading
+// containers across a cluster without sampling host utilization.
+type RandomPlacer struct{}
+
+// Place implements the Placer interface.
+func (RandomPlacer) Place(op trace.Operation, candidates []candidateHost) *candidateHost {
+	eligible := make([]*candidateHost, 0, len(candidates))
+	for i := range candidates {
+		c := &candidates[i]
+		if c.inMaintenanceMode || !c.connected {
+			continue
+		}
+		eligible = append(eligible, c)
+	}
+
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	return eligible[rand.Intn(len(eligible))]
+}
+
+// DRSPlacer never makes an explicit choice, deferring placement entirely to
+// vSphere DRS regardless of whether the VCH's own compute resource has DRS
+// enabled.
+type DRSPlacer struct{}
+
+// Place implements the Placer interface.
+func (DRSPlacer) Place(op trace.Operation, candidates []candidateHost) *candidateHost {
+	return nil
+}
+
+// placementOptions holds the configuration a PlacementOption mutates.
+type placementOptions struct {
+	placer Placer
+}
+
+// PlacementOption customizes a SelectPlacementHost call.
+type PlacementOption func(*placementOptions)
+
+// WithPlacer overrides SelectPlacementHost's default capacity-aware ranking with a
+// custom Placer, so advanced users can plug in their own selection policy - e.g.
+// RandomPlacer, DRSPlacer, or one of their own - without forking the exec handler.
+func WithPlacer(p Placer) PlacementOption {
+	return func(o *placementOptions) {
+		o.placer = p
+	}
+}
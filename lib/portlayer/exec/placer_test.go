@@ -0,0 +1,67 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+func TestCapacityPlacerMatchesRankHosts(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	candidates := []candidateHost{
+		{connected: true, freeMemoryMB: 2000},
+		{connected: true, freeMemoryMB: 8000},
+	}
+
+	best := CapacityPlacer{}.Place(op, candidates)
+	assert.Equal(t, int64(8000), best.freeMemoryMB)
+}
+
+func TestRandomPlacerOnlyPicksEligible(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	candidates := []candidateHost{
+		{inMaintenanceMode: true, connected: true, freeMemoryMB: 1},
+		{connected: false, freeMemoryMB: 2},
+		{connected: true, freeMemoryMB: 3},
+	}
+
+	for i := 0; i < 10; i++ {
+		best := RandomPlacer{}.Place(op, candidates)
+		assert.Equal(t, int64(3), best.freeMemoryMB)
+	}
+}
+
+func TestRandomPlacerNoneEligible(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	candidates := []candidateHost{
+		{inMaintenanceMode: true, connected: true},
+	}
+
+	assert.Nil(t, RandomPlacer{}.Place(op, candidates))
+}
+
+func TestDRSPlacerAlwaysDefers(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	candidates := []candidateHost{
+		{connected: true, freeMemoryMB: 8000},
+	}
+
+	assert.Nil(t, DRSPlacer{}.Place(op, candidates))
+}
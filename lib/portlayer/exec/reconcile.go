@@ -0,0 +1,134 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"time"
+
+	"github.com/vmware/vic/lib/portlayer/journal"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// orphanReconcileInterval is how often the background reconciler re-scans the VCH's resource
+// pool/folder for container VMs that vSphere knows about but the running portlayer does not -
+// for example after the portlayer process is restarted mid-create, or crashes and loses track
+// of an in-flight operation.
+const orphanReconcileInterval = 5 * time.Minute
+
+// startOrphanReconciler runs reconcileOrphans on orphanReconcileInterval until ctx is done.
+func startOrphanReconciler(ctx context.Context, sess *session.Session) {
+	go func() {
+		ticker := time.NewTicker(orphanReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reconcileOrphans(ctx, sess)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// reconcileOrphans adopts container VMs that are present in the VCH's vSphere inventory but
+// missing from the live container cache, so operations like docker ps see them again without
+// requiring a portlayer restart.
+//
+// It deliberately never destroys anything: telling a genuinely garbage leftover (e.g. a VM stub
+// left behind by a failed create) apart from a container mid-recovery needs more context than a
+// simple inventory diff has, so destructive cleanup is left to admins and the existing container
+// removal APIs.
+func reconcileOrphans(ctx context.Context, sess *session.Session) {
+	op := trace.NewOperation(ctx, "orphan reconciliation")
+
+	cons, err := infraContainers(op, sess)
+	if err != nil {
+		op.Errorf("Orphan reconciliation failed to list infrastructure containers: %s", err)
+		return
+	}
+
+	var adopted int
+	for _, c := range cons {
+		if Containers.Container(c.ExecConfig.ID) != nil {
+			continue
+		}
+
+		op.Warnf("Adopting orphaned container VM %s found in inventory but not in the container cache", c.ExecConfig.ID)
+		Containers.Put(c)
+		adopted++
+	}
+
+	if adopted > 0 {
+		op.Infof("Orphan reconciliation adopted %d container(s)", adopted)
+	}
+
+	reconcileJournal(op, cons)
+}
+
+// reconcileJournal inspects journal entries left behind by a create or remove that never
+// reached Journal.Complete - most likely because the portlayer crashed partway through. cons
+// is the inventory snapshot reconcileOrphans just took, so telling whether the container an
+// entry refers to actually exists doesn't need a second vSphere query.
+//
+// Like reconcileOrphans, this never destroys anything: a create whose VM never made it into
+// inventory needs an admin or orchestrator decision (retry vs. give up), not an automatic
+// guess. What it does do is clear entries that resolved themselves - a create that is adopted
+// above, or a remove that finished but crashed before recording completion - so a genuinely
+// stuck operation doesn't get lost in the noise of ones that already recovered on their own.
+func reconcileJournal(op trace.Operation, cons []*Container) {
+	if Journal == nil {
+		return
+	}
+
+	entries, err := Journal.Pending(op)
+	if err != nil {
+		op.Errorf("Journal reconciliation failed to list pending entries: %s", err)
+		return
+	}
+
+	exists := make(map[string]bool, len(cons))
+	for _, c := range cons {
+		exists[c.ExecConfig.ID] = true
+	}
+
+	for _, e := range entries {
+		found := exists[e.ContainerID] || Containers.Container(e.ContainerID) != nil
+
+		switch e.Operation {
+		case journal.OpCreate:
+			if found {
+				op.Infof("Journal reconciliation: create of container %s completed despite an interrupted commit, clearing journal entry", e.ContainerID)
+				if err := Journal.Complete(op, e.ContainerID); err != nil {
+					op.Errorf("Journal reconciliation failed to clear entry for %s: %s", e.ContainerID, err)
+				}
+			} else {
+				op.Warnf("Journal reconciliation: create of container %s never produced a VM (last phase %q) - leaving journal entry for manual review", e.ContainerID, e.Phase)
+			}
+		case journal.OpRemove:
+			if !found {
+				op.Infof("Journal reconciliation: remove of container %s completed despite an interrupted commit, clearing journal entry", e.ContainerID)
+				if err := Journal.Complete(op, e.ContainerID); err != nil {
+					op.Errorf("Journal reconciliation failed to clear entry for %s: %s", e.ContainerID, err)
+				}
+			} else {
+				op.Warnf("Journal reconciliation: remove of container %s never finished (last phase %q) - leaving journal entry for manual review", e.ContainerID, e.Phase)
+			}
+		}
+	}
+}
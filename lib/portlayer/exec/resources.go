@@ -0,0 +1,60 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import "fmt"
+
+// fallbackNumCPUs and fallbackMemoryMB are used when a create doesn't specify a
+// vCPU count/memory size and the VCH has no configured default of its own.
+const (
+	fallbackNumCPUs  = 1
+	fallbackMemoryMB = 512
+)
+
+// ResolveResources fills in numCPUs/memoryMB from the VCH's configured defaults when
+// either is left at zero, and validates the result against the VCH's configured
+// per-container maximums (see Config.Container). A negative request, or one over a
+// configured maximum, is rejected rather than silently clamped, so the caller finds
+// out its request wasn't honored instead of getting a smaller container than asked for.
+func ResolveResources(numCPUs, memoryMB int64) (int64, int64, error) {
+	if numCPUs < 0 {
+		return 0, 0, fmt.Errorf("number of CPUs must not be negative, got %d", numCPUs)
+	}
+	if memoryMB < 0 {
+		return 0, 0, fmt.Errorf("memory (MB) must not be negative, got %d", memoryMB)
+	}
+
+	if numCPUs == 0 {
+		numCPUs = Config.Container.DefaultNumCPUs
+		if numCPUs == 0 {
+			numCPUs = fallbackNumCPUs
+		}
+	}
+	if memoryMB == 0 {
+		memoryMB = Config.Container.DefaultMemoryMB
+		if memoryMB == 0 {
+			memoryMB = fallbackMemoryMB
+		}
+	}
+
+	if max := Config.Container.MaxContainerNumCPUs; max > 0 && numCPUs > max {
+		return 0, 0, fmt.Errorf("requested %d vCPUs exceeds the VCH maximum of %d", numCPUs, max)
+	}
+	if max := Config.Container.MaxContainerMemoryMB; max > 0 && memoryMB > max {
+		return 0, 0, fmt.Errorf("requested %d MB memory exceeds the VCH maximum of %d", memoryMB, max)
+	}
+
+	return numCPUs, memoryMB, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/lib/config"
+)
+
+func resetResourceConfig() {
+	Config.Container = config.Container{}
+}
+
+func TestResolveResourcesDefaults(t *testing.T) {
+	defer resetResourceConfig()
+	resetResourceConfig()
+
+	cpus, mem, err := ResolveResources(0, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, fallbackNumCPUs, cpus)
+	assert.EqualValues(t, fallbackMemoryMB, mem)
+
+	Config.Container.DefaultNumCPUs = 4
+	Config.Container.DefaultMemoryMB = 4096
+
+	cpus, mem, err = ResolveResources(0, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, cpus)
+	assert.EqualValues(t, 4096, mem)
+}
+
+func TestResolveResourcesExplicit(t *testing.T) {
+	defer resetResourceConfig()
+	resetResourceConfig()
+
+	cpus, mem, err := ResolveResources(1, 512)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cpus)
+	assert.EqualValues(t, 512, mem)
+}
+
+func TestResolveResourcesRejectsNegative(t *testing.T) {
+	defer resetResourceConfig()
+	resetResourceConfig()
+
+	_, _, err := ResolveResources(-1, 512)
+	assert.Error(t, err)
+
+	_, _, err = ResolveResources(1, -512)
+	assert.Error(t, err)
+}
+
+func TestResolveResourcesRejectsOverMax(t *testing.T) {
+	defer resetResourceConfig()
+	resetResourceConfig()
+
+	Config.Container.MaxContainerNumCPUs = 2
+	Config.Container.MaxContainerMemoryMB = 1024
+
+	_, _, err := ResolveResources(4, 512)
+	assert.Error(t, err)
+
+	_, _, err = ResolveResources(1, 2048)
+	assert.Error(t, err)
+
+	cpus, mem, err := ResolveResources(2, 1024)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, cpus)
+	assert.EqualValues(t, 1024, mem)
+}
@@ -0,0 +1,95 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/config/executor"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/tasks"
+)
+
+// ApplyShaping applies bandwidth-limiting traffic shaping to the vNIC a container has on
+// the given network, for fault-injection testing (e.g. simulating a constrained link).
+// A nil shaping removes any shaping previously applied. The container's vNIC port only
+// exists on vSphere once the container has been created, so callers should invoke this
+// after the container is running rather than at handle-commit time.
+//
+// This only works for endpoints backed by a distributed virtual switch port, since
+// standard vSwitch portgroups have no per-VM shaping override - only a shaping policy
+// for the whole portgroup. vSphere itself has no facility to inject latency or packet
+// loss on a virtual network port, so those aren't offered here.
+func ApplyShaping(op trace.Operation, id string, networkName string, shaping *executor.ShapingConfig) error {
+	defer trace.End(trace.Begin(id, op))
+
+	port, err := resolveContainerDVSPort(op, id, networkName)
+	if err != nil {
+		return err
+	}
+
+	req := types.ReconfigureDVPort_Task{
+		This: port.DVS.Reference(),
+		Port: []types.DVPortConfigSpec{
+			{
+				Operation: string(types.ConfigSpecOperationEdit),
+				Key:       port.Card.Port.PortKey,
+				Setting: &types.VMwareDVSPortSetting{
+					DVPortSetting: types.DVPortSetting{
+						InShapingPolicy:  shapingPolicy(shaping),
+						OutShapingPolicy: shapingPolicy(shaping),
+					},
+				},
+			},
+		},
+	}
+
+	client := port.DVS.Client()
+	_, err = tasks.WaitForResultAndRetryIf(op, func(ctx context.Context) (tasks.Task, error) {
+		res, err := methods.ReconfigureDVPort_Task(ctx, client, &req)
+		if err != nil {
+			return nil, err
+		}
+		return object.NewTask(client, res.Returnval), nil
+	}, tasks.IsTransientError)
+	if err != nil {
+		return fmt.Errorf("unable to apply traffic shaping for container %s: %s", id, err)
+	}
+
+	op.Infof("Applied traffic shaping to container %s on network %s: %+v", id, networkName, shaping)
+	return nil
+}
+
+// shapingPolicy converts a ShapingConfig into the DVS traffic shaping policy vSphere
+// expects, or explicitly disables shaping if cfg is nil.
+func shapingPolicy(cfg *executor.ShapingConfig) *types.DVSTrafficShapingPolicy {
+	if cfg == nil {
+		return &types.DVSTrafficShapingPolicy{
+			Enabled: &types.BoolPolicy{Value: types.NewBool(false)},
+		}
+	}
+
+	return &types.DVSTrafficShapingPolicy{
+		Enabled:          &types.BoolPolicy{Value: types.NewBool(true)},
+		AverageBandwidth: &types.LongPolicy{Value: cfg.AverageBandwidth},
+		PeakBandwidth:    &types.LongPolicy{Value: cfg.PeakBandwidth},
+		BurstSize:        &types.LongPolicy{Value: cfg.BurstSize},
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/lib/config/executor"
+)
+
+func TestShapingPolicyNilDisables(t *testing.T) {
+	policy := shapingPolicy(nil)
+	assert.NotNil(t, policy.Enabled)
+	assert.False(t, *policy.Enabled.Value)
+}
+
+func TestShapingPolicyAppliesBandwidth(t *testing.T) {
+	policy := shapingPolicy(&executor.ShapingConfig{
+		AverageBandwidth: 1000,
+		PeakBandwidth:    2000,
+		BurstSize:        3000,
+	})
+
+	assert.True(t, *policy.Enabled.Value)
+	assert.Equal(t, int64(1000), policy.AverageBandwidth.Value)
+	assert.Equal(t, int64(2000), policy.PeakBandwidth.Value)
+	assert.Equal(t, int64(3000), policy.BurstSize.Value)
+}
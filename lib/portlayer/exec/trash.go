@@ -0,0 +1,229 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/vic/lib/portlayer/event/events"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+	"github.com/vmware/vic/pkg/vsphere/vm"
+)
+
+// trashReapInterval is how often the background reaper scans the trash for containers
+// past their retention window.
+const trashReapInterval = time.Minute
+
+// trashedContainer is what's needed to either restore a trashed container's VM to the
+// inventory, unchanged, or to permanently delete its files once its retention expires.
+// The VM itself is unregistered - not destroyed - while it sits in the trash, so none of
+// this needs a live *vm.VirtualMachine.
+type trashedContainer struct {
+	id   string
+	name string
+
+	// datastore path to the VM's .vmx file, as vSphere reported it while the VM was
+	// still registered.
+	path string
+	vapp *types.ManagedObjectReference
+	pool *types.ManagedObjectReference
+	host *types.ManagedObjectReference
+
+	trashedAt   time.Time
+	retainUntil time.Time
+}
+
+// trash holds containers this VCH has soft-removed but not yet permanently deleted.
+var trash = &trashRegistry{containers: make(map[string]*trashedContainer)}
+
+type trashRegistry struct {
+	mu         sync.Mutex
+	containers map[string]*trashedContainer
+}
+
+func (t *trashRegistry) put(tc *trashedContainer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.containers[tc.id] = tc
+}
+
+func (t *trashRegistry) get(id string) *trashedContainer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.containers[id]
+}
+
+func (t *trashRegistry) remove(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.containers, id)
+}
+
+func (t *trashRegistry) due(now time.Time) []*trashedContainer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var due []*trashedContainer
+	for _, tc := range t.containers {
+		if !now.Before(tc.retainUntil) {
+			due = append(due, tc)
+		}
+	}
+	return due
+}
+
+// Trash unregisters a container's VM without deleting its files or disks, and remembers
+// it for retention past that point, restorable via RestoreContainer until the background
+// reaper permanently deletes it.
+func (c *Container) Trash(op trace.Operation, sess *session.Session, retention time.Duration) error {
+	defer trace.End(trace.Begin(c.ExecConfig.ID, op))
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.vm == nil {
+		return NotFoundError{}
+	}
+
+	if c.state == StateRunning {
+		return RemovePowerError{fmt.Errorf("container %s is powered on", c)}
+	}
+
+	existingState := c.updateState(op, StateRemoving)
+
+	var mvm mo.VirtualMachine
+	properties := []string{"summary.config", "summary.runtime.host", "resourcePool", "parentVApp"}
+	if err := c.vm.Properties(op, c.vm.Reference(), properties, &mvm); err != nil {
+		op.Errorf("Failed to get VM properties for %s: %s", c, err)
+		c.updateState(op, existingState)
+		return err
+	}
+
+	if err := c.vm.Unregister(op); err != nil {
+		op.Errorf("Failed to unregister %s for trash: %s", c, err)
+		c.updateState(op, existingState)
+		return err
+	}
+
+	now := time.Now()
+	trash.put(&trashedContainer{
+		id:          c.ExecConfig.ID,
+		name:        mvm.Summary.Config.Name,
+		path:        mvm.Summary.Config.VmPathName,
+		vapp:        mvm.ParentVApp,
+		pool:        mvm.ResourcePool,
+		host:        mvm.Summary.Runtime.Host,
+		trashedAt:   now,
+		retainUntil: now.Add(retention),
+	})
+
+	Containers.Remove(c.ExecConfig.ID)
+	publishContainerEvent(op, c.ExecConfig.ID, now, events.ContainerTrashed)
+
+	return nil
+}
+
+// RestoreContainer re-registers a trashed container's VM and puts it back in the live
+// container cache, undoing Trash as long as the reaper hasn't already permanently
+// deleted it.
+func RestoreContainer(op trace.Operation, sess *session.Session, id string) (*Container, error) {
+	tc := trash.get(id)
+	if tc == nil {
+		return nil, NotFoundError{}
+	}
+
+	containerVM, err := registerTrashedVM(op, sess, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	var mvm mo.VirtualMachine
+	if err := containerVM.Properties(op, containerVM.Reference(), []string{"config", "runtime"}, &mvm); err != nil {
+		return nil, err
+	}
+
+	c := newContainer(newBase(containerVM, mvm.Config, &mvm.Runtime))
+	Containers.Put(c)
+	trash.remove(id)
+
+	publishContainerEvent(op, id, time.Now(), events.ContainerRestored)
+
+	return c, nil
+}
+
+// registerTrashedVM re-registers tc's VM. The moref containerVM is constructed with is a
+// placeholder - Register never dereferences it, only replaces it with the real one vSphere
+// assigns on successful registration.
+func registerTrashedVM(op trace.Operation, sess *session.Session, tc *trashedContainer) (*vm.VirtualMachine, error) {
+	containerVM := vm.NewVirtualMachine(op, sess, types.ManagedObjectReference{Type: "VirtualMachine"})
+	if err := containerVM.Register(op, tc.path, tc.name, tc.vapp, tc.pool, tc.host, sess.VCHFolder); err != nil {
+		return nil, err
+	}
+
+	return containerVM, nil
+}
+
+// reapTrash permanently deletes the files (and so disks) of every trashed container whose
+// retention has expired, exactly as Remove would have deleted them immediately.
+func reapTrash(op trace.Operation, sess *session.Session) {
+	for _, tc := range trash.due(time.Now()) {
+		if err := purgeTrashedContainer(op, sess, tc); err != nil {
+			op.Errorf("trash reaper: %s: %s", tc.id, err.Error())
+			continue
+		}
+
+		trash.remove(tc.id)
+	}
+}
+
+func purgeTrashedContainer(op trace.Operation, sess *session.Session, tc *trashedContainer) error {
+	var dsPath object.DatastorePath
+	if !dsPath.FromString(tc.path) {
+		return fmt.Errorf("unable to parse datastore path %q", tc.path)
+	}
+
+	ds, err := sess.Finder.Datastore(op, dsPath.Datastore)
+	if err != nil {
+		return err
+	}
+
+	fm := ds.NewFileManager(sess.Datacenter, true)
+	return fm.Delete(op, path.Dir(dsPath.Path))
+}
+
+// StartTrashReaper runs reapTrash on trashReapInterval until ctx is done.
+func StartTrashReaper(ctx context.Context, sess *session.Session) {
+	go func() {
+		ticker := time.NewTicker(trashReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reapTrash(trace.NewOperation(ctx, "trash reaper"), sess)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
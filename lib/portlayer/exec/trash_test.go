@@ -0,0 +1,46 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashRegistryPutGetRemove(t *testing.T) {
+	r := &trashRegistry{containers: make(map[string]*trashedContainer)}
+
+	assert.Nil(t, r.get("abc"))
+
+	r.put(&trashedContainer{id: "abc"})
+	assert.NotNil(t, r.get("abc"))
+
+	r.remove("abc")
+	assert.Nil(t, r.get("abc"))
+}
+
+func TestTrashRegistryDueOnlyReturnsExpired(t *testing.T) {
+	r := &trashRegistry{containers: make(map[string]*trashedContainer)}
+	now := time.Now()
+
+	r.put(&trashedContainer{id: "expired", retainUntil: now.Add(-time.Minute)})
+	r.put(&trashedContainer{id: "fresh", retainUntil: now.Add(time.Hour)})
+
+	due := r.due(now)
+	assert.Len(t, due, 1)
+	assert.Equal(t, "expired", due[0].id)
+}
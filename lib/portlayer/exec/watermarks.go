@@ -0,0 +1,166 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/mo"
+
+	"github.com/vmware/vic/lib/portlayer/event/events"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// watermarkPollInterval is how often the background watermark monitor samples running
+// containers' memory usage.
+const watermarkPollInterval = 30 * time.Second
+
+// memoryWatermarkPercent is the fraction of a container's configured memory limit that,
+// once sustained for memoryWatermarkSustain, triggers a ContainerMemoryWatermark event -
+// early warning that a container is at risk of an in-guest OOM.
+const memoryWatermarkPercent = 90.0
+
+// memoryWatermarkSustain is how long usage must stay at or above memoryWatermarkPercent
+// before the watermark event fires, so a single spiky sample doesn't page anyone.
+const memoryWatermarkSustain = 5 * time.Minute
+
+// watermarkMonitor tracks, per container, how long memory usage has been continuously at or
+// above memoryWatermarkPercent, so a sustained crossing can be told apart from a brief spike.
+type watermarkMonitor struct {
+	m sync.Mutex
+
+	// aboveSince holds the time each container's usage first crossed the watermark; a
+	// container absent from the map is currently below it.
+	aboveSince map[string]time.Time
+
+	// alerted records containers for which the sustained-crossing event has already been
+	// published, so the monitor doesn't republish on every poll while usage stays high.
+	alerted map[string]bool
+}
+
+func newWatermarkMonitor() *watermarkMonitor {
+	return &watermarkMonitor{
+		aboveSince: make(map[string]time.Time),
+		alerted:    make(map[string]bool),
+	}
+}
+
+// startWatermarkMonitor runs sampling on watermarkPollInterval until ctx is done.
+func startWatermarkMonitor(ctx context.Context, sess *session.Session) {
+	wm := newWatermarkMonitor()
+
+	go func() {
+		ticker := time.NewTicker(watermarkPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				wm.sample(ctx, sess)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// sample checks memory usage for every running container and publishes a
+// ContainerMemoryWatermark event for any container that has been at or above
+// memoryWatermarkPercent of its memory limit for at least memoryWatermarkSustain.
+func (wm *watermarkMonitor) sample(ctx context.Context, sess *session.Session) {
+	op := trace.NewOperation(ctx, "watermark sample")
+
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	for _, c := range Containers.Containers([]State{StateRunning}) {
+		id := c.ExecConfig.ID
+		seen[id] = true
+
+		percent, ok := wm.memoryUsagePercent(op, c)
+		if !ok {
+			continue
+		}
+
+		if wm.record(id, now, percent) {
+			op.Warnf("Container %s memory usage has been at or above %.0f%% of its limit for %s", id, memoryWatermarkPercent, memoryWatermarkSustain)
+			publishContainerEvent(op, id, now, events.ContainerMemoryWatermark)
+		}
+	}
+
+	wm.forgetStale(seen)
+}
+
+// record updates the crossing state for a container given its latest usage sample. It
+// returns true the first time a sustained crossing is detected for that container.
+func (wm *watermarkMonitor) record(id string, now time.Time, percent float64) bool {
+	wm.m.Lock()
+	defer wm.m.Unlock()
+
+	if percent < memoryWatermarkPercent {
+		delete(wm.aboveSince, id)
+		delete(wm.alerted, id)
+		return false
+	}
+
+	since, tracked := wm.aboveSince[id]
+	if !tracked {
+		wm.aboveSince[id] = now
+		return false
+	}
+
+	if wm.alerted[id] || now.Sub(since) < memoryWatermarkSustain {
+		return false
+	}
+
+	wm.alerted[id] = true
+	return true
+}
+
+// forgetStale drops tracking state for containers no longer running, so a container that
+// stops while above the watermark doesn't leave a stale entry behind.
+func (wm *watermarkMonitor) forgetStale(seen map[string]bool) {
+	wm.m.Lock()
+	defer wm.m.Unlock()
+
+	for id := range wm.aboveSince {
+		if !seen[id] {
+			delete(wm.aboveSince, id)
+			delete(wm.alerted, id)
+		}
+	}
+}
+
+// memoryUsagePercent returns the container's current guest memory usage as a percentage of
+// its configured memory limit. ok is false if usage can't be determined - a container with
+// no configured limit, or a VM whose summary can't be refreshed.
+func (wm *watermarkMonitor) memoryUsagePercent(op trace.Operation, c *Container) (float64, bool) {
+	info := c.Info()
+	if info.MemorySizeMB <= 0 || info.vm == nil {
+		return 0, false
+	}
+
+	var o mo.VirtualMachine
+	if err := info.vm.Properties(op, info.vm.Reference(), []string{"summary.quickStats"}, &o); err != nil {
+		op.Debugf("watermark monitor: unable to refresh summary for container %s: %s", c.ExecConfig.ID, err)
+		return 0, false
+	}
+
+	usageMB := float64(o.Summary.QuickStats.HostMemoryUsage)
+	return usageMB / float64(info.MemorySizeMB) * 100, true
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatermarkMonitorRecord(t *testing.T) {
+	wm := newWatermarkMonitor()
+
+	start := time.Now()
+
+	// below the watermark never alerts
+	assert.False(t, wm.record("c1", start, 50))
+
+	// crossing the watermark starts the clock but doesn't alert immediately
+	assert.False(t, wm.record("c1", start, 95))
+	assert.False(t, wm.record("c1", start.Add(time.Minute), 95))
+
+	// once sustained past memoryWatermarkSustain, it alerts exactly once
+	assert.True(t, wm.record("c1", start.Add(memoryWatermarkSustain+time.Second), 95))
+	assert.False(t, wm.record("c1", start.Add(memoryWatermarkSustain+2*time.Second), 95))
+
+	// dropping back below the watermark resets the tracking
+	assert.False(t, wm.record("c1", start.Add(memoryWatermarkSustain+3*time.Second), 50))
+	assert.False(t, wm.record("c1", start.Add(memoryWatermarkSustain+4*time.Second), 95))
+}
+
+func TestWatermarkMonitorForgetStale(t *testing.T) {
+	wm := newWatermarkMonitor()
+
+	now := time.Now()
+	wm.record("gone", now, 95)
+	wm.record("still-here", now, 95)
+
+	wm.forgetStale(map[string]bool{"still-here": true})
+
+	wm.m.Lock()
+	defer wm.m.Unlock()
+
+	_, goneTracked := wm.aboveSince["gone"]
+	_, stillTracked := wm.aboveSince["still-here"]
+
+	assert.False(t, goneTracked)
+	assert.True(t, stillTracked)
+}
@@ -0,0 +1,227 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jobs runs a container from a stored CreateConfig on a fixed interval,
+// waits for it to exit, then removes it - a cron-like way to run short-lived
+// containers without an external scheduler polling the portlayer.
+//
+// Cron-syntax schedules ("5 4 * * *") aren't supported - there's no cron parser
+// vendored in this tree, and hand-rolling one for the sake of it would just be a
+// second, worse implementation of something like robfig/cron. A fixed interval
+// covers the common case; the Schedule type is the natural place to grow richer
+// expressions if that dependency ever gets vendored.
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// maxHistory bounds how many past runs a Job remembers.
+const maxHistory = 20
+
+// commitWaitTime is how long a job's container commit is allowed to wait for the
+// backing VM operation, mirroring the docker personality's own default.
+const commitWaitTime = 60
+
+// Schedule describes when a Job runs. Interval is the only supported mode today.
+type Schedule struct {
+	// Interval is the fixed period between runs. The first run happens Interval
+	// after the job is created.
+	Interval time.Duration
+}
+
+// Run records the outcome of one trigger of a Job.
+type Run struct {
+	ContainerID string
+	Start       time.Time
+	End         time.Time
+	Error       string
+}
+
+// Job is a stored CreateConfig plus a Schedule that triggers it repeatedly.
+type Job struct {
+	ID       string
+	Name     string
+	Config   *exec.ContainerCreateConfig
+	Schedule Schedule
+
+	mu      sync.Mutex
+	history []Run
+
+	cancel context.CancelFunc
+}
+
+// History returns the job's most recent runs, oldest first.
+func (j *Job) History() []Run {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	h := make([]Run, len(j.history))
+	copy(h, j.history)
+	return h
+}
+
+func (j *Job) recordRun(r Run) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.history = append(j.history, r)
+	if len(j.history) > maxHistory {
+		j.history = j.history[len(j.history)-maxHistory:]
+	}
+}
+
+// Manager owns the set of scheduled Jobs and the goroutine driving each one.
+type Manager struct {
+	session *session.Session
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewManager returns a Manager that creates containers via sess.
+func NewManager(sess *session.Session) *Manager {
+	return &Manager{
+		session: sess,
+		jobs:    make(map[string]*Job),
+	}
+}
+
+// Create stores a new Job and starts triggering it on its Schedule.
+func (m *Manager) Create(name string, cfg *exec.ContainerCreateConfig, sched Schedule) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	j := &Job{
+		ID:       uid.New().String(),
+		Name:     name,
+		Config:   cfg,
+		Schedule: sched,
+		cancel:   cancel,
+	}
+
+	m.mu.Lock()
+	m.jobs[j.ID] = j
+	m.mu.Unlock()
+
+	go m.run(ctx, j)
+
+	return j
+}
+
+// List returns every registered Job.
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+// Get returns the Job with the given id, if any.
+func (m *Manager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	return j, ok
+}
+
+// Delete stops a Job's future triggers and forgets it. In-flight runs are left to
+// finish and clean up their own container.
+func (m *Manager) Delete(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return false
+	}
+
+	j.cancel()
+	delete(m.jobs, id)
+	return true
+}
+
+// run fires j on its Schedule until ctx is cancelled.
+func (m *Manager) run(ctx context.Context, j *Job) {
+	ticker := time.NewTicker(j.Schedule.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.trigger(ctx, j)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// trigger creates and starts one container from j.Config, waits for it to stop,
+// then removes it, recording the outcome in j's history.
+func (m *Manager) trigger(ctx context.Context, j *Job) {
+	// a run is detached from the job's context, so a Delete while it's in flight still lets
+	// it wait for its container to stop and remove it
+	op := trace.NewOperation(context.Background(), "job %s (%s)", j.Name, j.ID)
+
+	run := Run{Start: time.Now()}
+	defer func() {
+		run.End = time.Now()
+		j.recordRun(run)
+	}()
+
+	h, err := exec.Create(op, m.session, j.Config)
+	if err != nil {
+		op.Errorf("job %s: create failed: %s", j.ID, err)
+		run.Error = err.Error()
+		return
+	}
+	run.ContainerID = h.ExecConfig.ID
+
+	h.SetTargetState(exec.StateRunning)
+	waitTime := int32(commitWaitTime)
+	if err := h.Commit(op, m.session, &waitTime); err != nil {
+		op.Errorf("job %s: commit failed: %s", j.ID, err)
+		run.Error = err.Error()
+		return
+	}
+
+	c := exec.Containers.Container(run.ContainerID)
+	if c == nil {
+		op.Errorf("job %s: container %s vanished after commit", j.ID, run.ContainerID)
+		run.Error = "container not found after create"
+		return
+	}
+
+	<-c.WaitForState(exec.StateStopped)
+
+	if err := c.Remove(op, m.session); err != nil {
+		op.Errorf("job %s: removing container %s failed: %s", j.ID, run.ContainerID, err)
+		run.Error = err.Error()
+	}
+
+	log.Infof("job %s: run of container %s complete", j.ID, run.ContainerID)
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journal records the intent behind a multi-step container mutation
+// (create or remove) to the datastore before the steps that carry it out run.
+// The existing orphan reconciler in lib/portlayer/exec already re-adopts
+// container VMs that show up in vSphere inventory but not in the live cache -
+// this package gives that recovery path something more useful than a plain
+// inventory diff to work from: a record of which operation was in flight and
+// how far it got, so a portlayer restart after a crash mid-create or
+// mid-remove can tell a genuinely half-finished mutation apart from a VM that
+// was simply never touched by the portlayer at all.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/vmware/vic/pkg/kvstore"
+)
+
+// Operation identifies the kind of multi-step mutation an Entry describes.
+type Operation string
+
+const (
+	OpCreate Operation = "create"
+	OpRemove Operation = "remove"
+)
+
+// Phase identifies how far an Operation has progressed. Phases are
+// operation-specific and are otherwise opaque to the journal itself - it's
+// up to the caller to define and interpret the phases that matter for
+// deciding whether a half-finished mutation can be completed or must be
+// rolled back.
+type Phase string
+
+// entryKeyPrefix namespaces journal entries so Pending's List call can find
+// them all with a single prefix match.
+const entryKeyPrefix = "journal.entries."
+
+// Entry describes one in-flight multi-step mutation.
+type Entry struct {
+	Operation   Operation
+	ContainerID string
+	Phase       Phase
+}
+
+// Journal persists Entry records to a kvstore.KeyValueStore so they survive
+// a portlayer crash or restart.
+type Journal struct {
+	kv kvstore.KeyValueStore
+}
+
+// NewJournal returns a Journal backed by kv. kv may be nil, in which case
+// the journal silently no-ops - this mirrors network.Context's handling of
+// a nil kv store, so callers that can't or don't want to persist journal
+// entries (e.g. in tests) don't need a special case.
+func NewJournal(kv kvstore.KeyValueStore) *Journal {
+	return &Journal{kv: kv}
+}
+
+func entryKey(containerID string) string {
+	return fmt.Sprintf("%s%s", entryKeyPrefix, containerID)
+}
+
+// Begin records that op is starting against containerID, at phase. It
+// overwrites any previous entry for the same container - a container can
+// only be in the middle of one journaled mutation at a time.
+func (j *Journal) Begin(ctx context.Context, op Operation, containerID string, phase Phase) error {
+	if j.kv == nil {
+		return nil
+	}
+
+	return j.put(ctx, &Entry{Operation: op, ContainerID: containerID, Phase: phase})
+}
+
+// Advance updates the phase of containerID's in-flight entry.
+func (j *Journal) Advance(ctx context.Context, containerID string, phase Phase) error {
+	if j.kv == nil {
+		return nil
+	}
+
+	entry, err := j.Get(containerID)
+	if err != nil {
+		return err
+	}
+
+	entry.Phase = phase
+	return j.put(ctx, entry)
+}
+
+// Complete removes containerID's entry, marking its journaled mutation as
+// finished. It is not an error to complete an entry that isn't there - a
+// journal with a nil kv store, or a caller completing the same container
+// twice, both end up here.
+func (j *Journal) Complete(ctx context.Context, containerID string) error {
+	if j.kv == nil {
+		return nil
+	}
+
+	if err := j.kv.Delete(ctx, entryKey(containerID)); err != nil && err != kvstore.ErrKeyNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the in-flight entry for containerID, or kvstore.ErrKeyNotFound
+// if there isn't one.
+func (j *Journal) Get(containerID string) (*Entry, error) {
+	if j.kv == nil {
+		return nil, kvstore.ErrKeyNotFound
+	}
+
+	raw, err := j.kv.Get(entryKey(containerID))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(raw, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Pending returns every entry left behind by a mutation that never called
+// Complete - the set of containers a recovery pass needs to reconcile.
+func (j *Journal) Pending(ctx context.Context) ([]*Entry, error) {
+	if j.kv == nil {
+		return nil, nil
+	}
+
+	values, err := j.kv.List(regexp.QuoteMeta(entryKeyPrefix) + `.+`)
+	if err != nil && err != kvstore.ErrKeyNotFound {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(values))
+	for k, v := range values {
+		entry := &Entry{}
+		if err := json.Unmarshal(v, entry); err != nil {
+			return nil, fmt.Errorf("journal: corrupt entry at key %s: %s", k, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (j *Journal) put(ctx context.Context, entry *Entry) error {
+	d, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return j.kv.Put(ctx, entryKey(entry.ContainerID), d)
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/vic/pkg/kvstore"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+func newTestJournal(t *testing.T) *Journal {
+	op := trace.NewOperation(context.Background(), "test")
+
+	kv, err := kvstore.NewKeyValueStore(op, &kvstore.MockBackend{}, "journal")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return NewJournal(kv)
+}
+
+func TestBeginAdvanceComplete(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	j := newTestJournal(t)
+
+	assert.NoError(t, j.Begin(op, OpCreate, "c1", Phase("validating")))
+
+	entry, err := j.Get("c1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, OpCreate, entry.Operation)
+		assert.Equal(t, Phase("validating"), entry.Phase)
+	}
+
+	assert.NoError(t, j.Advance(op, "c1", Phase("creating-vm")))
+
+	entry, err = j.Get("c1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Phase("creating-vm"), entry.Phase)
+	}
+
+	assert.NoError(t, j.Complete(op, "c1"))
+
+	_, err = j.Get("c1")
+	assert.Equal(t, kvstore.ErrKeyNotFound, err)
+}
+
+func TestPendingListsUncompletedEntries(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	j := newTestJournal(t)
+
+	assert.NoError(t, j.Begin(op, OpCreate, "c1", Phase("creating-vm")))
+	assert.NoError(t, j.Begin(op, OpRemove, "c2", Phase("detaching-disks")))
+
+	pending, err := j.Pending(op)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, pending, 2)
+
+	assert.NoError(t, j.Complete(op, "c1"))
+
+	pending, err = j.Pending(op)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "c2", pending[0].ContainerID)
+	}
+}
+
+func TestNilKVStoreNoOps(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	j := NewJournal(nil)
+
+	assert.NoError(t, j.Begin(op, OpCreate, "c1", Phase("validating")))
+
+	_, err := j.Get("c1")
+	assert.Equal(t, kvstore.ErrKeyNotFound, err)
+
+	pending, err := j.Pending(op)
+	assert.NoError(t, err)
+	assert.Nil(t, pending)
+}
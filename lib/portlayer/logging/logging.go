@@ -135,7 +135,10 @@ func toggle(handle *exec.Handle, connected bool) (*exec.Handle, error) {
 	return handle, nil
 }
 
-// Join adds two file backed serial port and configures them
+// Join adds two file backed serial port and configures them. It's a no-op if
+// handle already carries file backed serial ports - Create adds them
+// unconditionally so every container gets boot-time serial capture regardless
+// of whether its creator remembers to call Join.
 func Join(h interface{}) (interface{}, error) {
 	defer trace.End(trace.Begin(""))
 
@@ -144,6 +147,16 @@ func Join(h interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("Type assertion failed for %#+v", handle)
 	}
 
+	for _, change := range handle.Spec.Spec().DeviceChange {
+		if _, ok := change.GetVirtualDeviceConfigSpec().Device.(*types.VirtualSerialPort); !ok {
+			continue
+		}
+
+		if _, ok := change.GetVirtualDeviceConfigSpec().Device.GetVirtualDevice().Backing.(*types.VirtualSerialPortFileBackingInfo); ok {
+			return handle, nil
+		}
+	}
+
 	var logFilePath string
 
 	VMPathName := handle.Spec.VMPathName()
@@ -89,7 +89,7 @@ func newSupervisor(session *session.Session) *super {
 func (s *super) Subscribe(op trace.Operation, subscriber interface{}) (chan interface{}, error) {
 	switch sub := subscriber.(type) {
 	case *exec.Container:
-		return s.vms.Subscribe(op, sub.VMReference(), sub.String())
+		return s.vms.Subscribe(op, sub.VMReference(), sub.String(), endpointNames(sub))
 	}
 
 	err := UnsupportedTypeError{
@@ -100,6 +100,24 @@ func (s *super) Subscribe(op trace.Operation, subscriber interface{}) (chan inte
 	return nil, err
 }
 
+// endpointNames maps a container's NIC PCI slot numbers to the docker network
+// names they're attached to, so per-NIC stats can be reported by network name
+// (e.g. "bridge") instead of the generic vSphere device name (e.g. "ethernet-0").
+func endpointNames(c *exec.Container) map[string]string {
+	if c.ExecConfig == nil {
+		return nil
+	}
+
+	endpoints := make(map[string]string, len(c.ExecConfig.Networks))
+	for _, ne := range c.ExecConfig.Networks {
+		if ne.ID == "" {
+			continue
+		}
+		endpoints[ne.ID] = ne.Network.Name
+	}
+	return endpoints
+}
+
 func (s *super) Unsubscribe(op trace.Operation, subscriber interface{}, ch chan interface{}) {
 	switch sub := subscriber.(type) {
 	case *exec.Container:
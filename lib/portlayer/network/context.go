@@ -31,6 +31,7 @@ import (
 	"github.com/vmware/vic/lib/config/executor"
 	"github.com/vmware/vic/lib/constants"
 	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/lib/portlayer/network/registrar"
 	"github.com/vmware/vic/lib/spec"
 	"github.com/vmware/vic/pkg/ip"
 	"github.com/vmware/vic/pkg/kvstore"
@@ -66,6 +67,7 @@ type AddContainerOptions struct {
 	Aliases     []string
 	Ports       []string
 	Nameservers []string
+	Shaping     *executor.ShapingConfig
 }
 
 func NewContext(config *Configuration, kv kvstore.KeyValueStore) (*Context, error) {
@@ -515,6 +517,10 @@ type ScopeData struct {
 	Pools       []string
 	Annotations map[string]string
 	Internal    bool
+	// IPAM selects how the scope hands out addresses to endpoints that don't request a
+	// specific static IP: IPAMPolicySequential (default), IPAMPolicyRandom, or
+	// IPAMPolicySticky. Empty is treated as IPAMPolicySequential.
+	IPAM string
 }
 
 func (c *Context) NewScope(ctx context.Context, scopeData *ScopeData) (*Scope, error) {
@@ -809,6 +815,16 @@ func (c *Context) bindContainer(op trace.Operation, h *exec.Handle) ([]*Endpoint
 			}
 		}
 
+		if !ip.IsUnspecifiedIP(e.IP()) {
+			registrar.Notify(registrar.Record{
+				Action:      registrar.Allocated,
+				ContainerID: con.id.String(),
+				Name:        con.name,
+				Network:     s.Name(),
+				IP:          e.IP().String(),
+			})
+		}
+
 		endpoints = append(endpoints, e)
 	}
 
@@ -998,6 +1014,16 @@ func (c *Context) UnbindContainer(op trace.Operation, h *exec.Handle) ([]*Endpoi
 			return nil, err
 		}
 
+		if !ip.IsUnspecifiedIP(e.IP()) {
+			registrar.Notify(registrar.Record{
+				Action:      registrar.Released,
+				ContainerID: con.id.String(),
+				Name:        con.name,
+				Network:     s.Name(),
+				IP:          e.IP().String(),
+			})
+		}
+
 		// clear out assigned ip
 		ne.Assigned.IP = net.IPv4zero
 
@@ -1197,12 +1223,18 @@ func (c *Context) AddContainer(h *exec.Handle, options *AddContainerOptions) err
 		Network: executor.ContainerNetwork{
 			Common: executor.Common{
 				Name: s.Name(),
+				// ID carries the network's own moref, taken directly from the scope's
+				// NetworkReference rather than inferred from another object's moref -
+				// callers that need to resolve the backing precisely (e.g. for capture
+				// or shaping) shouldn't have to re-derive it themselves.
+				ID: s.Network().Reference().Value,
 			},
 			Aliases:    options.Aliases,
 			Type:       s.Type(),
 			TrustLevel: s.TrustLevel(),
 		},
-		Ports: options.Ports,
+		Ports:   options.Ports,
+		Shaping: options.Shaping,
 	}
 	pools := s.Pools()
 	ne.Network.Pools = make([]ip.Range, len(pools))
@@ -1222,6 +1254,10 @@ func (c *Context) AddContainer(h *exec.Handle, options *AddContainerOptions) err
 	ne.Static = false
 
 	if len(options.IP) > 0 && !ip.IsUnspecifiedIP(options.IP) {
+		if err = s.validateStaticIP(options.IP); err != nil {
+			return err
+		}
+
 		ne.Static = true
 		ne.IP = &net.IPNet{
 			IP:   options.IP,
@@ -1414,6 +1450,70 @@ func (c *Context) deleteScope(s *Scope) {
 	delete(c.scopes, s.Name())
 }
 
+// AddPool expands scope name's IP pool with additional ranges, without recreating the scope
+// or disturbing any of its existing endpoints - for long-lived bridge networks that have
+// simply run out of addresses. Each range in pools must lie entirely within the scope's
+// existing subnet; growing the subnet itself still requires recreating the scope.
+func (c *Context) AddPool(ctx context.Context, name string, pools []string) (*Scope, error) {
+	defer trace.End(trace.Begin(""))
+
+	c.Lock()
+	defer c.Unlock()
+
+	s, err := c.resolveScope(name)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ResourceNotFoundError{}
+	}
+
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("no pools specified")
+	}
+
+	added := make([]*AddressSpace, 0, len(pools))
+	for _, p := range pools {
+		r := ip.ParseRange(p)
+		if r == nil {
+			return nil, fmt.Errorf("invalid pool %s specified for scope %s", p, s.name)
+		}
+
+		if !ip.IsRoutableIP(r.FirstIP, s.subnet) || !ip.IsRoutableIP(r.LastIP, s.subnet) {
+			return nil, fmt.Errorf("pool %s is not within scope %s's subnet %s", p, s.name, s.subnet)
+		}
+
+		for _, existing := range s.pools() {
+			if existing != nil && existing.Overlaps(*r) {
+				return nil, fmt.Errorf("pool %s overlaps with an existing pool %s on scope %s", p, existing, s.name)
+			}
+		}
+		for _, other := range added {
+			if other.Pool != nil && other.Pool.Overlaps(*r) {
+				return nil, fmt.Errorf("pool %s overlaps with another pool being added to scope %s", p, s.name)
+			}
+		}
+
+		added = append(added, NewAddressSpaceFromRange(r.FirstIP, r.LastIP))
+	}
+
+	s.spaces = append(s.spaces, added...)
+
+	if c.kv != nil {
+		var d []byte
+		d, err = s.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.kv.Put(ctx, scopeKey(s.Name()), d); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
 // When invoking docker rename operation, the container name is changed
 // This method is to change the container name in network and alias for dns query
 func (c *Context) UpdateContainerNameInScope(h *exec.Handle) error {
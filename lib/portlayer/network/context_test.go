@@ -1468,3 +1468,47 @@ func TestDeleteScopeLimits(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, numScopes, len(scopes))
 }
+
+func TestContextAddPool(t *testing.T) {
+	kv := &kvstore.MockKeyValueStore{}
+	kv.On("List", mock.Anything).Return(nil, nil)
+	kv.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ctx, err := NewContext(testConfig(), kv)
+	assert.NoError(t, err)
+
+	s, err := ctx.NewScope(context.TODO(), &ScopeData{
+		ScopeType: constants.BridgeScopeType,
+		Name:      "pooltest",
+		Subnet:    &net.IPNet{IP: net.IPv4(10, 14, 0, 0), Mask: net.CIDRMask(16, 32)},
+		Pools:     []string{"10.14.1.0/24"},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+
+	// unknown scope
+	_, err = ctx.AddPool(context.TODO(), "no-such-scope", []string{"10.14.2.0/24"})
+	assert.Error(t, err)
+
+	// no pools specified
+	_, err = ctx.AddPool(context.TODO(), s.Name(), nil)
+	assert.Error(t, err)
+
+	// pool outside the scope's subnet
+	_, err = ctx.AddPool(context.TODO(), s.Name(), []string{"10.15.2.0/24"})
+	assert.Error(t, err)
+
+	// pool overlapping the scope's existing pool
+	_, err = ctx.AddPool(context.TODO(), s.Name(), []string{"10.14.1.128/25"})
+	assert.Error(t, err)
+
+	before := len(s.Pools())
+
+	updated, err := ctx.AddPool(context.TODO(), s.Name(), []string{"10.14.2.0/24"})
+	assert.NoError(t, err)
+	assert.Equal(t, s, updated)
+	assert.Equal(t, before+1, len(s.Pools()))
+
+	// the newly added pool is actually usable
+	_, err = s.spaces[len(s.spaces)-1].ReserveNextIP4()
+	assert.NoError(t, err)
+}
@@ -41,6 +41,11 @@ type Endpoint struct {
 	aliases   map[string][]alias
 	gw        *net.IP
 	subnet    *net.IPNet
+
+	// ipConflict is set when a vSphere MAC conflict event has been seen for this
+	// endpoint's vNIC, indicating another device on the shared port group is
+	// answering ARP for the same address.
+	ipConflict bool
 }
 
 // scopeName returns the "fully qualified" name of an alias. Aliases are scoped
@@ -152,6 +157,19 @@ func (e *Endpoint) Ports() []Port {
 	return ports
 }
 
+// IPConflict reports whether a vSphere MAC conflict event has been seen for
+// this endpoint since it was last bound.
+func (e *Endpoint) IPConflict() bool {
+	return e.ipConflict
+}
+
+// SetIPConflict flags or clears this endpoint's conflict state. Callers must
+// hold the owning Context's lock, matching the locking convention used for
+// all other Endpoint mutation.
+func (e *Endpoint) SetIPConflict(conflict bool) {
+	e.ipConflict = conflict
+}
+
 func (e *Endpoint) addAlias(con, a string) (alias, bool) {
 	if a == "" {
 		return badAlias, false
@@ -29,6 +29,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
+	"math/rand"
 	"net"
 
 	log "github.com/Sirupsen/logrus"
@@ -351,6 +353,54 @@ func (s *AddressSpace) ReserveIP4(ip net.IP) error {
 	return err
 }
 
+// ReserveRandomIP4 reserves a uniformly random available IPv4 address, rather than
+// ReserveNextIP4's lowest-available one. It's used by the "random" scope IPAM policy for
+// users who'd rather not hand out predictably sequential addresses.
+func (s *AddressSpace) ReserveRandomIP4() (net.IP, error) {
+	var total int64
+	for _, r := range s.availableRanges {
+		total += ipCount(r) + 1
+	}
+
+	if total == 0 {
+		return nil, errors.New("no IP addresses available in allocated range")
+	}
+
+	offset := rand.Int63n(total)
+	for _, r := range s.availableRanges {
+		count := ipCount(r) + 1
+		if offset < count {
+			target := addOffsetIP4(r.FirstIP, offset)
+			if err := s.ReserveIP4(target); err != nil {
+				return nil, err
+			}
+			return target, nil
+		}
+		offset -= count
+	}
+
+	return nil, errors.New("no IP addresses available in allocated range")
+}
+
+// ipCount returns the number of addresses between r.FirstIP and r.LastIP beyond the first,
+// i.e. 0 for a single-address range.
+func ipCount(r *ip.Range) int64 {
+	first := big.NewInt(0).SetBytes(r.FirstIP.To16())
+	last := big.NewInt(0).SetBytes(r.LastIP.To16())
+	return big.NewInt(0).Sub(last, first).Int64()
+}
+
+// addOffsetIP4 returns the IPv4 address offset addresses after start.
+func addOffsetIP4(start net.IP, offset int64) net.IP {
+	i := big.NewInt(0).SetBytes(start.To16())
+	i.Add(i, big.NewInt(offset))
+
+	b := i.Bytes()
+	addr := make(net.IP, net.IPv6len)
+	copy(addr[net.IPv6len-len(b):], b)
+	return addr
+}
+
 // ReleaseIP4Range releases a sub address space into the parent address space.
 // Sub address space has to have only a single available range.
 func (s *AddressSpace) ReleaseIP4Range(space *AddressSpace) error {
@@ -137,6 +137,28 @@ func TestReserveIP4(t *testing.T) {
 	}
 }
 
+func TestReserveRandomIP4(t *testing.T) {
+	space := NewAddressSpaceFromRange(net.ParseIP("10.10.10.10"),
+		net.ParseIP("10.10.10.14"))
+
+	seen := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		ip, err := space.ReserveRandomIP4()
+		if err != nil {
+			t.Fatalf("space.ReserveRandomIP4() => (%s, %s), want (ip, nil)", ip, err)
+		}
+
+		if seen[ip.String()] {
+			t.Errorf("space.ReserveRandomIP4() returned already-reserved IP %s", ip)
+		}
+		seen[ip.String()] = true
+	}
+
+	if ip, err := space.ReserveRandomIP4(); err == nil {
+		t.Errorf("space.ReserveRandomIP4() => (%s, nil), want (_, error)", ip)
+	}
+}
+
 func TestReleaseIP4(t *testing.T) {
 	space := NewAddressSpaceFromRange(net.ParseIP("10.10.10.10"),
 		net.ParseIP("10.10.10.11"))
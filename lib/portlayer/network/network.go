@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	log "github.com/Sirupsen/logrus"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/vmware/vic/lib/portlayer/event"
 	"github.com/vmware/vic/lib/portlayer/event/events"
 	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/lib/portlayer/network/registrar"
 	"github.com/vmware/vic/lib/portlayer/store"
 	"github.com/vmware/vic/pkg/kvstore"
 	"github.com/vmware/vic/pkg/trace"
@@ -96,6 +98,11 @@ func Init(op trace.Operation, sess *session.Session, source extraconfig.DataSour
 			return
 		}
 
+		// wire up the IPAM/DNS notification hook, if the VCH was configured with one
+		if config.IPAMHookURL != "" {
+			registrar.Register(registrar.NewHTTPHook(config.IPAMHookURL))
+		}
+
 		var kv kvstore.KeyValueStore
 		kv, err = store.NewDatastoreKeyValue(op, sess, "network.contexts.default")
 		if err != nil {
@@ -159,6 +166,43 @@ func handleEvent(netctx *Context, ie events.Event) {
 			op.Warnf("Failed to commit handle after network unbind for container %s: %s", ie.Reference(), err)
 		}
 
+	case events.ContainerIPConflict:
+		op := trace.NewOperation(context.Background(), fmt.Sprintf("handleEvent(%s)", ie.EventID()))
+		op.Warnf("Handling Event: %s", ie.EventID())
+
+		// container events carry the container ID as their reference - resolve it via the
+		// exec container cache the same way the PoweredOff case above does.
+		handle, err := exec.GetContainer(op, uid.Parse(ie.Reference()))
+		if err != nil || handle == nil {
+			op.Warnf("Received IP conflict event for unknown container %s: %s", ie.Reference(), ie.Message())
+			return
+		}
+		defer handle.Close()
+
+		con := netctx.Container(handle.ExecConfig.ID)
+		if con == nil {
+			op.Warnf("Container %s has no network context registration, dropping IP conflict event", handle.ExecConfig.ID)
+			return
+		}
+
+		netctx.Lock()
+		for _, e := range con.Endpoints() {
+			e.SetIPConflict(true)
+		}
+		netctx.Unlock()
+
+		op.Errorf("Duplicate IP detected on container %s's network: %s", handle.ExecConfig.ID, ie.Message())
+
+	case events.ContainerRemoved:
+		op := trace.NewOperation(context.Background(), fmt.Sprintf("handleEvent(%s)", ie.EventID()))
+		op.Infof("Handling Event: %s", ie.EventID())
+
+		// exec's OnEvent handler has already dropped the container from its cache by the time
+		// this fires (e.g. it was deleted directly in vCenter rather than through the portlayer),
+		// so there's no handle to commit through - free the IP/endpoint reservations directly.
+		if _, err := netctx.RemoveIDFromScopes(op, ie.Reference()); err != nil {
+			op.Errorf("Failed to remove container %s from scopes: %s", ie.Reference(), err)
+		}
 	}
 	return
 }
@@ -198,12 +242,22 @@ func engageContext(op trace.Operation, netctx *Context, em event.EventManager) e
 
 	s.Suspend(true)
 	defer s.Resume()
-	for _, c := range exec.Containers.Containers(nil) {
+
+	containers := exec.Containers.Containers(nil)
+
+	// Refreshing a container's handle is a property collector round trip to vSphere, and on a
+	// VCH with hundreds of containers doing that sequentially is what actually pushes portlayer
+	// startup out to minutes. The refreshes are independent of each other, so fetch them with
+	// bounded parallelism; the rest of this loop (scope creation, binding) still runs serially
+	// below since it mutates netctx's shared scope/container maps.
+	handles, err := fetchContainerHandles(op, containers)
+	if err != nil {
+		return err
+	}
+
+	for i, c := range containers {
 		log.Debugf("adding container %s", c)
-		h, err := c.NewHandle(op)
-		if err != nil {
-			return err
-		}
+		h := handles[i]
 		defer h.Close()
 
 		// add any user created networks that show up in container's config
@@ -250,6 +304,59 @@ func engageContext(op trace.Operation, netctx *Context, em event.EventManager) e
 	return nil
 }
 
+// maxConcurrentHandleFetches bounds how many container VMs are refreshed from vSphere at once
+// while reconstructing network state at startup, trading off progress speed against not
+// overwhelming the property collector with hundreds of simultaneous requests.
+const maxConcurrentHandleFetches = 32
+
+// fetchContainerHandles resolves a *exec.Handle for each of containers concurrently, since each
+// is an independent property collector round trip and doing them one at a time is what makes
+// startup network reconciliation slow on VCHs with hundreds of containers. It returns as soon
+// as all fetches complete, or the first error encountered.
+func fetchContainerHandles(op trace.Operation, containers []*exec.Container) ([]*exec.Handle, error) {
+	handles := make([]*exec.Handle, len(containers))
+	errs := make([]error, len(containers))
+
+	sem := make(chan struct{}, maxConcurrentHandleFetches)
+	var wg sync.WaitGroup
+	var done int32
+
+	for i, c := range containers {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, c *exec.Container) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			handles[i], errs[i] = c.NewHandle(op)
+
+			n := atomic.AddInt32(&done, 1)
+			if n%50 == 0 || int(n) == len(containers) {
+				op.Infof("network reconciliation: refreshed %d/%d containers", n, len(containers))
+			}
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			// close every handle that was successfully fetched before the error - otherwise
+			// they leak into the handle LRU (lib/portlayer/exec/handle.go) with nothing left
+			// to ever close them.
+			for _, h := range handles {
+				if h != nil {
+					h.Close()
+				}
+			}
+			return nil, err
+		}
+	}
+
+	return handles, nil
+}
+
 func getBridgeLink(config *Configuration) (Link, error) {
 	// add the gateway address to the bridge interface
 	link, err := LinkByName(config.BridgeNetwork)
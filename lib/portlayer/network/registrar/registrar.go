@@ -0,0 +1,108 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registrar gives operators a hook into container endpoint allocation and release,
+// so an external IPAM/DNS system (e.g. Infoblox) can be kept in sync with container IPs and
+// names without polling the portlayer API.
+package registrar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Action describes what happened to the Record being reported.
+type Action string
+
+const (
+	// Allocated is reported once a container's endpoint has a real, assigned IP.
+	Allocated Action = "allocated"
+	// Released is reported when a container's endpoint IP is returned to the pool.
+	Released Action = "released"
+)
+
+// Record describes a single container endpoint allocation or release.
+type Record struct {
+	Action      Action   `json:"action"`
+	ContainerID string   `json:"containerID"`
+	Name        string   `json:"name"`
+	Network     string   `json:"network"`
+	IP          string   `json:"ip"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// Hook is notified of every endpoint allocation and release. A hook is best-effort: a
+// failure to notify does not fail or roll back the underlying network operation, since
+// losing IPAM/DNS sync is preferable to losing container connectivity.
+type Hook interface {
+	Notify(r Record)
+}
+
+var hook Hook
+
+// Register installs h as the hook notified by Notify. Registering a nil Hook (the
+// default) disables notification entirely.
+func Register(h Hook) {
+	hook = h
+}
+
+// Notify reports r to the registered Hook, if any. It never returns an error - see Hook.
+func Notify(r Record) {
+	if hook == nil {
+		return
+	}
+
+	hook.Notify(r)
+}
+
+// HTTPHook is a Hook that POSTs each Record as JSON to an operator-run HTTP service.
+type HTTPHook struct {
+	URL    string
+	client *http.Client
+}
+
+// NewHTTPHook returns a Hook that POSTs to url for every allocation and release.
+func NewHTTPHook(url string) *HTTPHook {
+	return &HTTPHook{
+		URL:    url,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Notify implements Hook.
+func (h *HTTPHook) Notify(r Record) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		log.Errorf("registrar: unable to marshal record for %s: %s", r.ContainerID, err)
+		return
+	}
+
+	resp, err := h.client.Post(h.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("registrar hook %s unreachable: %s", h.URL, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Errorf("registrar hook %s returned status %d for %s", h.URL, resp.StatusCode, fmt.Sprintf("%s/%s", r.ContainerID, r.Action))
+	}
+}
@@ -29,6 +29,41 @@ import (
 	"github.com/vmware/vic/pkg/uid"
 )
 
+// InvalidIPForScopeError indicates a requested static IP cannot be used on a scope - it's
+// outside the scope's subnet, or it's the network, gateway, or broadcast address.
+type InvalidIPForScopeError struct {
+	IP     net.IP
+	Subnet *net.IPNet
+	Reason string
+}
+
+func (e InvalidIPForScopeError) Error() string {
+	return fmt.Sprintf("IP address %s is not valid on network %s: %s", e.IP, e.Subnet, e.Reason)
+}
+
+// IPAddressInUseError indicates a requested static IP is already held by another endpoint on
+// the scope.
+type IPAddressInUseError struct {
+	IP                   net.IP
+	ConflictingContainer string
+}
+
+func (e IPAddressInUseError) Error() string {
+	if e.ConflictingContainer == "" {
+		return fmt.Sprintf("IP address %s is already in use", e.IP)
+	}
+
+	return fmt.Sprintf("IP address %s is already in use by container %s", e.IP, e.ConflictingContainer)
+}
+
+// IPAM policies governing how a scope hands out addresses from reserveEndpointIP. The zero
+// value, IPAMPolicySequential, matches the pre-existing "lowest available" behavior.
+const (
+	IPAMPolicySequential = "sequential"
+	IPAMPolicyRandom     = "random"
+	IPAMPolicySticky     = "sticky"
+)
+
 type Scope struct {
 	sync.RWMutex
 
@@ -46,9 +81,19 @@ type Scope struct {
 	network     object.NetworkReference
 	annotations map[string]string
 	internal    bool
+	ipam        string
+	// sticky holds the last IP handed out to a given container name, so a recreated
+	// container of the same name gets the same address back. Only populated when
+	// ipam == IPAMPolicySticky.
+	sticky map[string]net.IP
 }
 
 func newScope(id uid.UID, scopeType string, network object.NetworkReference, scopeData *ScopeData) *Scope {
+	ipam := scopeData.IPAM
+	if ipam == "" {
+		ipam = IPAMPolicySequential
+	}
+
 	return &Scope{
 		id:          id,
 		name:        scopeData.Name,
@@ -61,6 +106,8 @@ func newScope(id uid.UID, scopeType string, network object.NetworkReference, sco
 		containers:  make(map[uid.UID]*Container),
 		annotations: make(map[string]string),
 		internal:    scopeData.Internal,
+		ipam:        ipam,
+		sticky:      make(map[string]net.IP),
 	}
 }
 
@@ -142,11 +189,49 @@ func (s *Scope) pools() []*ip.Range {
 	return pools
 }
 
+// validateStaticIP checks that requested is usable as a static address on s - within the
+// scope's subnet, and not the network, gateway, or broadcast address - without reserving it.
+// The address can still lose a race for the actual reservation; this only rejects requests
+// that could never succeed.
+func (s *Scope) validateStaticIP(requested net.IP) error {
+	subnet := s.Subnet()
+
+	if !ip.IsRoutableIP(requested, subnet) {
+		return InvalidIPForScopeError{IP: requested, Subnet: subnet, Reason: "not within the network's subnet, or is the network/broadcast address"}
+	}
+
+	if requested.Equal(s.Gateway()) {
+		return InvalidIPForScopeError{IP: requested, Subnet: subnet, Reason: "is the network's gateway address"}
+	}
+
+	return nil
+}
+
+// conflictingContainer returns the name of the container currently holding requested on s, if
+// any. Callers must hold s's lock.
+func (s *Scope) conflictingContainer(requested net.IP) string {
+	for _, e := range s.endpoints {
+		if e.IP().Equal(requested) {
+			return e.Container().Name()
+		}
+	}
+
+	return ""
+}
+
 func (s *Scope) reserveEndpointIP(e *Endpoint) error {
 	if s.isDynamic() {
 		return nil
 	}
 
+	// a sticky scope hands the container back whatever address it held last time, as
+	// long as the request didn't already ask for a specific static IP
+	if s.ipam == IPAMPolicySticky && ip.IsUnspecifiedIP(e.ip) {
+		if last, ok := s.sticky[e.Container().Name()]; ok {
+			e.ip = last
+		}
+	}
+
 	// reserve an ip address
 	var err error
 	for _, p := range s.spaces {
@@ -156,7 +241,12 @@ func (s *Scope) reserveEndpointIP(e *Endpoint) error {
 			}
 		} else {
 			var eip net.IP
-			if eip, err = p.ReserveNextIP4(); err == nil {
+			if s.ipam == IPAMPolicyRandom {
+				eip, err = p.ReserveRandomIP4()
+			} else {
+				eip, err = p.ReserveNextIP4()
+			}
+			if err == nil {
 				e.ip = eip
 				return nil
 			}
@@ -164,6 +254,12 @@ func (s *Scope) reserveEndpointIP(e *Endpoint) error {
 		}
 	}
 
+	if !ip.IsUnspecifiedIP(e.ip) {
+		if holder := s.conflictingContainer(e.ip); holder != "" {
+			return IPAddressInUseError{IP: e.ip, ConflictingContainer: holder}
+		}
+	}
+
 	return err
 }
 
@@ -174,6 +270,9 @@ func (s *Scope) releaseEndpointIP(e *Endpoint) error {
 
 	for _, p := range s.spaces {
 		if err := p.ReleaseIP4(e.ip); err == nil {
+			if s.ipam == IPAMPolicySticky && !e.static {
+				s.sticky[e.Container().Name()] = e.ip
+			}
 			if !e.static {
 				e.ip = net.IPv4(0, 0, 0, 0)
 			}
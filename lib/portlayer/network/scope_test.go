@@ -19,10 +19,13 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vic/lib/constants"
 	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/pkg/ip"
 	"github.com/vmware/vic/pkg/trace"
 	"github.com/vmware/vic/pkg/uid"
 )
@@ -193,3 +196,113 @@ func TestScopeAddRemoveContainer(t *testing.T) {
 
 	}
 }
+
+func TestScopeStickyIPAM(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("172.16.0.0/24")
+	s := newScope(uid.New(), constants.BridgeScopeType, nil, &ScopeData{
+		Name:   "sticky",
+		Subnet: subnet,
+		IPAM:   IPAMPolicySticky,
+	})
+	s.spaces = []*AddressSpace{NewAddressSpaceFromNetwork(subnet)}
+
+	con := &Container{id: uid.New(), name: "foo"}
+	e := newEndpoint(con, s, nil, nil)
+	if err := s.AddContainer(con, e); err != nil {
+		t.Fatalf("s.AddContainer() => %s, want nil", err)
+	}
+	first := e.IP()
+
+	if err := s.RemoveContainer(con); err != nil {
+		t.Fatalf("s.RemoveContainer() => %s, want nil", err)
+	}
+
+	// a recreated container with the same name should get the same IP back
+	con2 := &Container{id: uid.New(), name: "foo"}
+	e2 := newEndpoint(con2, s, nil, nil)
+	if err := s.AddContainer(con2, e2); err != nil {
+		t.Fatalf("s.AddContainer() => %s, want nil", err)
+	}
+
+	if !e2.IP().Equal(first) {
+		t.Errorf("s.AddContainer() for recreated container => IP %s, want sticky IP %s", e2.IP(), first)
+	}
+}
+
+func TestScopeValidateStaticIP(t *testing.T) {
+	ctx, err := NewContext(testConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewContext() => (nil, %s), want (ctx, nil)", err)
+	}
+
+	s := ctx.defaultScope
+
+	var tests = []struct {
+		ip  net.IP
+		err bool
+	}{
+		{net.IPv4(172, 16, 0, 50), false},
+		{s.Gateway(), true},
+		{ip.AllZerosAddr(s.Subnet()), true},
+		{ip.AllOnesAddr(s.Subnet()), true},
+		{net.IPv4(10, 255, 255, 5), true}, // outside the scope's subnet entirely
+	}
+
+	for _, te := range tests {
+		err := s.validateStaticIP(te.ip)
+		if te.err && err == nil {
+			t.Errorf("s.validateStaticIP(%s) => nil, want error", te.ip)
+		}
+		if !te.err && err != nil {
+			t.Errorf("s.validateStaticIP(%s) => %s, want nil", te.ip, err)
+		}
+	}
+}
+
+// TestScopeAddContainerConcurrentSameIP ensures that when two containers race to claim the
+// same static IP on a scope, exactly one wins and the loser gets a typed error naming the
+// winner rather than a generic pool-exhaustion message.
+func TestScopeAddContainerConcurrentSameIP(t *testing.T) {
+	ctx, err := NewContext(testConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewContext() => (nil, %s), want (ctx, nil)", err)
+	}
+
+	s := ctx.defaultScope
+	contestedIP := makeIP(172, 16, 0, 60)
+
+	const racers = 8
+	var wg sync.WaitGroup
+	results := make([]error, racers)
+
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			c := &Container{id: uid.New(), name: fmt.Sprintf("racer-%d", i)}
+			e := newEndpoint(c, s, contestedIP, nil)
+			results[i] = s.AddContainer(c, e)
+		}(i)
+	}
+	wg.Wait()
+
+	wins, conflicts := 0, 0
+	for _, err := range results {
+		switch err.(type) {
+		case nil:
+			wins++
+		case IPAddressInUseError:
+			conflicts++
+		default:
+			t.Errorf("s.AddContainer() => unexpected error type %T (%s)", err, err)
+		}
+	}
+
+	if wins != 1 {
+		t.Errorf("got %d winners for a contested IP, want exactly 1", wins)
+	}
+	if conflicts != racers-1 {
+		t.Errorf("got %d losers for a contested IP, want %d", conflicts, racers-1)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pods groups existing containers into an ordered start/stop unit for
+// sidecar-style deployments.
+//
+// A Kubernetes-style pod gives every member the same network namespace, so
+// containers can reach each other over localhost and share one IP. VIC containers
+// are each a full VM, not a process sharing a kernel namespace, so that sharing
+// isn't possible here - a Pod instead groups containers that are expected to share a
+// network scope (Create does not check that they do), and guarantees the anchor container (the first in the list,
+// analogous to a pod's infra container) is reachable before its sidecars start, and
+// that sidecars are stopped before the anchor on the way down. Actual traffic
+// between members goes over the shared scope's network, not localhost.
+package pods
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vic/lib/portlayer/exec"
+	"github.com/vmware/vic/pkg/trace"
+	"github.com/vmware/vic/pkg/uid"
+	"github.com/vmware/vic/pkg/vsphere/session"
+)
+
+// commitWaitTime bounds how long a member's start/stop is allowed to wait for the
+// backing VM operation, mirroring the equivalent constant in lib/portlayer/jobs.
+var commitWaitTime = int32(60)
+
+// Pod is an ordered group of containers with a shared lifecycle. Members[0] is the
+// anchor - the container the rest of the pod depends on being up first.
+type Pod struct {
+	ID      string
+	Name    string
+	Members []string
+}
+
+// Manager owns the set of known Pods and starts/stops their members via sess.
+type Manager struct {
+	session *session.Session
+
+	mu   sync.Mutex
+	pods map[string]*Pod
+}
+
+// NewManager returns a Manager that operates on containers via sess.
+func NewManager(sess *session.Session) *Manager {
+	return &Manager{
+		session: sess,
+		pods:    make(map[string]*Pod),
+	}
+}
+
+// Create groups the given, already-created containers into a new Pod. It does not
+// start any of them.
+func (m *Manager) Create(name string, members []string) (*Pod, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("a pod needs at least one member container")
+	}
+
+	for _, id := range members {
+		if exec.Containers.Container(id) == nil {
+			return nil, fmt.Errorf("container %s not found", id)
+		}
+	}
+
+	p := &Pod{
+		ID:      uid.New().String(),
+		Name:    name,
+		Members: members,
+	}
+
+	m.mu.Lock()
+	m.pods[p.ID] = p
+	m.mu.Unlock()
+
+	return p, nil
+}
+
+// List returns every known Pod.
+func (m *Manager) List() []*Pod {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pods := make([]*Pod, 0, len(m.pods))
+	for _, p := range m.pods {
+		pods = append(pods, p)
+	}
+	return pods
+}
+
+// Get returns the Pod with the given id, if any.
+func (m *Manager) Get(id string) (*Pod, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.pods[id]
+	return p, ok
+}
+
+// Delete forgets a Pod. It does not touch its member containers.
+func (m *Manager) Delete(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pods[id]; !ok {
+		return false
+	}
+	delete(m.pods, id)
+	return true
+}
+
+// Start starts the pod's anchor first and waits for it to be running before
+// starting each sidecar in order, so a sidecar's startup command can assume the
+// anchor is already reachable on the shared scope.
+func (m *Manager) Start(op trace.Operation, id string) error {
+	p, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("pod %s not found", id)
+	}
+
+	for _, memberID := range p.Members {
+		if err := m.startMember(op, memberID); err != nil {
+			return fmt.Errorf("pod %s: starting %s: %s", id, memberID, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop stops the pod's members in reverse order, so sidecars shut down before the
+// anchor they depend on.
+func (m *Manager) Stop(op trace.Operation, id string) error {
+	p, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("pod %s not found", id)
+	}
+
+	for i := len(p.Members) - 1; i >= 0; i-- {
+		memberID := p.Members[i]
+		if err := m.stopMember(op, memberID); err != nil {
+			return fmt.Errorf("pod %s: stopping %s: %s", id, memberID, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) startMember(op trace.Operation, id string) error {
+	c := exec.Containers.Container(id)
+	if c == nil {
+		return fmt.Errorf("container %s not found", id)
+	}
+
+	h, err := c.NewHandle(context.Background())
+	if err != nil {
+		return err
+	}
+
+	h.SetTargetState(exec.StateRunning)
+	if err := h.Commit(op, m.session, &commitWaitTime); err != nil {
+		return err
+	}
+
+	<-c.WaitForState(exec.StateRunning)
+	return nil
+}
+
+func (m *Manager) stopMember(op trace.Operation, id string) error {
+	c := exec.Containers.Container(id)
+	if c == nil {
+		return fmt.Errorf("container %s not found", id)
+	}
+
+	h, err := c.NewHandle(context.Background())
+	if err != nil {
+		return err
+	}
+
+	h.SetTargetState(exec.StateStopped)
+	return h.Commit(op, m.session, &commitWaitTime)
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"path"
 	"sync"
 	"time"
 
@@ -554,6 +555,33 @@ func (c *NameLookupCache) DeleteBranch(op trace.Operation, img *image.Image, kee
 	return deletedImages, nil
 }
 
+// Prefetch walks an image's ancestry chain, from ID up through its parents, and loads each
+// layer into the in-memory store cache ahead of time. GetImage already does this lazily on a
+// container's first reference to a given layer; Prefetch lets a caller pay that datastore
+// round-trip cost ahead of a planned deployment wave instead of stalling the first container
+// start of the rollout on it. It returns the IDs of the layers it warmed, ordered from ID up
+// to (and including) the root.
+func (c *NameLookupCache) Prefetch(op trace.Operation, store *url.URL, ID string) ([]string, error) {
+	warmed := make([]string, 0)
+
+	id := ID
+	for {
+		img, err := c.GetImage(op, store, id)
+		if err != nil {
+			return warmed, err
+		}
+
+		warmed = append(warmed, img.ID)
+
+		if img.Parent() == img.Self() {
+			// reached the root
+			return warmed, nil
+		}
+
+		id = path.Base(img.ParentLink.Path)
+	}
+}
+
 func (c *NameLookupCache) ImageStorageUsage() int64 {
 	c.storeCacheLock.Lock()
 	defer c.storeCacheLock.Unlock()
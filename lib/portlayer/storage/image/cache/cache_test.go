@@ -548,3 +548,47 @@ func TestPopulateCacheInExpectedOrder(t *testing.T) {
 		assert.NotNil(t, v)
 	}
 }
+
+func TestPrefetch(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+
+	storeURL, _ := util.ImageStoreNameToURL("testStore")
+	storageURLStr := storeURL.String()
+
+	url1, _ := url.Parse(storageURLStr + "/id1")
+	url2, _ := url.Parse(storageURLStr + "/id2")
+	url3, _ := url.Parse(storageURLStr + "/id3")
+	scratchURL, _ := url.Parse(storageURLStr + "/" + constants.ScratchLayerID)
+
+	img1 := &image.Image{ID: "id1", SelfLink: url1, ParentLink: scratchURL, Store: storeURL}
+	img2 := &image.Image{ID: "id2", SelfLink: url2, ParentLink: url1, Store: storeURL}
+	img3 := &image.Image{ID: "id3", SelfLink: url3, ParentLink: url2, Store: storeURL}
+	scratchImg := &image.Image{
+		ID:         constants.ScratchLayerID,
+		SelfLink:   scratchURL,
+		ParentLink: scratchURL,
+		Store:      storeURL,
+	}
+
+	imageMap := map[string]*image.Image{
+		img1.ID:       img1,
+		img2.ID:       img2,
+		img3.ID:       img3,
+		scratchImg.ID: scratchImg,
+	}
+	storeImages := map[url.URL]map[string]*image.Image{
+		*storeURL: imageMap,
+	}
+	st := mock.NewMockDataStore(storeImages)
+
+	imageCache := NewLookupCache(st)
+	sURL, err := imageCache.GetImageStore(op, "testStore")
+	require.NoError(t, err, "Received error while getting image store from cache")
+
+	warmed, err := imageCache.Prefetch(op, sURL, "id3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id3", "id2", "id1", constants.ScratchLayerID}, warmed)
+
+	_, err = imageCache.Prefetch(op, sURL, "does-not-exist")
+	assert.Error(t, err)
+}
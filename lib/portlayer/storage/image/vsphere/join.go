@@ -20,10 +20,11 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/vic/lib/portlayer/exec"
 	"github.com/vmware/vic/lib/portlayer/storage/image"
+	"github.com/vmware/vic/lib/spec"
 	"github.com/vmware/vic/pkg/trace"
 )
 
-func Join(op trace.Operation, handle *exec.Handle, id, imgID, repoName string, img *image.Image) (*exec.Handle, error) {
+func Join(op trace.Operation, handle *exec.Handle, id, imgID, repoName string, img *image.Image, iopsLimit int64) (*exec.Handle, error) {
 	defer trace.End(trace.Begin(img.ID, op))
 
 	// set the rw layer name
@@ -53,6 +54,8 @@ func Join(op trace.Operation, handle *exec.Handle, id, imgID, repoName string, i
 		},
 	}
 
+	spec.SetIOPSLimit(disk, iopsLimit)
+
 	handle.Spec.AddVirtualDisk(disk)
 
 	// record the repo name and image ID that resolved to the layer in question
@@ -0,0 +1,91 @@
+// Copyright 2016-2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"bytes"
+	"os"
+	"path"
+
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vic/pkg/trace"
+)
+
+const referrersDir = "referrers"
+
+// referrersDirPath returns the path to the directory tracking which VCHs are using an image.
+// When several VCHs are configured to share the same image store (see the image store URL in
+// their config), each records itself here rather than the image being copied per-VCH, so a
+// layer is written to the store once and shared.
+func (v *ImageStore) referrersDirPath(storeName, ID string) string {
+	return path.Join(v.imageDirPath(storeName, ID), referrersDir)
+}
+
+func (v *ImageStore) referrerFilePath(storeName, ID, vch string) string {
+	return path.Join(v.referrersDirPath(storeName, ID), vch)
+}
+
+// vchIdentity identifies the calling VCH for the purposes of reference tracking. The portlayer
+// runs inside the VCH appliance VM, so its own hostname is already a stable, unique identity -
+// the same value used to stamp the OwnerVCH custom field on container VMs.
+func vchIdentity() string {
+	if h, err := os.Hostname(); err == nil && h != "" {
+		return h
+	}
+
+	return "unknown"
+}
+
+// addReferrer records that this VCH is using image ID, so a concurrent GC on another VCH
+// sharing the same image store won't delete it out from under us.
+func (v *ImageStore) addReferrer(op trace.Operation, storeName, ID string) error {
+	if _, err := v.Mkdir(op, true, v.referrersDirPath(storeName, ID)); err != nil {
+		return err
+	}
+
+	return v.Upload(op, bytes.NewReader(nil), v.referrerFilePath(storeName, ID, vchIdentity()))
+}
+
+// removeReferrer drops this VCH's claim on image ID. It is not an error if we were never
+// recorded as a referrer, e.g. images written before this feature existed.
+func (v *ImageStore) removeReferrer(op trace.Operation, storeName, ID string) error {
+	err := v.Rm(op, v.referrerFilePath(storeName, ID, vchIdentity()))
+	if err != nil && types.IsFileNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// otherReferrers lists the VCHs other than the caller that are still using image ID.
+func (v *ImageStore) otherReferrers(op trace.Operation, storeName, ID string) ([]string, error) {
+	res, err := v.Ls(op, v.referrersDirPath(storeName, ID))
+	if err != nil {
+		if types.IsFileNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	self := vchIdentity()
+	var others []string
+	for _, f := range res.File {
+		if f.GetFileInfo().Path != self {
+			others = append(others, f.GetFileInfo().Path)
+		}
+	}
+
+	return others, nil
+}
@@ -259,12 +259,28 @@ func (v *ImageStore) WriteImage(op trace.Operation, parent *image.Image, ID stri
 			return nil, fmt.Errorf("parent ID is empty")
 		}
 
+		// On a store shared by several VCHs, another VCH may have already pulled this exact
+		// layer - reuse it rather than writing it a second time.
+		if _, statErr := v.Stat(op, v.manifestPath(storeName, ID)); statErr == nil {
+			op.Infof("ImageStore: reusing image %s already present on shared store %s", ID, storeName)
+
+			if err := v.addReferrer(op, storeName, ID); err != nil {
+				op.Warnf("ImageStore: unable to record reference to shared image %s: %s", ID, err)
+			}
+
+			return v.GetImage(op, parent.Store, ID)
+		}
+
 		dsk, err = v.writeImage(op, storeName, parent.ID, ID, meta, sum, r)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if err := v.addReferrer(op, storeName, ID); err != nil {
+		op.Warnf("ImageStore: unable to record reference to image %s: %s", ID, err)
+	}
+
 	newImage := &image.Image{
 		ID:            ID,
 		SelfLink:      imageURL,
@@ -613,6 +629,18 @@ func (v *ImageStore) DeleteImage(op trace.Operation, image *image.Image) (*image
 		return nil, err
 	}
 
+	if err := v.removeReferrer(op, storeName, image.ID); err != nil {
+		op.Warnf("ImageStore: unable to drop reference to image %s: %s", image.ID, err)
+	}
+
+	others, err := v.otherReferrers(op, storeName, image.ID)
+	if err != nil {
+		op.Warnf("ImageStore: unable to check other referrers of image %s, deleting anyway: %s", image.ID, err)
+	} else if len(others) > 0 {
+		op.Infof("ImageStore: skipping deletion of image %s, still referenced by %v", image.ID, others)
+		return image, nil
+	}
+
 	return image, v.deleteImage(op, storeName, image.ID)
 }
 
@@ -120,6 +120,32 @@ func (v *VolumeLookupCache) VolumeStoresList(op trace.Operation) ([]string, erro
 	return stores, nil
 }
 
+// VolumeStoreOnDatastore returns the service url of a configured volume store backed by the
+// given vSphere datastore, if one exists. Volume stores that aren't backed by a single named
+// datastore (e.g. NFS) are never matched. Used to place a new volume alongside a specific
+// container's VM files when the caller doesn't care which store name is used, only which
+// datastore it lands on.
+func (v *VolumeLookupCache) VolumeStoreOnDatastore(op trace.Operation, ds string) (*url.URL, bool) {
+	v.vlcLock.RLock()
+	defer v.vlcLock.RUnlock()
+
+	for u, vs := range v.volumeStores {
+		locator, ok := vs.(volume.DatastoreLocator)
+		if !ok || locator.Datastore() != ds {
+			continue
+		}
+
+		storeURL, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+
+		return storeURL, true
+	}
+
+	return nil, false
+}
+
 func (v *VolumeLookupCache) VolumeCreate(op trace.Operation, ID string, store *url.URL, capacityKB uint64, info map[string][]byte) (*volume.Volume, error) {
 	v.vlcLock.Lock()
 	defer v.vlcLock.Unlock()
@@ -39,6 +39,14 @@ import (
 type MockVolumeStore struct {
 	// id -> volume
 	db map[string]*volume.Volume
+
+	// datastore backing this store, for DatastoreLocator - empty unless a test sets it
+	datastore string
+}
+
+// Datastore satisfies volume.DatastoreLocator.
+func (m *MockVolumeStore) Datastore() string {
+	return m.datastore
 }
 
 func NewMockVolumeStore() *MockVolumeStore {
@@ -262,6 +270,30 @@ func TestAddVolumesToCache(t *testing.T) {
 	}
 }
 
+func TestVolumeStoreOnDatastore(t *testing.T) {
+	op := trace.NewOperation(context.Background(), "test")
+	v := NewVolumeLookupCache(op)
+
+	dsStore := NewMockVolumeStore()
+	dsStore.datastore = "datastore1"
+	dsStoreURL, err := util.VolumeStoreNameToURL("dsStore")
+	assert.NoError(t, err)
+	v.volumeStores[dsStoreURL.String()] = dsStore
+
+	// a store on a different (here, unset) datastore is never matched
+	otherStore := NewMockVolumeStore()
+	otherStoreURL, err := util.VolumeStoreNameToURL("otherStore")
+	assert.NoError(t, err)
+	v.volumeStores[otherStoreURL.String()] = otherStore
+
+	found, ok := v.VolumeStoreOnDatastore(op, "datastore1")
+	assert.True(t, ok)
+	assert.Equal(t, dsStoreURL.String(), found.String())
+
+	_, ok = v.VolumeStoreOnDatastore(op, "no-such-datastore")
+	assert.False(t, ok)
+}
+
 // Create 2 store caches but use the same backing datastore.  Create images
 // with the first cache, then get the image with the second.  This simulates
 // restart since the second cache is empty and has to go to the backing store.
@@ -36,6 +36,13 @@ type Disk interface {
 	DiskPath() url.URL
 }
 
+// DatastoreLocator is implemented by volume stores backed by a single vSphere datastore. It lets
+// callers discover which datastore a store's volumes live on, e.g. to place a new volume's VMDK
+// on the same datastore as a particular container's VM files.
+type DatastoreLocator interface {
+	Datastore() string
+}
+
 // VolumeStorer is an interface to create, remove, enumerate, and get Volumes.
 type VolumeStorer interface {
 	// Creates a volume on the given volume store, of the given size, with the given metadata.
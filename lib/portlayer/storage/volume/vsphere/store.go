@@ -82,6 +82,12 @@ func NewVolumeStore(op trace.Operation, storeName string, s *session.Session, ds
 	return v, nil
 }
 
+// Datastore returns the name of the datastore this VolumeStore creates volumes on, satisfying
+// volume.DatastoreLocator.
+func (v *VolumeStore) Datastore() string {
+	return v.Helper.RootURL.Datastore
+}
+
 // Returns the path to the vol relative to the given store.  The dir structure
 // for a vol in the datastore is `<configured datastore path>/volumes/<vol ID>/<vol ID>.vmkd`.
 // Everything up to "volumes" is taken care of by the datastore wrapper.
@@ -0,0 +1,103 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package templates stores named container create configs that a "create from
+// template" request can build on, rather than resending the full config (image,
+// command, resources, networks) for every repeated deployment.
+package templates
+
+import (
+	"sync"
+
+	"github.com/vmware/vic/lib/apiservers/portlayer/models"
+)
+
+var (
+	mu    sync.RWMutex
+	store = make(map[string]*models.ContainerCreateConfig)
+)
+
+// Save stores cc under name, replacing any existing template of that name.
+func Save(name string, cc *models.ContainerCreateConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	store[name] = cc
+}
+
+// Get returns the template stored under name, if any.
+func Get(name string) (*models.ContainerCreateConfig, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	cc, ok := store[name]
+	return cc, ok
+}
+
+// List returns the name of every stored template.
+func List() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(store))
+	for name := range store {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Delete removes the template stored under name, reporting whether it existed.
+func Delete(name string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := store[name]; !ok {
+		return false
+	}
+	delete(store, name)
+	return true
+}
+
+// Merge returns a copy of base with the non-zero Name, NumCpus, MemoryMB, Hostname,
+// Domainname and Annotations of overrides applied on top of it, so a "create from
+// template" request only has to send what it's changing rather than the whole config.
+// Every other field of overrides is ignored; the merged config keeps base's value.
+func Merge(base *models.ContainerCreateConfig, overrides *models.ContainerCreateConfig) *models.ContainerCreateConfig {
+	merged := *base
+
+	if overrides == nil {
+		return &merged
+	}
+
+	if overrides.Name != "" {
+		merged.Name = overrides.Name
+	}
+	if overrides.NumCpus != 0 {
+		merged.NumCpus = overrides.NumCpus
+	}
+	if overrides.MemoryMB != 0 {
+		merged.MemoryMB = overrides.MemoryMB
+	}
+	if overrides.Hostname != "" {
+		merged.Hostname = overrides.Hostname
+	}
+	if overrides.Domainname != "" {
+		merged.Domainname = overrides.Domainname
+	}
+	if len(overrides.Annotations) > 0 {
+		merged.Annotations = overrides.Annotations
+	}
+
+	return &merged
+}
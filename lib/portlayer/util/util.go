@@ -77,7 +77,8 @@ func prepTemplate(op trace.Operation, template string) (string, int) {
 	}
 
 	withoutName := strings.Replace(template, config.NameToken.String(), "", 1)
-	withoutEither := strings.Replace(withoutName, config.IDToken.String(), "", 1)
+	withoutID := strings.Replace(withoutName, config.IDToken.String(), "", 1)
+	withoutEither := strings.Replace(withoutID, config.VCHToken.String(), "", 1)
 	availableLen := constants.MaxVMNameLength - len(withoutEither)
 
 	// TODO: initialization time check that template actually contains a token or we have a static string
@@ -145,6 +146,12 @@ func DisplayName(op trace.Operation, cfg *spec.VirtualMachineConfigSpecConfig, n
 	name, availableLen = replaceToken(name, config.IDToken, shortID, availableLen)
 	name, availableLen = replaceToken(name, config.NameToken, prettyName, availableLen)
 
+	vchName, err := os.Hostname()
+	if err != nil {
+		op.Warnf("Unable to determine VCH hostname for {vch} naming token: %s", err)
+	}
+	name, availableLen = replaceToken(name, config.VCHToken, vchName, availableLen)
+
 	op.Infof("Applied naming convention: %s resulting %s", namingConvention, name)
 	return name
 }
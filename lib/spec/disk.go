@@ -60,3 +60,16 @@ func (s *VirtualMachineConfigSpec) RemoveVirtualDisk(device *types.VirtualDisk)
 
 	return s.RemoveAndDestroyVirtualDevice(device)
 }
+
+// SetIOPSLimit caps the aggregate read+write IOPS available to disk via StorageIOAllocation.
+// A limit of 0 or less is treated as unlimited and clears any existing cap.
+func SetIOPSLimit(disk *types.VirtualDisk, limit int64) {
+	if limit <= 0 {
+		disk.StorageIOAllocation = nil
+		return
+	}
+
+	disk.StorageIOAllocation = &types.StorageIOAllocationInfo{
+		Limit: types.NewInt64(limit),
+	}
+}
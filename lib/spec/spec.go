@@ -42,9 +42,17 @@ type VirtualMachineConfigSpecConfig struct {
 	// Memory - in MB
 	MemoryMB int64
 
+	// CPUAffinity, if non-empty, pins the VM's virtual CPUs to these physical CPU IDs
+	// on its host (see types.VirtualMachineAffinityInfo).
+	CPUAffinity []int32
+
 	// VMFork enabled
 	VMForkEnabled bool
 
+	// SyncTimeWithHost controls whether VMware Tools periodically syncs the guest clock to
+	// the ESXi host clock. Off by default so it doesn't fight with a container's own NTP setup.
+	SyncTimeWithHost bool
+
 	// datastore path of the media file we boot from
 	BootMediaPath string
 
@@ -88,6 +96,12 @@ func NewVirtualMachineConfigSpec(ctx context.Context, session *session.Session,
 		MemoryMB:            config.MemoryMB,
 		MemoryHotAddEnabled: &config.VMForkEnabled,
 
+		Tools: &types.ToolsConfigInfo{
+			SyncTimeWithHost: &config.SyncTimeWithHost,
+		},
+
+		CpuAffinity: cpuAffinitySpec(config.CPUAffinity),
+
 		ExtraConfig: []types.BaseOptionValue{
 			// lets us see the UUID for the containerfs disk (hidden from daemon)
 			&types.OptionValue{Key: "disk.EnableUUID", Value: "true"},
@@ -131,6 +145,16 @@ func NewVirtualMachineConfigSpec(ctx context.Context, session *session.Session,
 	return vmcs, nil
 }
 
+// cpuAffinitySpec returns the CpuAffinity to set on the VM's config spec, or nil if no
+// affinity was requested.
+func cpuAffinitySpec(ids []int32) *types.VirtualMachineAffinityInfo {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return &types.VirtualMachineAffinityInfo{AffinitySet: ids}
+}
+
 // AddVirtualDevice appends an Add operation to the DeviceChange list
 func (s *VirtualMachineConfigSpec) AddVirtualDevice(device types.BaseVirtualDevice) *VirtualMachineConfigSpec {
 	s.DeviceChange = append(s.DeviceChange,
@@ -72,6 +72,14 @@ type ExecutorConfig struct {
 	// Hostname and domainname provided by personality
 	Hostname   string `vic:"0.1" scope:"read-only" key:"hostname"`
 	Domainname string `vic:"0.1" scope:"read-only" key:"domainname"`
+
+	// ReapOrphans controls whether the tether, acting as pid 1, marks itself as a child
+	// subreaper so that orphaned grandchildren are reparented to it for reaping rather than
+	// to the guest's actual init.
+	ReapOrphans bool `vic:"0.1" scope:"read-only" key:"reaporphans"`
+
+	// Diagnostics holds basic diagnostics data
+	Diagnostics executor.Diagnostics `vic:"0.1" scope:"read-only" key:"diagnostics"`
 }
 
 // SessionConfig defines the content of a session - this maps to the root of a process tree
@@ -113,6 +121,12 @@ type SessionConfig struct {
 	// Restart controls whether a process gets relaunched if it exists
 	Restart bool `vic:"0.1" scope:"read-only" key:"restart"`
 
+	// RestartPolicy refines how Restart is applied - whether it's unconditional or
+	// contingent on the session having exited with a failure, and how many times it may be
+	// retried. An empty RestartPolicy.Name preserves the historical behavior of restarting
+	// unconditionally while Restart is true.
+	RestartPolicy executor.RestartConfig `vic:"0.1" scope:"read-only" key:"restartpolicy"`
+
 	// StopSignal is the signal name or number used to stop a container
 	StopSignal string `vic:"0.1" scope:"read-only" key:"stopSignal"`
 
@@ -146,6 +160,25 @@ type SessionConfig struct {
 	extraconfigKey string
 }
 
+// ShouldRestart reports whether the tether should relaunch this session's process, given its
+// restart policy, its last exit status and how many times it has already been restarted.
+func (s *SessionConfig) ShouldRestart() bool {
+	if !s.Restart {
+		return false
+	}
+
+	if s.RestartPolicy.Name == "on-failure" {
+		if s.ExitStatus == 0 {
+			return false
+		}
+		if s.RestartPolicy.MaximumRetryCount > 0 && s.Diagnostics.ResurrectionCount >= s.RestartPolicy.MaximumRetryCount {
+			return false
+		}
+	}
+
+	return true
+}
+
 type NetworkEndpoint struct {
 	// Common.Name - the nic alias requested (only one name and one alias possible in linux)
 	// Common.ID - pci slot of the vnic allowing for interface identifcation in-guest
@@ -80,6 +80,10 @@ type Tether interface {
 	Wait(ctx context.Context) error
 	Reload()
 	Register(name string, ext Extension)
+	// AddSession injects a new exec-style session directly into the live Execs table and
+	// triggers the launch of its process, without requiring a config reload sourced from
+	// guestinfo. Returns an error if a session with the same ID already exists.
+	AddSession(session *SessionConfig) error
 }
 
 // Extension is a very simple extension interface for supporting code that need to be
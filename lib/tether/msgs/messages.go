@@ -150,6 +150,38 @@ func (s *SignalMsg) FromString(name string) error {
 // CloseStdinMsg
 const CloseStdinReq = "close-stdin"
 
+// SessionCreateMsg asks the tether to start an additional session directly over the attach
+// control channel, without requiring a VM reconfigure/guestinfo round-trip first. It's used for
+// exec-style sessions, which are already excluded from ExtraConfig persistence.
+const SessionCreateReq = "session-create"
+
+type SessionCreateMsg struct {
+	ID   string
+	Path string
+	Args []string
+	Env  []string
+	Dir  string
+
+	Tty       bool
+	Attach    bool
+	OpenStdin bool
+
+	User  string
+	Group string
+}
+
+func (s *SessionCreateMsg) RequestType() string {
+	return SessionCreateReq
+}
+
+func (s *SessionCreateMsg) Marshal() []byte {
+	return ssh.Marshal(*s)
+}
+
+func (s *SessionCreateMsg) Unmarshal(payload []byte) error {
+	return ssh.Unmarshal(payload, s)
+}
+
 // ContainersMsg
 const ContainersReq = "container-ids"
 
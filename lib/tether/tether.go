@@ -484,7 +484,7 @@ func (t *tether) processSessions() error {
 				}
 
 				priorLaunch := proc != nil || session.Started != ""
-				if priorLaunch && !session.Restart {
+				if priorLaunch && !session.ShouldRestart() {
 					log.Debugf("Skipping non-restartable exited or failed session: %s", id)
 					return
 				}
@@ -577,6 +577,16 @@ func (t *tether) Start() error {
 
 		t.setLogLevel()
 
+		// re-apply in case ReapOrphans changed since the last reload - the subreaper
+		// attribute can be toggled at any time, not just at process start
+		if err := setChildSubreaper(t.config.ReapOrphans); err != nil {
+			log.Errorf("Unable to update child subreaper state: %s", err)
+		}
+
+		if err := setCoreDumpLimits(t.config.Diagnostics.EnableCoreDumps); err != nil {
+			log.Errorf("Unable to update core dump configuration: %s", err)
+		}
+
 		// TODO: this ensures that we run vm related setup code once
 		// This is temporary as none of those functions are idempotent at this point
 		// https://github.com/vmware/vic/issues/5833
@@ -688,6 +698,29 @@ func (t *tether) Reload() {
 	}
 }
 
+// AddSession injects a new exec-style session directly into the live Execs table, then
+// triggers a reload so the normal launch path picks it up immediately. This lets a control
+// channel that's already connected (e.g. attach) hand the tether new work without going
+// through a VM reconfigure/guestinfo round-trip.
+func (t *tether) AddSession(session *SessionConfig) error {
+	if session.ID == "" {
+		return errors.New("session requires an ID")
+	}
+
+	t.config.Lock()
+	if _, ok := t.config.Execs[session.ID]; ok {
+		t.config.Unlock()
+		return fmt.Errorf("session %s already exists", session.ID)
+	}
+
+	session.Active = true
+	t.config.Execs[session.ID] = session
+	t.config.Unlock()
+
+	t.Reload()
+	return nil
+}
+
 func (t *tether) Register(name string, extension Extension) {
 	log.Infof("Registering tether extension " + name)
 
@@ -787,7 +820,12 @@ func (t *tether) loggingLocked(session *SessionConfig) error {
 		cfg := session.Diagnostics.SysLogConfig
 		var w syslog.Writer
 		if t.writer == nil {
-			t.writer, err = syslog.Dial(cfg.Network, cfg.RAddr, syslog.Info|syslog.Daemon, fmt.Sprintf("%s", t.config.ID[:shortLen]))
+			format := syslog.RFC3164
+			if cfg.Format == "rfc5424" {
+				format = syslog.RFC5424
+			}
+
+			t.writer, err = syslog.DialFormat(cfg.Network, cfg.RAddr, syslog.Info|syslog.Daemon, fmt.Sprintf("%s", t.config.ID[:shortLen]), format)
 			if err != nil {
 				log.Warnf("could not connect to syslog server: %s", err)
 			}
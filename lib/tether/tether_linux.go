@@ -17,6 +17,7 @@ package tether
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -61,6 +62,54 @@ func ReloadConfig() error {
 	return nil
 }
 
+// setChildSubreaper enables or disables the calling process' PR_SET_CHILD_SUBREAPER attribute.
+// It can be called repeatedly - the kernel allows the attribute to be toggled at any time, not
+// just at process start - which lets ReapOrphans be re-applied on every tether config reload.
+func setChildSubreaper(enabled bool) error {
+	var arg uintptr
+	if enabled {
+		arg = 1
+	}
+
+	if _, _, err := syscall.RawSyscall(syscall.SYS_PRCTL, SetChildSubreaper, arg, 0); err != 0 {
+		return err
+	}
+
+	return nil
+}
+
+// CoreDumpDir is where session process core dumps land when Diagnostics.EnableCoreDumps is
+// set. It's under the container's own filesystem rather than a separate mount, so the existing
+// container export/stat API can list and retrieve dumps without any new portlayer plumbing.
+const CoreDumpDir = "/var/vic/coredumps"
+
+// setCoreDumpLimits enables or disables core dumps for session processes launched from this
+// point on. It can be called repeatedly, e.g. on every tether config reload, mirroring
+// setChildSubreaper. When enabling, it also points the kernel at CoreDumpDir so dumps end up
+// somewhere already reachable via the container filesystem rather than wherever the crashing
+// process happened to have as its working directory.
+func setCoreDumpLimits(enabled bool) error {
+	var cur, max uint64
+	if enabled {
+		// RLIM_INFINITY is -1 on linux, which overflows as an untyped constant conversion to
+		// uint64 - ^uint64(0) is the same all-ones bit pattern syscall.Rlimit actually wants.
+		cur, max = ^uint64(0), ^uint64(0)
+
+		if err := os.MkdirAll(CoreDumpDir, 0700); err != nil {
+			return fmt.Errorf("unable to create core dump directory %s: %s", CoreDumpDir, err)
+		}
+
+		pattern := filepath.Join(CoreDumpDir, "%e.%p.core")
+		if err := ioutil.WriteFile("/proc/sys/kernel/core_pattern", []byte(pattern), 0644); err != nil {
+			return fmt.Errorf("unable to set core_pattern: %s", err)
+		}
+	}
+
+	// rlimits are inherited across fork/exec, so setting ours here applies to every session
+	// process launched afterward without needing per-Cmd plumbing.
+	return syscall.Setrlimit(syscall.RLIMIT_CORE, &syscall.Rlimit{Cur: cur, Max: max})
+}
+
 // childReaper is used to handle events from child processes, including child exit.
 // If running as pid=1 then this means it handles zombie process reaping for orphaned children
 // as well as direct child processes.
@@ -83,7 +132,7 @@ func (t *tether) childReaper() error {
 	*/
 
 	// TODO: update to check /proc/sys/kernel/osrelease and use this only on supported kernel versions
-	if _, _, err := syscall.RawSyscall(syscall.SYS_PRCTL, SetChildSubreaper, uintptr(1), 0); err != 0 {
+	if err := setChildSubreaper(true); err != nil {
 		// for now just log the error
 		log.Errorf("Unable to configure child subreaper - should not matter when run as pid1: %s", err)
 	}
@@ -0,0 +1,29 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package tether
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetCoreDumpLimitsDisabled only exercises the disabled path - the enabled path writes to
+// /proc/sys/kernel/core_pattern and requires privileges this test doesn't assume it has.
+func TestSetCoreDumpLimitsDisabled(t *testing.T) {
+	assert.NoError(t, setCoreDumpLimits(false))
+}
@@ -219,7 +219,7 @@ func (h *handler) vmotionComplete(w io.Writer, tc *telnet.Conn, b []byte) {
 		<-ch
 		log.Info("vMotion completed successfully")
 	} else {
-		log.Errorf("couldnt find previous information of vm after vmotion (vmuuid: %s)", cvm.vmUUID)
+		log.Errorf("couldn't find previous information for the migrated container VM's serial connection")
 	}
 
 }
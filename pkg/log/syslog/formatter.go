@@ -37,6 +37,12 @@ func (c *rfc3164Formatter) Format(p Priority, ts time.Time, hostname, tag, msg s
 	return fmt.Sprintf("<%d>%s %s %s[%d]: %s", p, ts.Format(time.RFC3339), hostname, tag, os.Getpid(), msg)
 }
 
+type rfc5424Formatter struct{}
+
+func (c *rfc5424Formatter) Format(p Priority, ts time.Time, hostname, tag, msg string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s", p, ts.Format(time.RFC3339), hostname, tag, os.Getpid(), msg)
+}
+
 type netDialer interface {
 	dial() (net.Conn, error)
 }
@@ -58,6 +64,8 @@ func newFormatter(network string, f Format) formatter {
 	switch f {
 	case RFC3164:
 		return &rfc3164Formatter{}
+	case RFC5424:
+		return &rfc5424Formatter{}
 	}
 
 	return nil
@@ -93,11 +93,18 @@ func New(priority Priority, tag string) (Writer, error) {
 // tag.
 // If network is empty, Dial will connect to the local syslog server.
 func Dial(network, raddr string, priority Priority, tag string) (Writer, error) {
+	return DialFormat(network, raddr, priority, tag, RFC3164)
+}
+
+// DialFormat is like Dial but lets the caller pick the message framing - e.g. RFC5424 for
+// collectors that require it, rather than always defaulting to RFC3164.
+func DialFormat(network, raddr string, priority Priority, tag string, format Format) (Writer, error) {
 	d := &defaultDialer{
 		network:  network,
 		raddr:    raddr,
 		tag:      tag,
 		priority: priority,
+		format:   format,
 	}
 
 	return d.dial()
@@ -106,6 +113,7 @@ func Dial(network, raddr string, priority Priority, tag string) (Writer, error)
 type defaultDialer struct {
 	network, raddr, tag string
 	priority            Priority
+	format              Format
 }
 
 func validPriority(priority Priority) bool {
@@ -121,7 +129,7 @@ func (d *defaultDialer) dial() (Writer, error) {
 	// #nosec: Errors unhandled.
 	hostname, _ := os.Hostname()
 
-	w := newWriter(d.priority, tag, hostname, newNetDialer(d.network, d.raddr), newFormatter(d.network, RFC3164))
+	w := newWriter(d.priority, tag, hostname, newNetDialer(d.network, d.raddr), newFormatter(d.network, d.format))
 
 	go w.run()
 
@@ -153,4 +161,5 @@ type Format int
 
 const (
 	RFC3164 Format = iota
+	RFC5424
 )
@@ -0,0 +1,185 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errors maps common vSphere SOAP faults to typed errors that
+// handlers and retry logic can switch on without reaching into govmomi's
+// soap/vim25 fault plumbing themselves. Add a case here rather than a new
+// one-off certifier function in the package that happens to hit the fault
+// first.
+package errors
+
+import (
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// InvalidPowerStateError reports that an operation could not be completed
+// because the VM was not in the required power state.
+type InvalidPowerStateError struct {
+	ExistingState  types.VirtualMachinePowerState
+	RequestedState types.VirtualMachinePowerState
+}
+
+func (e InvalidPowerStateError) Error() string {
+	return fmt.Sprintf("invalid power state: requested %s while VM is %s", e.RequestedState, e.ExistingState)
+}
+
+// NotFoundError reports that the object a vSphere operation was addressed to
+// no longer exists, such as a file or managed object removed out of band.
+type NotFoundError struct {
+	Msg string
+}
+
+func (e NotFoundError) Error() string {
+	if e.Msg == "" {
+		return "not found"
+	}
+	return e.Msg
+}
+
+// PermissionDeniedError reports that the operation was rejected because the
+// caller lacks a required privilege on a managed object.
+type PermissionDeniedError struct {
+	Object      types.ManagedObjectReference
+	PrivilegeID string
+}
+
+func (e PermissionDeniedError) Error() string {
+	return fmt.Sprintf("permission denied: missing privilege %q on %s", e.PrivilegeID, e.Object)
+}
+
+// InsufficientResourcesError reports that vSphere rejected the operation
+// because the cluster or host does not have the resources to satisfy it.
+type InsufficientResourcesError struct {
+	Msg string
+}
+
+func (e InsufficientResourcesError) Error() string {
+	if e.Msg == "" {
+		return "insufficient resources"
+	}
+	return e.Msg
+}
+
+// vimFault extracts the underlying vim fault from err, whether it arrived as
+// a raw types.HasFault (which a govmomi task.Error also satisfies) or a soap
+// fault - the shapes a govmomi call can hand back for the same fault.
+func vimFault(err error) types.AnyType {
+	if f, ok := err.(types.HasFault); ok {
+		return f.Fault()
+	}
+
+	if soap.IsSoapFault(err) {
+		return soap.ToSoapFault(err).VimFault()
+	}
+
+	if soap.IsVimFault(err) {
+		return soap.ToVimFault(err)
+	}
+
+	return nil
+}
+
+// Translate maps a govmomi/vSphere error to one of this package's typed
+// errors when it recognizes the underlying fault, and returns err unchanged
+// otherwise. Callers that only need a yes/no check should prefer the IsX
+// helpers below; Translate is for call sites that want to surface the typed
+// error itself, e.g. to callers or over the wire.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch f := vimFault(err).(type) {
+	case *types.InvalidPowerState:
+		return InvalidPowerStateError{ExistingState: f.ExistingState, RequestedState: f.RequestedState}
+	case types.InvalidPowerState:
+		return InvalidPowerStateError{ExistingState: f.ExistingState, RequestedState: f.RequestedState}
+	case *types.InvalidPowerStateFault:
+		return InvalidPowerStateError{ExistingState: f.ExistingState, RequestedState: f.RequestedState}
+	case types.InvalidPowerStateFault:
+		return InvalidPowerStateError{ExistingState: f.ExistingState, RequestedState: f.RequestedState}
+
+	case *types.ManagedObjectNotFound:
+		return NotFoundError{Msg: fmt.Sprintf("managed object %s not found", f.Obj)}
+	case types.ManagedObjectNotFound:
+		return NotFoundError{Msg: fmt.Sprintf("managed object %s not found", f.Obj)}
+	case *types.FileNotFound:
+		return NotFoundError{Msg: fmt.Sprintf("file %s not found", f.File)}
+	case types.FileNotFound:
+		return NotFoundError{Msg: fmt.Sprintf("file %s not found", f.File)}
+
+	case *types.NoPermission:
+		return PermissionDeniedError{Object: f.Object, PrivilegeID: f.PrivilegeId}
+	case types.NoPermission:
+		return PermissionDeniedError{Object: f.Object, PrivilegeID: f.PrivilegeId}
+
+	case *types.InsufficientResourcesFault:
+		return InsufficientResourcesError{Msg: "insufficient resources"}
+	case types.InsufficientResourcesFault:
+		return InsufficientResourcesError{Msg: "insufficient resources"}
+	}
+
+	if types.IsFileNotFound(err) {
+		return NotFoundError{Msg: "file not found"}
+	}
+
+	return err
+}
+
+// IsInvalidPowerStateError is an error certifier function for errors coming back from vsphere.
+// It checks for an InvalidPowerState fault, whether or not the error has already been
+// translated via Translate.
+func IsInvalidPowerStateError(err error) bool {
+	if _, ok := err.(InvalidPowerStateError); ok {
+		return true
+	}
+	_, ok := Translate(err).(InvalidPowerStateError)
+	return ok
+}
+
+// IsNotFoundError is an error certifier function for errors coming back from vsphere. It checks
+// for a ManagedObjectNotFound or FileNotFound fault, whether or not the error has already been
+// translated via Translate.
+func IsNotFoundError(err error) bool {
+	if _, ok := err.(NotFoundError); ok {
+		return true
+	}
+	_, ok := Translate(err).(NotFoundError)
+	return ok
+}
+
+// IsPermissionDeniedError is an error certifier function for errors coming back from vsphere. It
+// checks for a NoPermission fault, whether or not the error has already been translated via
+// Translate.
+func IsPermissionDeniedError(err error) bool {
+	if _, ok := err.(PermissionDeniedError); ok {
+		return true
+	}
+	_, ok := Translate(err).(PermissionDeniedError)
+	return ok
+}
+
+// IsInsufficientResourcesError is an error certifier function for errors coming back from
+// vsphere. It checks for an InsufficientResourcesFault, whether or not the error has already
+// been translated via Translate.
+func IsInsufficientResourcesError(err error) bool {
+	if _, ok := err.(InsufficientResourcesError); ok {
+		return true
+	}
+	_, ok := Translate(err).(InsufficientResourcesError)
+	return ok
+}
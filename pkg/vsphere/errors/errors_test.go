@@ -0,0 +1,95 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func faultError(fault types.BaseMethodFault) error {
+	return task.Error{
+		LocalizedMethodFault: &types.LocalizedMethodFault{
+			Fault:            fault,
+			LocalizedMessage: "test message",
+		},
+	}
+}
+
+func TestTranslateInvalidPowerState(t *testing.T) {
+	err := faultError(&types.InvalidPowerState{
+		RequestedState: types.VirtualMachinePowerStatePoweredOn,
+		ExistingState:  types.VirtualMachinePowerStatePoweredOff,
+	})
+
+	assert.True(t, IsInvalidPowerStateError(err))
+
+	translated, ok := Translate(err).(InvalidPowerStateError)
+	if !ok {
+		t.Fatalf("Translate() => %T, want InvalidPowerStateError", Translate(err))
+	}
+	assert.Equal(t, types.VirtualMachinePowerStatePoweredOff, translated.ExistingState)
+}
+
+func TestTranslateFileNotFound(t *testing.T) {
+	err := faultError(&types.FileNotFound{
+		FileFault: types.FileFault{File: "[datastore1] foo/bar.vmdk"},
+	})
+
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestTranslateManagedObjectNotFound(t *testing.T) {
+	err := faultError(&types.ManagedObjectNotFound{
+		Obj: types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"},
+	})
+
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestTranslateNoPermission(t *testing.T) {
+	err := faultError(&types.NoPermission{
+		Object:      types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"},
+		PrivilegeId: "System.View",
+	})
+
+	assert.True(t, IsPermissionDeniedError(err))
+
+	translated, ok := Translate(err).(PermissionDeniedError)
+	if !ok {
+		t.Fatalf("Translate() => %T, want PermissionDeniedError", Translate(err))
+	}
+	assert.Equal(t, "System.View", translated.PrivilegeID)
+}
+
+func TestTranslateInsufficientResources(t *testing.T) {
+	err := faultError(&types.InsufficientResourcesFault{})
+
+	assert.True(t, IsInsufficientResourcesError(err))
+}
+
+func TestTranslateUnrecognizedFaultPassesThrough(t *testing.T) {
+	err := faultError(&types.NotSupported{})
+
+	assert.False(t, IsInvalidPowerStateError(err))
+	assert.False(t, IsNotFoundError(err))
+	assert.False(t, IsPermissionDeniedError(err))
+	assert.False(t, IsInsufficientResourcesError(err))
+	assert.Equal(t, err, Translate(err))
+}
@@ -28,3 +28,8 @@ func GuestInfoSource() (DataSource, error) {
 func GuestInfoSourceWithPrefix(prefix string) (DataSource, error) {
 	return nil, errors.New("Not implemented on OSX")
 }
+
+// GuestInfoPrefixOverride looks up an administrator-configured extraConfig key namespace override.
+func GuestInfoPrefixOverride() (string, error) {
+	return "", errors.New("Not implemented on OSX")
+}
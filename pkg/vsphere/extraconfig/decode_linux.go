@@ -61,3 +61,22 @@ func GuestInfoSourceWithPrefix(prefix string) (DataSource, error) {
 
 	return new(SecretKey).Source(source), nil
 }
+
+// GuestInfoPrefixOverride looks up an administrator-configured extraConfig key namespace at
+// PrefixOverrideKey, bypassing the usual namespaced source - the prefix has to be discoverable
+// before it can be applied to the rest of decoding. Callers should pass the result to SetPrefix
+// before decoding anything else.
+func GuestInfoPrefixOverride() (string, error) {
+	if !vmcheck.IsVirtualCPU() {
+		return "", fmt.Errorf("not in a virtual world")
+	}
+
+	guestinfo := rpcvmx.NewConfig()
+
+	value, err := guestinfo.String(PrefixOverrideKey, "")
+	if value == "" {
+		return "", ErrKeyNotFound
+	}
+
+	return value, err
+}
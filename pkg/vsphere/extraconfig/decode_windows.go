@@ -30,3 +30,8 @@ func GuestInfoSource() (DataSource, error) {
 func GuestInfoSourceWithPrefix(prefix string) (DataSource, error) {
 	return nil, errors.New("Not implemented on Windows")
 }
+
+// GuestInfoPrefixOverride looks up an administrator-configured extraConfig key namespace override.
+func GuestInfoPrefixOverride() (string, error) {
+	return "", errors.New("Not implemented on Windows")
+}
@@ -79,12 +79,33 @@ var (
 
 	// suffix separator character
 	suffixSeparator = "@"
+
+	// PrefixOverrideKey is a fixed, unprefixed guestinfo key holding an administrator-configured
+	// override for DefaultPrefix (see VirtualContainerHostConfigSpec.GuestInfoPrefix). It has to
+	// live outside of the namespace it controls, otherwise nothing could discover it.
+	PrefixOverrideKey = GuestInfoPrefix + "extraConfig.prefix"
 )
 
 func defaultGuestInfoPrefix() string {
 	return GuestInfoPrefix + DefaultPrefix
 }
 
+// SetPrefix overrides DefaultPrefix for the calling process, so that its extraConfig/guestinfo
+// keys are namespaced separately from any other VIC version or tooling sharing the same VM. It
+// must be called before any Encode/Decode in the process and does not migrate keys already
+// written under the previous prefix.
+func SetPrefix(prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	if !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+
+	DefaultPrefix = prefix
+}
+
 const (
 	// Invalid value
 	Invalid = 1 << iota
@@ -104,6 +104,57 @@ type VMMetrics struct {
 	Interval int32
 }
 
+// VCHMetrics aggregates per-container VMMetrics into VCH-wide totals, suitable for
+// publishing to external consumers (e.g. vRealize Operations) that want capacity
+// planning data at the VCH level rather than per-container detail.
+type VCHMetrics struct {
+	// ContainerCount is the number of containers folded into this aggregate
+	ContainerCount int
+	// CPUUsageMhz is the sum of each container's CPU.Usage-weighted mhz consumption
+	CPUUsageMhz int64
+	// MemoryConsumed is the sum of each container's Memory.Consumed, in bytes
+	MemoryConsumed int64
+	// MemoryActive is the sum of each container's Memory.Active, in bytes
+	MemoryActive int64
+	// NetworkRxBytes is the sum of each container's received network bytes over the interval
+	NetworkRxBytes uint64
+	// NetworkTxBytes is the sum of each container's transmitted network bytes over the interval
+	NetworkTxBytes uint64
+	SampleTime     time.Time
+	// interval of collection in seconds
+	Interval int32
+}
+
+// AggregateVMMetrics folds a set of per-container VMMetrics into a single VCHMetrics
+// summary. SampleTime and Interval are taken from the last metric in the slice, on the
+// assumption that all containers on a VCH are sampled on the same collection cadence.
+func AggregateVMMetrics(metrics []*VMMetrics) *VCHMetrics {
+	agg := &VCHMetrics{}
+
+	for _, m := range metrics {
+		if m == nil {
+			continue
+		}
+
+		agg.ContainerCount++
+		for _, cpu := range m.CPU.CPUs {
+			agg.CPUUsageMhz += cpu.MhzUsage
+		}
+		agg.MemoryConsumed += m.Memory.Consumed
+		agg.MemoryActive += m.Memory.Active
+
+		for _, net := range m.Networks {
+			agg.NetworkRxBytes += net.Rx.Bytes
+			agg.NetworkTxBytes += net.Tx.Bytes
+		}
+
+		agg.SampleTime = m.SampleTime
+		agg.Interval = m.Interval
+	}
+
+	return agg
+}
+
 // VMCollector is the VM metrics collector
 type VMCollector struct {
 	perfMgr *performance.Manager
@@ -372,8 +423,11 @@ func (vmc *VMCollector) sample(op trace.Operation, mos []types.ManagedObjectRefe
 	}
 }
 
-// Subscribe to a vm metric subscription
-func (vmc *VMCollector) Subscribe(op trace.Operation, moref types.ManagedObjectReference, id string) (chan interface{}, error) {
+// Subscribe to a vm metric subscription. endpoints, if non-nil, maps a NIC's PCI
+// slot number (as a string, matching executor.NetworkEndpoint.ID) to the docker
+// network name it belongs to, so per-NIC network stats can be reported using
+// that name rather than the generic vSphere device name (e.g. "ethernet-0").
+func (vmc *VMCollector) Subscribe(op trace.Operation, moref types.ManagedObjectReference, id string, endpoints map[string]string) (chan interface{}, error) {
 	vmc.mu.Lock()
 	defer vmc.mu.Unlock()
 
@@ -384,7 +438,7 @@ func (vmc *VMCollector) Subscribe(op trace.Operation, moref types.ManagedObjectR
 	_, exists := vmc.subs[moref]
 	if !exists {
 		op.Debugf("Creating new subscription(%s)", id)
-		sub, err := newVMSubscription(op, vmc.session, moref, id)
+		sub, err := newVMSubscription(op, vmc.session, moref, id, endpoints)
 		if err != nil {
 			return nil, err
 		}
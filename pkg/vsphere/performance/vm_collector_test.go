@@ -0,0 +1,57 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateVMMetrics(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	m1 := &VMMetrics{
+		CPU:      CPUMetrics{CPUs: []CPUUsage{{ID: 0, MhzUsage: 100}, {ID: 1, MhzUsage: 50}}},
+		Memory:   MemoryMetrics{Consumed: 1024, Active: 512},
+		Networks: []Network{{Name: "eth0", Rx: NetworkUsage{Bytes: 10}, Tx: NetworkUsage{Bytes: 20}}},
+		Interval: sampleInterval,
+	}
+	m2 := &VMMetrics{
+		CPU:        CPUMetrics{CPUs: []CPUUsage{{ID: 0, MhzUsage: 25}}},
+		Memory:     MemoryMetrics{Consumed: 2048, Active: 1024},
+		Networks:   []Network{{Name: "eth0", Rx: NetworkUsage{Bytes: 30}, Tx: NetworkUsage{Bytes: 40}}},
+		SampleTime: now,
+		Interval:   sampleInterval,
+	}
+
+	agg := AggregateVMMetrics([]*VMMetrics{m1, m2, nil})
+
+	assert.Equal(t, 2, agg.ContainerCount)
+	assert.Equal(t, int64(175), agg.CPUUsageMhz)
+	assert.Equal(t, int64(3072), agg.MemoryConsumed)
+	assert.Equal(t, int64(1536), agg.MemoryActive)
+	assert.Equal(t, uint64(40), agg.NetworkRxBytes)
+	assert.Equal(t, uint64(60), agg.NetworkTxBytes)
+	assert.Equal(t, now, agg.SampleTime)
+	assert.Equal(t, sampleInterval, agg.Interval)
+}
+
+func TestAggregateVMMetricsEmpty(t *testing.T) {
+	agg := AggregateVMMetrics(nil)
+	assert.Equal(t, 0, agg.ContainerCount)
+	assert.Equal(t, int64(0), agg.CPUUsageMhz)
+}
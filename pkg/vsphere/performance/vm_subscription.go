@@ -38,6 +38,11 @@ type vmSubscription struct {
 	devices             object.VirtualDeviceList
 	deviceInstanceToKey map[string]string
 
+	// endpoints maps a NIC's PCI slot number (as a string) to the docker
+	// network name it belongs to - see executor.NetworkEndpoint.ID. May be nil,
+	// in which case network names fall back to the generic vSphere device name.
+	endpoints map[string]string
+
 	diskNames    []string // container's virtualDisk names
 	networkNames []string // container's network names
 }
@@ -63,13 +68,37 @@ func (sub *vmSubscription) DeviceName(instance string) string {
 	device := sub.devices.FindByKey(int32(key))
 	if device != nil {
 		// get the name
-		name = sub.devices.Name(device)
+		name = sub.networkName(device)
 		// populate map
 		sub.deviceInstanceToKey[instance] = name
 	}
 	return name
 }
 
+// networkName returns the docker network name associated with an ethernet
+// device, resolved via the endpoints map keyed by PCI slot number. Falls back
+// to the generic vSphere device name (e.g. "ethernet-0") when the device isn't
+// a NIC, has no PCI slot assigned yet, or isn't present in endpoints.
+func (sub *vmSubscription) networkName(device types.BaseVirtualDevice) string {
+	if slot, ok := pciSlotNumber(device); ok {
+		if name, exists := sub.endpoints[strconv.Itoa(int(slot))]; exists {
+			return name
+		}
+	}
+	return sub.devices.Name(device)
+}
+
+// pciSlotNumber returns the PCI bus slot number assigned to a virtual device,
+// mirroring the slot lookup vic uses when a NIC is added to a container (see
+// lib/spec.VirtualDeviceSlotNumber).
+func pciSlotNumber(device types.BaseVirtualDevice) (int32, bool) {
+	info, ok := device.GetVirtualDevice().SlotInfo.(*types.VirtualDevicePciBusSlotInfo)
+	if !ok {
+		return 0, false
+	}
+	return info.PciSlotNumber, true
+}
+
 // ID returns the subscription's id
 func (sub *vmSubscription) ID() string {
 	return sub.id
@@ -97,7 +126,7 @@ func (sub *vmSubscription) DeviceList(op trace.Operation) error {
 				sub.diskNames = append(sub.diskNames, fmt.Sprintf("%s%d:%d", "scsi", c.GetVirtualSCSIController().BusNumber, *disk.UnitNumber))
 			}
 		case object.DeviceTypeEthernet:
-			sub.networkNames = append(sub.networkNames, list.Name(list[i]))
+			sub.networkNames = append(sub.networkNames, sub.networkName(list[i]))
 		}
 	}
 
@@ -161,7 +190,7 @@ func (sub *vmSubscription) Evict(ch chan interface{}) {
 }
 
 // newVMSubscription is a helper func to convert the interface to a subscription
-func newVMSubscription(op trace.Operation, session *session.Session, moref types.ManagedObjectReference, id string) (*vmSubscription, error) {
+func newVMSubscription(op trace.Operation, session *session.Session, moref types.ManagedObjectReference, id string, endpoints map[string]string) (*vmSubscription, error) {
 	// ensure we have a valid moRef..we won't worry about inspecting the details
 	if moref.String() == "" {
 		err := fmt.Errorf("no vm associated with new stats subscription: %s", id)
@@ -172,6 +201,7 @@ func newVMSubscription(op trace.Operation, session *session.Session, moref types
 	sub := &vmSubscription{
 		vm:                  object.NewVirtualMachine(session.Vim25(), moref),
 		deviceInstanceToKey: make(map[string]string),
+		endpoints:           endpoints,
 	}
 
 	err := sub.DeviceList(op)
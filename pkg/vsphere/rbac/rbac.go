@@ -216,6 +216,33 @@ func (am *AuthzManager) ReadPermsOnDC(ctx context.Context, dcRef types.ManagedOb
 	return false, nil
 }
 
+// MissingPrivileges checks the current session's user for each of privIDs against entity, and
+// returns the subset that are not held. An empty result means all of privIDs are held.
+func (am *AuthzManager) MissingPrivileges(ctx context.Context, entity types.ManagedObjectReference, privIDs []string) ([]string, error) {
+	req := types.HasPrivilegeOnEntity{
+		This:   am.authzManager.Reference(),
+		Entity: entity,
+		PrivId: privIDs,
+	}
+
+	res, err := methods.HasPrivilegeOnEntity(ctx, am.client, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for i, held := range res.Returnval {
+		if i >= len(privIDs) {
+			break
+		}
+		if !held {
+			missing = append(missing, privIDs[i])
+		}
+	}
+
+	return missing, nil
+}
+
 func (am *AuthzManager) PrincipalHasRole(ctx context.Context, roleName string) (bool, error) {
 	// Build expected representation of the ops-user
 	principal := strings.ToLower(am.Principal)
@@ -0,0 +1,199 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// sessionCachePath is where the encrypted vSphere session cookies are persisted across a
+// portlayer process restart (e.g. during an upgrade), so the restarted process can resume
+// the existing vCenter session instead of paying for a full login and inventory walk.
+// Variable rather than const so tests can point it at a scratch location.
+var sessionCachePath = "/var/run/vic/session.cache"
+
+// sessionCacheKeyPath is where the symmetric key that encrypts sessionCachePath is kept.
+// It's generated the first time a session is cached and reused after that. Keeping it in a
+// separate, independently-permissioned file - rather than deriving it from data that's
+// already sitting next to the cache file (e.g. the vCenter thumbprint and username, which
+// anyone able to read sessionCachePath can also read) - means a copy of sessionCachePath
+// alone is not enough to decrypt it, and the key can be rotated (delete this file) without
+// touching vCenter credentials. Variable rather than const so tests can point it at a
+// scratch location.
+var sessionCacheKeyPath = "/var/run/vic/session.key"
+
+// aesKeySize is the key size, in bytes, generated for cacheKey - AES-256.
+const aesKeySize = 32
+
+// cachedCookie is the subset of http.Cookie fields needed to replay a cookie against the
+// SDK endpoint it was issued for.
+type cachedCookie struct {
+	Name, Value, Domain, Path string
+}
+
+// cacheKey returns the symmetric encryption key for the session cache, generating and
+// persisting a new random one at sessionCacheKeyPath on first use.
+func cacheKey() ([]byte, error) {
+	key, err := ioutil.ReadFile(sessionCacheKeyPath)
+	if err == nil && len(key) == aesKeySize {
+		return key, nil
+	}
+
+	key = make([]byte, aesKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sessionCacheKeyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(sessionCacheKeyPath, key, 0600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// SaveSessionCache encrypts the session cookies vSphere issued for s's SDK endpoint and
+// writes them to sessionCachePath. Failure to save just means the next restart falls back
+// to a full login, so callers should log rather than fail hard on error.
+func SaveSessionCache(s *Session) error {
+	jar := s.Vim25().Jar
+	if jar == nil {
+		return fmt.Errorf("session has no cookie jar to cache")
+	}
+
+	u := s.Vim25().URL()
+
+	cookies := jar.Cookies(u)
+	if len(cookies) == 0 {
+		return fmt.Errorf("session has no cookies to cache")
+	}
+
+	cached := make([]cachedCookie, len(cookies))
+	for i, c := range cookies {
+		cached[i] = cachedCookie{Name: c.Name, Value: c.Value, Domain: u.Hostname(), Path: c.Path}
+	}
+
+	plaintext, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	key, err := cacheKey()
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := encrypt(plaintext, key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sessionCachePath), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(sessionCachePath, ciphertext, 0600)
+}
+
+// LoadSessionCache decrypts and returns the cookies previously saved by SaveSessionCache for
+// s, or nil if there's no usable cache. A missing, corrupt, or undecryptable file is treated
+// as a cache miss rather than an error - the caller's fallback is simply to log in fresh.
+func LoadSessionCache(s *Session) []*http.Cookie {
+	ciphertext, err := ioutil.ReadFile(sessionCachePath)
+	if err != nil {
+		return nil
+	}
+
+	key, err := cacheKey()
+	if err != nil {
+		return nil
+	}
+
+	plaintext, err := decrypt(ciphertext, key)
+	if err != nil {
+		return nil
+	}
+
+	var cached []cachedCookie
+	if err := json.Unmarshal(plaintext, &cached); err != nil {
+		return nil
+	}
+
+	cookies := make([]*http.Cookie, len(cached))
+	for i, c := range cached {
+		cookies[i] = &http.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path}
+	}
+
+	return cookies
+}
+
+// ClearSessionCache removes any persisted session cache, e.g. after an explicit logout, so a
+// subsequent restart doesn't try to resume a session that's been deliberately ended.
+func ClearSessionCache() error {
+	err := os.Remove(sessionCachePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func encrypt(plaintext, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(ciphertext, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("session cache ciphertext is truncated")
+	}
+
+	nonce, data := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, data, nil)
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vic-session-cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := sessionCacheKeyPath
+	sessionCacheKeyPath = dir + "/session.key"
+	defer func() { sessionCacheKeyPath = saved }()
+
+	key, err := cacheKey()
+	if err != nil {
+		t.Fatalf("cacheKey failed: %s", err)
+	}
+	plaintext := []byte(`[{"Name":"vmware_soap_session","Value":"deadbeef"}]`)
+
+	ciphertext, err := encrypt(plaintext, key)
+	if err != nil {
+		t.Fatalf("encrypt failed: %s", err)
+	}
+
+	decrypted, err := decrypt(ciphertext, key)
+	if err != nil {
+		t.Fatalf("decrypt failed: %s", err)
+	}
+
+	if string(decrypted) != string(plaintext) {
+		t.Errorf("decrypted plaintext does not match original: %s", decrypted)
+	}
+}
+
+func TestDecryptWithWrongKeyFails(t *testing.T) {
+	right := bytes.Repeat([]byte{0x01}, aesKeySize)
+	wrong := bytes.Repeat([]byte{0x02}, aesKeySize)
+
+	ciphertext, err := encrypt([]byte("secret"), right)
+	if err != nil {
+		t.Fatalf("encrypt failed: %s", err)
+	}
+
+	if _, err := decrypt(ciphertext, wrong); err == nil {
+		t.Error("expected decrypt with the wrong key to fail")
+	}
+}
+
+func TestLoadSessionCacheMissingOrCorruptFileIsCacheMiss(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vic-session-cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := sessionCachePath
+	sessionCachePath = dir + "/session.cache"
+	defer func() { sessionCachePath = saved }()
+
+	savedKey := sessionCacheKeyPath
+	sessionCacheKeyPath = dir + "/session.key"
+	defer func() { sessionCacheKeyPath = savedKey }()
+
+	s := &Session{Config: &Config{Thumbprint: "aa:bb:cc"}}
+	s.User = url.UserPassword("root", "password")
+
+	// no cache file at all
+	if cookies := LoadSessionCache(s); cookies != nil {
+		t.Errorf("expected no cookies with no cache file present, got %v", cookies)
+	}
+
+	// a cache file that isn't even valid ciphertext for this key
+	if err := ioutil.WriteFile(sessionCachePath, []byte("not a valid cache file"), 0600); err != nil {
+		t.Fatalf("failed to write corrupt cache file: %s", err)
+	}
+
+	if cookies := LoadSessionCache(s); cookies != nil {
+		t.Errorf("expected a corrupt cache file to be treated as a cache miss, got %v", cookies)
+	}
+}
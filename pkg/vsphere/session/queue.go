@@ -0,0 +1,92 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// queuedOperation is an idempotent mutating operation queued while vCenter is
+// unreachable, to be replayed once the session reconnects.
+type queuedOperation struct {
+	description string
+	queuedAt    time.Time
+	run         func(trace.Operation) error
+}
+
+// ReconnectQueue buffers idempotent mutating operations while the connection to
+// vCenter is down, replaying them in order once WithConnectionLossQueue observes
+// a successful call again. It's bounded on both size and age: a caller may have
+// already given up and moved on by the time connectivity returns, so entries
+// older than ttl are dropped rather than replayed.
+type ReconnectQueue struct {
+	mu  sync.Mutex
+	max int
+	ttl time.Duration
+	ops *list.List // of *queuedOperation
+}
+
+// NewReconnectQueue creates an empty queue holding at most max operations, each
+// discarded if not replayed within ttl of being queued.
+func NewReconnectQueue(max int, ttl time.Duration) *ReconnectQueue {
+	return &ReconnectQueue{max: max, ttl: ttl, ops: list.New()}
+}
+
+// Enqueue appends run, labeled by description for logging and health
+// reporting, if the queue has room.
+func (q *ReconnectQueue) Enqueue(description string, run func(trace.Operation) error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.ops.Len() >= q.max {
+		return fmt.Errorf("reconnect queue is full (%d operations); vCenter has been unreachable too long to keep queuing", q.max)
+	}
+
+	q.ops.PushBack(&queuedOperation{description: description, queuedAt: time.Now(), run: run})
+	return nil
+}
+
+// Depth returns the number of operations currently queued.
+func (q *ReconnectQueue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.ops.Len()
+}
+
+// Replay runs every queued operation that hasn't exceeded the queue's ttl, in
+// the order it was queued, dropping the rest without running them.
+func (q *ReconnectQueue) Replay(op trace.Operation) {
+	q.mu.Lock()
+	pending := q.ops
+	q.ops = list.New()
+	q.mu.Unlock()
+
+	for e := pending.Front(); e != nil; e = e.Next() {
+		qo := e.Value.(*queuedOperation)
+		if time.Since(qo.queuedAt) > q.ttl {
+			op.Warnf("dropping queued operation %q: exceeded reconnect queue TTL of %s", qo.description, q.ttl)
+			continue
+		}
+
+		if err := qo.run(op); err != nil {
+			op.Errorf("replaying queued operation %q failed: %s", qo.description, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+func TestReconnectQueueReplaysInOrder(t *testing.T) {
+	q := NewReconnectQueue(10, time.Hour)
+
+	var replayed []int
+	for i := 0; i < 3; i++ {
+		i := i
+		if err := q.Enqueue("op", func(trace.Operation) error {
+			replayed = append(replayed, i)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if q.Depth() != 3 {
+		t.Fatalf("expected depth 3, got %d", q.Depth())
+	}
+
+	q.Replay(trace.NewOperation(context.Background(), "test"))
+
+	if q.Depth() != 0 {
+		t.Errorf("expected queue to be drained after replay, depth is %d", q.Depth())
+	}
+
+	if len(replayed) != 3 || replayed[0] != 0 || replayed[1] != 1 || replayed[2] != 2 {
+		t.Errorf("expected operations replayed in order [0 1 2], got %v", replayed)
+	}
+}
+
+func TestReconnectQueueRejectsWhenFull(t *testing.T) {
+	q := NewReconnectQueue(1, time.Hour)
+
+	if err := q.Enqueue("first", func(trace.Operation) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.Enqueue("second", func(trace.Operation) error { return nil }); err == nil {
+		t.Error("expected an error when the queue is full, got nil")
+	}
+}
+
+func TestReconnectQueueDropsExpiredEntries(t *testing.T) {
+	q := NewReconnectQueue(10, time.Millisecond)
+
+	replayed := false
+	if err := q.Enqueue("stale", func(trace.Operation) error {
+		replayed = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	q.Replay(trace.NewOperation(context.Background(), "test"))
+
+	if replayed {
+		t.Error("expected an operation older than the queue's ttl to be dropped, not replayed")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// maxReloginAttempts bounds how many times WithLoginRetry will re-authenticate
+// and retry op before giving up and surfacing the last error - a vCenter that's
+// permanently unreachable should fail the call rather than retry forever.
+const maxReloginAttempts = 3
+
+// WithLoginRetry runs op and returns its result. If op fails because the
+// session has lost its authentication - vCenter was restarted, or the session
+// idled out past the keepalive - it transparently re-logs in and retries op
+// before surfacing the error to the caller. Concurrent callers that all
+// observe the same expired session serialize on the relogin rather than each
+// attempting their own.
+func (s *Session) WithLoginRetry(op trace.Operation, f func() error) error {
+	var err error
+	for attempt := 0; attempt < maxReloginAttempts; attempt++ {
+		if err = f(); err == nil || !isNotAuthenticated(err) {
+			return err
+		}
+
+		if rerr := s.relogin(op); rerr != nil {
+			op.Errorf("session re-authentication failed: %s", rerr)
+			return err
+		}
+	}
+
+	return err
+}
+
+// WithConnectionLossQueue runs op via WithLoginRetry. If it still fails because
+// vCenter is entirely unreachable - not merely an expired session, which
+// WithLoginRetry already recovers from - and a ReconnectQueue is configured, op
+// is queued for replay under description instead of failing the caller, and nil
+// is returned. Callers must only use this for operations that are safe to
+// re-run blind against vCenter after the fact, since the original caller won't
+// see the eventual result.
+func (s *Session) WithConnectionLossQueue(op trace.Operation, description string, f func() error) error {
+	err := s.WithLoginRetry(op, f)
+	if err == nil && s.ReconnectQueue != nil && s.ReconnectQueue.Depth() > 0 {
+		// a call got through again - run what was queued while vCenter was unreachable
+		s.ReconnectQueue.Replay(op)
+	}
+	if err == nil || s.ReconnectQueue == nil || !IsConnectionError(err) {
+		return err
+	}
+
+	if qerr := s.ReconnectQueue.Enqueue(description, func(trace.Operation) error { return f() }); qerr != nil {
+		op.Errorf("unable to queue %q for replay after vCenter connection loss: %s", description, qerr)
+		return err
+	}
+
+	op.Warnf("vCenter is unreachable, queued %q for replay once it recovers", description)
+	return nil
+}
+
+// IsSessionExpired reports whether err is a vSphere NotAuthenticated fault -
+// the session lost its authentication, e.g. because vCenter was restarted or
+// the session idled out past the keepalive.
+func IsSessionExpired(err error) bool {
+	return isNotAuthenticated(err)
+}
+
+// relogin re-authenticates s against vCenter, serializing concurrent callers
+// so only one of them actually performs the login while the rest wait for it
+// to finish rather than racing to log in independently.
+func (s *Session) relogin(op trace.Operation) error {
+	s.reloginMu.Lock()
+	defer s.reloginMu.Unlock()
+
+	op.Warnf("session appears to have expired, attempting to re-authenticate")
+
+	if err := s.Client.Login(op, s.User); err != nil {
+		return err
+	}
+
+	op.Infof("session re-authenticated")
+
+	if s.ReconnectQueue != nil {
+		s.ReconnectQueue.Replay(op)
+	}
+
+	return nil
+}
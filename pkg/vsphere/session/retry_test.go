@@ -0,0 +1,178 @@
+// Copyright 2018 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	"github.com/vmware/vic/pkg/trace"
+)
+
+// datacenterName fetches the Datacenter's name via the property collector -
+// an authenticated call that fails with NotAuthenticated once the session
+// has been logged out from under it.
+func datacenterName(ctx context.Context, sess *Session) error {
+	var dc mo.Datacenter
+	pc := property.DefaultCollector(sess.Client.Client)
+	return pc.RetrieveOne(ctx, sess.Datacenter.Reference(), []string{"name"}, &dc)
+}
+
+// retrySession connects to the simulator behind server and populates the
+// session's inventory. Create is not used here because it takes its target and
+// credentials from guestinfo rather than from the Config.
+func retrySession(ctx context.Context, server *simulator.Server) (*Session, error) {
+	config := &Config{
+		Service:        server.URL.String(),
+		User:           server.URL.User,
+		Insecure:       true,
+		Thumbprint:     server.CertificateInfo().ThumbprintSHA1,
+		DatacenterPath: "/DC0",
+		ClusterPath:    "/DC0/host/DC0_C0",
+		DatastorePath:  "/DC0/datastore/LocalDS_0",
+		PoolPath:       "/DC0/host/DC0_C0/Resources",
+	}
+
+	sess, err := NewSession(config).Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sess.Populate(ctx)
+}
+
+func TestWithLoginRetryReauthenticatesExpiredSession(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	sess, err := retrySession(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Logout(ctx)
+
+	// Invalidate the session out from under ourselves, as if vCenter had
+	// restarted or the session had idled out past the keepalive.
+	if err := sess.Client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	op := trace.NewOperation(ctx, "test")
+
+	attempts := 0
+	err = sess.WithLoginRetry(op, func() error {
+		attempts++
+		return datacenterName(ctx, sess)
+	})
+	if err != nil {
+		t.Fatalf("expected transparent relogin and retry to succeed, got: %s", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("expected exactly one retry after relogin (2 attempts), got %d", attempts)
+	}
+}
+
+func TestWithLoginRetrySurfacesErrorWhenReloginFails(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	sess, err := retrySession(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sess.Client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make relogin fail: the simulator rejects a login with an empty password.
+	sess.User = url.UserPassword("root", "")
+
+	op := trace.NewOperation(ctx, "test")
+	err = sess.WithLoginRetry(op, func() error {
+		return datacenterName(ctx, sess)
+	})
+	if err == nil {
+		t.Fatal("expected an error surfaced when relogin fails, got nil")
+	}
+
+	if !IsSessionExpired(err) {
+		t.Errorf("expected the original NotAuthenticated fault to be surfaced, got: %s", err)
+	}
+}
+
+func TestWithConnectionLossQueueQueuesUnreachableVCenter(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+
+	ctx := context.Background()
+	sess, err := retrySession(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess.EnableReconnectQueue(10, time.Hour)
+
+	// Simulate vCenter becoming entirely unreachable, as opposed to the
+	// session merely expiring.
+	server.Close()
+
+	op := trace.NewOperation(ctx, "test")
+	attempts := 0
+	err = sess.WithConnectionLossQueue(op, "queue test op", func() error {
+		attempts++
+		return datacenterName(ctx, sess)
+	})
+	if err != nil {
+		t.Fatalf("expected the operation to be queued rather than failed, got: %s", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("expected exactly one attempt before queuing, got %d", attempts)
+	}
+
+	if sess.ReconnectQueue.Depth() != 1 {
+		t.Errorf("expected the operation to be queued, depth is %d", sess.ReconnectQueue.Depth())
+	}
+}
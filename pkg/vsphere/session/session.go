@@ -27,11 +27,13 @@ package session
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -99,6 +101,22 @@ type Session struct {
 	Finder *find.Finder
 
 	DRSEnabled *bool
+
+	// reloginMu serializes re-authentication triggered by WithLoginRetry, so
+	// that concurrent callers who all observe an expired session don't each
+	// try to log back in - one relogin runs while the rest wait for it.
+	reloginMu sync.Mutex
+
+	// ReconnectQueue, if set, lets WithConnectionLossQueue buffer idempotent
+	// mutating operations while vCenter is unreachable instead of failing them
+	// outright. It's nil - disabled - unless EnableReconnectQueue is called.
+	ReconnectQueue *ReconnectQueue
+}
+
+// EnableReconnectQueue turns on operation queuing across connection loss: up
+// to max operations, each dropped if not replayed within ttl of being queued.
+func (s *Session) EnableReconnectQueue(max int, ttl time.Duration) {
+	s.ReconnectQueue = NewReconnectQueue(max, ttl)
 }
 
 // RoundTripFunc alias
@@ -284,17 +302,35 @@ func (s *Session) Connect(ctx context.Context) (*Session, error) {
 		SessionManager: session.NewManager(vimClient),
 	}
 
-	if s.CloneTicket != "" {
-		// clone a user session if we have a ticket
-		err = s.SessionManager.CloneSession(op, s.CloneTicket)
-	} else {
-		// otherwise login to create a new one
-		err = login(op)
+	resumed := false
+	if cookies := LoadSessionCache(s); len(cookies) > 0 {
+		if jar := s.Vim25().Jar; jar != nil {
+			jar.SetCookies(s.Vim25().URL(), cookies)
+
+			if user, uerr := s.SessionManager.UserSession(op); uerr == nil && user != nil {
+				op.Debug("Resumed vSphere session from local cache, skipping login")
+				resumed = true
+			}
+		}
 	}
-	if err != nil {
-		return nil, UserPassLoginError{
-			Host: soapURL.Host,
-			Err:  err,
+
+	if !resumed {
+		if s.CloneTicket != "" {
+			// clone a user session if we have a ticket
+			err = s.SessionManager.CloneSession(op, s.CloneTicket)
+		} else {
+			// otherwise login to create a new one
+			err = login(op)
+		}
+		if err != nil {
+			return nil, UserPassLoginError{
+				Host: soapURL.Host,
+				Err:  err,
+			}
+		}
+
+		if cerr := SaveSessionCache(s); cerr != nil {
+			op.Debugf("unable to cache vSphere session for fast restart: %s", cerr)
 		}
 	}
 
@@ -450,3 +486,21 @@ func isNotAuthenticated(err error) bool {
 	}
 	return false
 }
+
+// IsConnectionError reports whether err looks like vCenter is entirely
+// unreachable - a transport-level failure rather than an expired session,
+// which soap.IsSoapFault (and so isNotAuthenticated) can't detect since no
+// SOAP response was ever received.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	if uerr, ok := err.(*url.Error); ok {
+		_, ok := uerr.Err.(net.Error)
+		return ok
+	}
+	return false
+}
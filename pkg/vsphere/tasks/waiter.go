@@ -330,6 +330,13 @@ func IsAlreadyPoweredOffError(err error) bool {
 	return match && invalidState.ExistingState == types.VirtualMachinePowerStatePoweredOff
 }
 
+// IsAlreadyPoweredOnError verifies that the error is an InvalidPowerState error and
+// returns true if the existing state from the error is powered on
+func IsAlreadyPoweredOnError(err error) bool {
+	invalidState, match := isInvalidPowerStateError(err)
+	return match && invalidState.ExistingState == types.VirtualMachinePowerStatePoweredOn
+}
+
 // IsInvalidStateError is an error certifier function for errors coming back from vsphere. It checks for an InvalidStateFault
 func IsInvalidStateError(err error) bool {
 	if soap.IsVimFault(err) {
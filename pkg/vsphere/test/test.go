@@ -125,6 +125,10 @@ func PickRandomHost(ctx context.Context, session *session.Session, t *testing.T)
 		t.Errorf("ERROR: %s", err)
 		t.SkipNow()
 	}
+	if len(hosts) == 0 {
+		t.Errorf("ERROR: no eligible hosts attached to datastore %q in cluster %q", session.Datastore.Name(), session.Cluster.Name())
+		t.SkipNow()
+	}
 	return hosts[rand.Intn(len(hosts))]
 }
 
@@ -403,6 +403,21 @@ func (vm *VirtualMachine) UUID(ctx context.Context) (string, error) {
 	return mvm.Summary.Config.Uuid, nil
 }
 
+// AcquireTicket requests a one-time console access ticket of the given kind (e.g. "webmks",
+// "mks", "vmrc") for this VM, for handing to a console client without exposing vSphere
+// credentials to it.
+func (vm *VirtualMachine) AcquireTicket(ctx context.Context, kind string) (*types.VirtualMachineTicket, error) {
+	op := trace.FromContext(ctx, "AcquireTicket")
+
+	ticket, err := vm.VirtualMachine.AcquireTicket(op, kind)
+	if err != nil {
+		op.Errorf("Unable to acquire %s ticket: %s", kind, err)
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
 // DeleteExceptDisks destroys the VM after detaching all virtual disks
 func (vm *VirtualMachine) DeleteExceptDisks(ctx context.Context) (*types.TaskInfo, error) {
 
@@ -631,21 +646,32 @@ func (vm *VirtualMachine) fixVM(op trace.Operation) error {
 		return err
 	}
 
-	task, err := vm.registerVM(op, mvm.Summary.Config.VmPathName, name, mvm.ParentVApp, mvm.ResourcePool, mvm.Summary.Runtime.Host, vm.Session.VCHFolder)
-	if err != nil {
+	if err := vm.Register(op, mvm.Summary.Config.VmPathName, name, mvm.ParentVApp, mvm.ResourcePool, mvm.Summary.Runtime.Host, vm.Session.VCHFolder); err != nil {
 		op.Errorf("Unable to register VM %q back: %s", name, err)
 		return err
 	}
+
+	return nil
+}
+
+// Register re-registers an unregistered VM at path - one this VCH previously
+// unregistered without deleting its files, whether to recover it from an invalid
+// state (see fixVM) or to restore a container out of the trash. It resets vm's
+// own object reference to the newly registered VM, since re-registering changes it.
+func (vm *VirtualMachine) Register(op trace.Operation, path, name string, vapp, pool, host *types.ManagedObjectReference, vmfolder *object.Folder) error {
+	task, err := vm.registerVM(op, path, name, vapp, pool, host, vmfolder)
+	if err != nil {
+		return err
+	}
+
 	info, err := task.WaitForResult(op, nil)
 	if err != nil {
 		return err
 	}
-	// re-register vm will change vm reference, so reset the object reference here
 	if info.Error != nil {
 		return errors.New(info.Error.LocalizedMessage)
 	}
 
-	// set new registered vm attribute back
 	newRef := info.Result.(types.ManagedObjectReference)
 	common := object.NewCommon(vm.Vim25(), newRef)
 	common.InventoryPath = vm.InventoryPath
@@ -1044,3 +1070,9 @@ func (vm *VirtualMachine) InCluster(op trace.Operation) bool {
 func (vm *VirtualMachine) IsAlreadyPoweredOffError(err error) bool {
 	return tasks.IsAlreadyPoweredOffError(err)
 }
+
+// IsAlreadyPoweredOnError is an accessor method because of the number of times package name and
+// variable name tend to collide for VMs.
+func (vm *VirtualMachine) IsAlreadyPoweredOnError(err error) bool {
+	return tasks.IsAlreadyPoweredOnError(err)
+}